@@ -0,0 +1,521 @@
+package nanogui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AccessibleRole is the semantic role of a widget reported to assistive technologies
+type AccessibleRole string
+
+const (
+	RoleApplication  AccessibleRole = "application"
+	RoleGroup        AccessibleRole = "group"
+	RoleDialog       AccessibleRole = "dialog"
+	RoleMenu         AccessibleRole = "menu"
+	RoleLabel        AccessibleRole = "label"
+	RoleButton       AccessibleRole = "button"
+	RoleToggleButton AccessibleRole = "toggle button"
+	RoleRadioButton  AccessibleRole = "radio button"
+	RoleCheckBox     AccessibleRole = "checkbox"
+	RoleComboBox     AccessibleRole = "combobox"
+	RoleSlider       AccessibleRole = "slider"
+	RoleProgressBar  AccessibleRole = "progressbar"
+	RoleText         AccessibleRole = "editable text"
+	RoleImage        AccessibleRole = "image"
+	RoleScrollPane   AccessibleRole = "scroll pane"
+	RoleColorChooser AccessibleRole = "color chooser"
+	RoleChart        AccessibleRole = "chart"
+)
+
+// AccessibleState is a set of state flags of an accessible node
+type AccessibleState int
+
+const (
+	StateFocusable AccessibleState = 1 << iota
+	StateFocused
+	StateDisabled
+	StateInvisible
+	StateChecked
+	StatePressed
+	StateEditable
+	StateReadOnly
+	StateExpanded
+	StateHasPopup
+	StateModal
+	StateInvalid
+	StateMultiLine
+	StateProtected
+)
+
+var accessibleStateNames = []string{
+	"focusable", "focused", "disabled", "invisible", "checked", "pressed",
+	"editable", "readonly", "expanded", "haspopup", "modal", "invalid", "multiline", "protected",
+}
+
+func (s AccessibleState) String() string {
+	var names []string
+	for i, name := range accessibleStateNames {
+		if s&(1<<uint(i)) != 0 {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// AccessibleNode is a snapshot of the semantic information of a widget
+type AccessibleNode struct {
+	ID       int
+	Widget   Widget
+	Role     AccessibleRole
+	Name     string
+	Value    string
+	State    AccessibleState
+	Bounds   [4]int // absolute x, y, width, height
+	Children []*AccessibleNode
+
+	// range of slider like widgets (valid if HasRange is true)
+	HasRange                    bool
+	MinValue, MaxValue, Current float64
+}
+
+// Has() returns whether the node has all of the given state flags
+func (n *AccessibleNode) Has(state AccessibleState) bool {
+	return n.State&state == state
+}
+
+func (n *AccessibleNode) String() string {
+	return fmt.Sprintf("%s %q value=%q [%s]", n.Role, n.Name, n.Value, n.State)
+}
+
+// AccessibilityDescriber can be implemented by custom widgets to report their own role, name, value and state
+//
+// DescribeAccessibility() receives a node filled with the default description and can modify it.
+type AccessibilityDescriber interface {
+	DescribeAccessibility(node *AccessibleNode)
+}
+
+// DescribeWidget() returns the semantic description of a widget without its children
+func DescribeWidget(widget Widget) *AccessibleNode {
+	node := &AccessibleNode{
+		Widget: widget,
+		Role:   RoleGroup,
+	}
+	x, y := widget.AbsolutePosition()
+	w, h := widget.Size()
+	node.Bounds = [4]int{x, y, w, h}
+	if widget.TabStop() {
+		node.State |= StateFocusable
+	}
+	if widget.Focused() && !hasFocusedChild(widget) {
+		node.State |= StateFocused
+	}
+	if !widget.Enabled() {
+		node.State |= StateDisabled
+	}
+	if !widget.Visible() {
+		node.State |= StateInvisible
+	}
+
+	switch w := widget.(type) {
+	case *Screen:
+		node.Role = RoleApplication
+		node.Name = w.Caption()
+	case *Popup:
+		node.Role = RoleMenu
+		if button := findPopupButton(w); button != nil {
+			node.Name = button.Caption()
+		}
+	case *Window:
+		node.Role = RoleDialog
+		node.Name = w.Title()
+		if w.Modal() {
+			node.State |= StateModal
+		}
+	case *Label:
+		node.Role = RoleLabel
+		node.Name = w.Caption()
+	case *RichLabel:
+		node.Role = RoleLabel
+		node.Name = w.PlainText()
+	case *ColorPicker:
+		node.Role = RoleColorChooser
+		node.Name = accessibleLabel(widget)
+		node.Value = colorString(w.Color())
+		describePopupButton(node, &w.PopupButton)
+	case *ComboBox:
+		node.Role = RoleComboBox
+		node.Name = accessibleLabel(widget)
+		node.Value = w.Caption()
+		describePopupButton(node, &w.PopupButton)
+	case *PopupButton:
+		node.Role = RoleButton
+		node.Name = w.Caption()
+		describePopupButton(node, w)
+	case *Button:
+		node.Name = w.Caption()
+		switch {
+		case w.Flags()&RadioButtonType != 0:
+			node.Role = RoleRadioButton
+			if w.Pushed() {
+				node.State |= StateChecked
+			}
+		case w.Flags()&ToggleButtonType != 0:
+			node.Role = RoleToggleButton
+			if w.Pushed() {
+				node.State |= StatePressed
+			}
+		default:
+			node.Role = RoleButton
+		}
+	case *CheckBox:
+		node.Role = RoleCheckBox
+		node.Name = w.Caption()
+		if w.Checked() {
+			node.State |= StateChecked
+		}
+	case *Slider:
+		node.Role = RoleSlider
+		node.Name = accessibleLabel(widget)
+		setAccessibleRange(node, 0, 1, float64(w.Value()))
+	case *ProgressBar:
+		node.Role = RoleProgressBar
+		node.Name = accessibleLabel(widget)
+		setAccessibleRange(node, 0, 1, float64(w.Value()))
+	case *IntBox:
+		describeTextBox(node, &w.TextBox)
+		if w.hasRange {
+			min, max := w.Range()
+			setAccessibleRange(node, float64(min), float64(max), float64(w.Value()))
+		}
+	case *FloatBox:
+		describeTextBox(node, &w.TextBox)
+		if w.hasRange {
+			min, max := w.Range()
+			setAccessibleRange(node, min, max, w.Value())
+		}
+	case *TextBox:
+		describeTextBox(node, w)
+	case *TextArea:
+		node.Role = RoleText
+		node.Name = accessibleLabel(widget)
+		node.State |= StateMultiLine
+		if w.Editable() {
+			node.State |= StateEditable
+		} else {
+			node.State |= StateReadOnly
+		}
+		node.Value = string(w.editingText())
+	case *ColorWheel:
+		node.Role = RoleColorChooser
+		node.Name = accessibleLabel(widget)
+		node.Value = colorString(w.Color())
+	case *ImageView, *ImagePanel:
+		node.Role = RoleImage
+	case *VScrollPanel:
+		node.Role = RoleScrollPane
+	case *Graph:
+		node.Role = RoleChart
+		node.Name = w.Caption()
+		node.Value = w.Header()
+	}
+	if node.Name == "" {
+		node.Name = widget.Tooltip()
+	}
+	if describer, ok := widget.(AccessibilityDescriber); ok {
+		describer.DescribeAccessibility(node)
+	}
+	return node
+}
+
+func describePopupButton(node *AccessibleNode, button *PopupButton) {
+	node.State |= StateHasPopup
+	if button.Pushed() {
+		node.State |= StateExpanded
+	}
+}
+
+func describeTextBox(node *AccessibleNode, textBox *TextBox) {
+	node.Role = RoleText
+	node.Name = accessibleLabel(textBox)
+	node.Value = textBox.Value()
+	if textBox.Editable() {
+		node.State |= StateEditable
+		if textBox.Focused() {
+			node.Value = string(textBox.valueTemp)
+		}
+	} else {
+		node.State |= StateReadOnly
+	}
+	if textBox.ValidationError() != nil {
+		node.State |= StateInvalid
+	}
+	if textBox.Password() {
+		node.State |= StateProtected
+		node.Value = string(textBox.displayText([]rune(node.Value)))
+	}
+}
+
+func setAccessibleRange(node *AccessibleNode, min, max, current float64) {
+	node.HasRange = true
+	node.MinValue = min
+	node.MaxValue = max
+	node.Current = current
+	if max != min {
+		node.Value = fmt.Sprintf("%.0f%%", (current-min)/(max-min)*100)
+	} else {
+		node.Value = "100%"
+	}
+}
+
+// accessibleLabel() returns the caption of the Label placed just before the widget (the usual form layout)
+func accessibleLabel(widget Widget) string {
+	parent := widget.Parent()
+	if parent == nil {
+		return ""
+	}
+	var previous Widget
+	for _, child := range parent.Children() {
+		if child == widget {
+			break
+		}
+		previous = child
+	}
+	if label, ok := previous.(*Label); ok {
+		return label.Caption()
+	}
+	return ""
+}
+
+func findPopupButton(popup *Popup) *PopupButton {
+	window := popup.ParentWindow()
+	if window == nil {
+		return nil
+	}
+	var found *PopupButton
+	var search func(w Widget)
+	search = func(w Widget) {
+		for _, child := range w.Children() {
+			if found != nil {
+				return
+			}
+			var button *PopupButton
+			switch b := child.(type) {
+			case *PopupButton:
+				button = b
+			case *ComboBox:
+				button = &b.PopupButton
+			case *ColorPicker:
+				button = &b.PopupButton
+			}
+			if button != nil && button.popup == popup {
+				found = button
+				return
+			}
+			search(child)
+		}
+	}
+	search(window)
+	return found
+}
+
+func hasFocusedChild(widget Widget) bool {
+	for _, child := range widget.Children() {
+		if child.Focused() {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessibilityEventType is the kind of change reported to an AccessibilityBridge
+type AccessibilityEventType int
+
+const (
+	AccessibilityNodeAdded AccessibilityEventType = iota
+	AccessibilityNodeRemoved
+	AccessibilityFocusChanged
+	AccessibilityNameChanged
+	AccessibilityValueChanged
+	AccessibilityStateChanged
+	AccessibilityVisibilityChanged
+)
+
+func (t AccessibilityEventType) String() string {
+	switch t {
+	case AccessibilityNodeAdded:
+		return "NodeAdded"
+	case AccessibilityNodeRemoved:
+		return "NodeRemoved"
+	case AccessibilityFocusChanged:
+		return "FocusChanged"
+	case AccessibilityNameChanged:
+		return "NameChanged"
+	case AccessibilityValueChanged:
+		return "ValueChanged"
+	case AccessibilityStateChanged:
+		return "StateChanged"
+	case AccessibilityVisibilityChanged:
+		return "VisibilityChanged"
+	}
+	return "Unknown"
+}
+
+// AccessibilityEvent describes a change of the accessibility tree
+type AccessibilityEvent struct {
+	Type     AccessibilityEventType
+	Node     *AccessibleNode // for AccessibilityNodeRemoved, the last snapshot of the removed node
+	Previous *AccessibleNode // nil for AccessibilityNodeAdded
+}
+
+// AccessibilityBridge is implemented by platform adapters (e.g. AT-SPI, UI Automation) which publish the tree
+type AccessibilityBridge interface {
+	// TreeUpdated() is called with the new tree after every update that has events
+	TreeUpdated(root *AccessibleNode)
+	// AccessibilityEvent() is called for each detected change, before TreeUpdated()
+	AccessibilityEvent(event AccessibilityEvent)
+}
+
+// AccessibilityTracker builds accessibility trees of a widget tree and reports changes between them
+type AccessibilityTracker struct {
+	root   Widget
+	bridge AccessibilityBridge
+	ids    map[Widget]int
+	nextID int
+	nodes  map[Widget]*AccessibleNode
+	tree   *AccessibleNode
+}
+
+// NewAccessibilityTracker() creates a tracker of the widget tree under root. bridge can be nil
+func NewAccessibilityTracker(root Widget, bridge AccessibilityBridge) *AccessibilityTracker {
+	return &AccessibilityTracker{
+		root:   root,
+		bridge: bridge,
+		ids:    make(map[Widget]int),
+		nextID: 1,
+		nodes:  make(map[Widget]*AccessibleNode),
+	}
+}
+
+// Bridge() returns the bridge which receives the changes
+func (a *AccessibilityTracker) Bridge() AccessibilityBridge {
+	return a.bridge
+}
+
+// SetBridge() sets the bridge which receives the changes
+func (a *AccessibilityTracker) SetBridge(bridge AccessibilityBridge) {
+	a.bridge = bridge
+}
+
+// Tree() returns the tree built by the last Update()
+func (a *AccessibilityTracker) Tree() *AccessibleNode {
+	return a.tree
+}
+
+// Node() returns the node of the widget built by the last Update()
+func (a *AccessibilityTracker) Node(widget Widget) *AccessibleNode {
+	return a.nodes[widget]
+}
+
+// Update() rebuilds the tree and returns the changes since the previous update
+//
+// The events are also sent to the bridge.
+func (a *AccessibilityTracker) Update() []AccessibilityEvent {
+	previous := a.nodes
+	a.nodes = make(map[Widget]*AccessibleNode)
+	a.tree = a.build(a.root, false)
+
+	var events []AccessibilityEvent
+	var focusEvent *AccessibilityEvent
+	a.walk(a.tree, func(node *AccessibleNode) {
+		old, ok := previous[node.Widget]
+		if !ok {
+			events = append(events, AccessibilityEvent{Type: AccessibilityNodeAdded, Node: node})
+			if node.Has(StateFocused) {
+				focusEvent = &AccessibilityEvent{Type: AccessibilityFocusChanged, Node: node}
+			}
+			return
+		}
+		if node.Name != old.Name {
+			events = append(events, AccessibilityEvent{Type: AccessibilityNameChanged, Node: node, Previous: old})
+		}
+		if node.Value != old.Value || node.Current != old.Current {
+			events = append(events, AccessibilityEvent{Type: AccessibilityValueChanged, Node: node, Previous: old})
+		}
+		if (node.State^old.State)&StateInvisible != 0 {
+			events = append(events, AccessibilityEvent{Type: AccessibilityVisibilityChanged, Node: node, Previous: old})
+		}
+		if (node.State^old.State)&^(StateInvisible|StateFocused) != 0 {
+			events = append(events, AccessibilityEvent{Type: AccessibilityStateChanged, Node: node, Previous: old})
+		}
+		if node.Has(StateFocused) && !old.Has(StateFocused) {
+			focusEvent = &AccessibilityEvent{Type: AccessibilityFocusChanged, Node: node, Previous: old}
+		}
+	})
+	for widget, old := range previous {
+		if _, ok := a.nodes[widget]; !ok {
+			events = append(events, AccessibilityEvent{Type: AccessibilityNodeRemoved, Node: old})
+			delete(a.ids, widget)
+		}
+	}
+	if focusEvent != nil {
+		events = append(events, *focusEvent)
+	}
+	if a.bridge != nil && len(events) > 0 {
+		for _, event := range events {
+			a.bridge.AccessibilityEvent(event)
+		}
+		a.bridge.TreeUpdated(a.tree)
+	}
+	return events
+}
+
+// build() describes the widget and its descendants. Descendants of an invisible widget are invisible too
+func (a *AccessibilityTracker) build(widget Widget, inheritedInvisible bool) *AccessibleNode {
+	node := DescribeWidget(widget)
+	if inheritedInvisible {
+		node.State |= StateInvisible
+	}
+	id, ok := a.ids[widget]
+	if !ok {
+		id = a.nextID
+		a.nextID++
+		a.ids[widget] = id
+	}
+	node.ID = id
+	a.nodes[widget] = node
+	for _, child := range widget.Children() {
+		node.Children = append(node.Children, a.build(child, node.Has(StateInvisible)))
+	}
+	return node
+}
+
+func (a *AccessibilityTracker) walk(node *AccessibleNode, f func(node *AccessibleNode)) {
+	f(node)
+	for _, child := range node.Children {
+		a.walk(child, f)
+	}
+}
+
+// SetAccessibilityBridge() connects a platform adapter. The tree is updated after each DrawAll() call
+func (s *Screen) SetAccessibilityBridge(bridge AccessibilityBridge) {
+	if s.accessibility == nil {
+		s.accessibility = NewAccessibilityTracker(s, bridge)
+	} else {
+		s.accessibility.SetBridge(bridge)
+	}
+}
+
+// Accessibility() returns the accessibility tracker of the screen
+func (s *Screen) Accessibility() *AccessibilityTracker {
+	if s.accessibility == nil {
+		s.accessibility = NewAccessibilityTracker(s, nil)
+	}
+	return s.accessibility
+}
+
+// AccessibilityTree() builds the current accessibility tree of the screen
+func (s *Screen) AccessibilityTree() *AccessibleNode {
+	s.Accessibility().Update()
+	return s.accessibility.Tree()
+}
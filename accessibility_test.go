@@ -0,0 +1,98 @@
+package nanogui
+
+import (
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+type recordingBridge struct {
+	events []AccessibilityEvent
+	trees  int
+}
+
+func (r *recordingBridge) TreeUpdated(root *AccessibleNode) {
+	r.trees++
+}
+
+func (r *recordingBridge) AccessibilityEvent(event AccessibilityEvent) {
+	r.events = append(r.events, event)
+}
+
+func (r *recordingBridge) has(eventType AccessibilityEventType, node *AccessibleNode) bool {
+	for _, event := range r.events {
+		if event.Type == eventType && event.Node.ID == node.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAccessibilityTree(t *testing.T) {
+	screen, window := newTestWindow(t)
+	NewLabel(window, "Volume")
+	slider := NewSlider(window)
+	mute := NewCheckBox(window, "Mute")
+	screen.PerformLayout()
+	bridge := &recordingBridge{}
+	screen.SetAccessibilityBridge(bridge)
+	screen.DrawAll()
+
+	a := screen.Accessibility()
+	if tree := a.Tree(); tree.Role != RoleApplication {
+		t.Errorf("root role = %v, want RoleApplication", tree.Role)
+	}
+	if bridge.trees == 0 || len(bridge.events) == 0 {
+		t.Fatal("the bridge isn't notified of the initial tree")
+	}
+	node := a.Node(slider)
+	if node.Role != RoleSlider || node.Name != "Volume" || !node.HasRange {
+		t.Fatalf("slider node = %+v, want a ranged slider labelled by the preceding label", node)
+	}
+
+	bridge.events = nil
+	screen.Input().PressKey(glfw.KeyTab, 0)
+	screen.Input().PressKey(glfw.KeyRight, 0)
+	mute.SetVisible(false)
+	screen.DrawAll()
+	if !a.Node(slider).Has(StateFocused) {
+		t.Error("the focused slider should have StateFocused")
+	}
+	if !bridge.has(AccessibilityFocusChanged, a.Node(slider)) {
+		t.Error("focus change isn't reported")
+	}
+	if !bridge.has(AccessibilityValueChanged, a.Node(slider)) {
+		t.Error("value change isn't reported")
+	}
+	if !bridge.has(AccessibilityVisibilityChanged, a.Node(mute)) {
+		t.Error("visibility change isn't reported")
+	}
+}
+
+func TestAccessibilityInheritedInvisible(t *testing.T) {
+	screen, window := newTestWindow(t)
+	panel := NewWidget(window)
+	panel.SetLayout(NewGroupLayout())
+	deep := NewCheckBox(panel, "Deep")
+	screen.PerformLayout()
+	a := screen.Accessibility()
+	a.Update()
+
+	window.SetVisible(false)
+	events := a.Update()
+	if !a.Node(deep).Has(StateInvisible) {
+		t.Fatal("a grandchild of a hidden window should be invisible")
+	}
+	bridge := &recordingBridge{events: events}
+	if !bridge.has(AccessibilityVisibilityChanged, a.Node(deep)) {
+		t.Error("visibility change of the grandchild isn't reported")
+	}
+}
+
+func TestAccessibleRangeWithEmptyRange(t *testing.T) {
+	node := &AccessibleNode{}
+	setAccessibleRange(node, 5, 5, 5)
+	if node.Value != "100%" {
+		t.Errorf("Value = %q, want %q", node.Value, "100%")
+	}
+}
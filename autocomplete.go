@@ -0,0 +1,425 @@
+package nanogui
+
+import (
+	"fmt"
+	"github.com/shibukawa/glfw"
+	"github.com/shibukawa/nanovgo"
+	"unicode"
+)
+
+// maxVisibleSuggestions is the number of rows of the autocomplete list shown without scrolling
+const maxVisibleSuggestions = 8
+
+// Suggestion is an entry of the autocomplete list of a TextBox
+type Suggestion struct {
+	// Text replaces the text before the cursor when the suggestion is accepted
+	Text string
+	// Label is drawn in the list. Text is used if it is empty
+	Label string
+	// Matches are the rune ranges [begin, end) of the label which are drawn highlighted
+	Matches [][2]int
+}
+
+func (s Suggestion) label() string {
+	if s.Label == "" {
+		return s.Text
+	}
+	return s.Label
+}
+
+// FilterSuggestions() returns the candidates which contain prefix (case insensitive) with the matching range highlighted.
+// Candidates which start with prefix come first
+func FilterSuggestions(prefix string, candidates []string) []Suggestion {
+	pattern := lowerRunes([]rune(prefix))
+	var heads, others []Suggestion
+	for _, candidate := range candidates {
+		index := indexRunes(lowerRunes([]rune(candidate)), pattern)
+		if index < 0 {
+			continue
+		}
+		suggestion := Suggestion{Text: candidate}
+		if len(pattern) > 0 {
+			suggestion.Matches = [][2]int{{index, index + len(pattern)}}
+		}
+		if index == 0 {
+			heads = append(heads, suggestion)
+		} else {
+			others = append(others, suggestion)
+		}
+	}
+	return append(heads, others...)
+}
+
+// SetCompleter() sets the provider of autocomplete suggestions
+//
+// It is called with the text before the cursor whenever the text is edited, and the
+// returned suggestions are shown in a popup under the text box. Up/Down select a
+// suggestion, Enter or a click accepts it and Escape closes the list.
+func (t *TextBox) SetCompleter(completer func(prefix string) []Suggestion) {
+	t.completer = completer
+	t.asyncCompleter = nil
+	t.HideSuggestions()
+}
+
+// SetAsyncCompleter() sets a provider of autocomplete suggestions which answers through a channel
+//
+// The result is received while drawing, so the provider can look up the suggestions in
+// another goroutine. The result of an outdated request is discarded.
+func (t *TextBox) SetAsyncCompleter(completer func(prefix string) <-chan []Suggestion) {
+	t.asyncCompleter = completer
+	t.completer = nil
+	t.HideSuggestions()
+}
+
+// Suggestions() returns the suggestions shown in the list
+func (t *TextBox) Suggestions() []Suggestion {
+	if !t.SuggestionsVisible() {
+		return nil
+	}
+	return t.suggestionList.suggestions
+}
+
+// SuggestionsVisible() returns whether or not the autocomplete list is shown
+func (t *TextBox) SuggestionsVisible() bool {
+	return t.suggestionPopup != nil && t.suggestionPopup.Visible()
+}
+
+// SelectedSuggestion() returns the index of the suggestion selected in the list, or -1
+func (t *TextBox) SelectedSuggestion() int {
+	if !t.SuggestionsVisible() {
+		return -1
+	}
+	return t.suggestionList.selected
+}
+
+// ShowSuggestions() requests the suggestions for the text before the cursor, even if it is empty. Down does the same
+func (t *TextBox) ShowSuggestions() {
+	t.requestSuggestions(true)
+}
+
+// HideSuggestions() closes the autocomplete list and discards the pending asynchronous request
+func (t *TextBox) HideSuggestions() {
+	t.pendingSuggestions = nil
+	if t.suggestionPopup != nil {
+		t.suggestionPopup.SetVisible(false)
+	}
+}
+
+// requestSuggestions() asks the completer for suggestions. An empty prefix closes the list unless force is true
+func (t *TextBox) requestSuggestions(force bool) {
+	if t.completer == nil && t.asyncCompleter == nil {
+		return
+	}
+	if t.committed || len(t.preeditText) != 0 || t.cursorPos < 0 {
+		return
+	}
+	prefix := string(t.valueTemp[:t.cursorPos])
+	if prefix == "" && !force {
+		t.HideSuggestions()
+		return
+	}
+	if t.completer != nil {
+		t.showSuggestionList(t.completer(prefix))
+	} else {
+		t.pendingSuggestions = t.asyncCompleter(prefix)
+	}
+}
+
+// pollSuggestions() receives the result of the pending asynchronous request without blocking
+func (t *TextBox) pollSuggestions() {
+	if t.pendingSuggestions == nil {
+		return
+	}
+	select {
+	case suggestions, ok := <-t.pendingSuggestions:
+		t.pendingSuggestions = nil
+		if ok && !t.committed && len(t.preeditText) == 0 {
+			t.showSuggestionList(suggestions)
+		}
+	default:
+	}
+}
+
+// updateSuggestions() refreshes the list after an edit: a new text asks for new suggestions, a cursor move closes the list
+func (t *TextBox) updateSuggestions(before editState) {
+	if !runesEqual(before.text, t.valueTemp) {
+		t.requestSuggestions(false)
+	} else if before.cursor != t.cursorPos {
+		t.HideSuggestions()
+	}
+}
+
+func (t *TextBox) showSuggestionList(suggestions []Suggestion) {
+	if len(suggestions) == 0 {
+		t.HideSuggestions()
+		return
+	}
+	screen := findScreen(t)
+	if screen == nil {
+		return
+	}
+	if t.suggestionPopup == nil {
+		window := t.FindWindow()
+		t.suggestionPopup = NewPopup(window.Parent(), window)
+		for t.suggestionPopup.ChildCount() > 0 {
+			t.suggestionPopup.RemoveChildByIndex(t.suggestionPopup.ChildCount() - 1)
+		}
+		t.suggestionPopup.SetVisible(false)
+		t.suggestionPopup.SetArrowVisible(false)
+		t.suggestionPopup.SetAnchorHeight(0)
+		t.suggestionPopup.SetLayout(&suggestionLayout{})
+		t.suggestionList = newSuggestionList(t.suggestionPopup, t)
+	}
+	t.suggestionList.setSuggestions(suggestions)
+	t.placeSuggestions()
+	if !t.suggestionPopup.Visible() {
+		t.suggestionPopup.SetVisible(true)
+		screen.MoveWindowToFront(t.suggestionPopup)
+	}
+}
+
+// placeSuggestions() moves the autocomplete list under the text box
+func (t *TextBox) placeSuggestions() {
+	ax, ay := t.AbsolutePosition()
+	wx, wy := t.suggestionPopup.ParentWindow().Position()
+	t.suggestionPopup.SetAnchorPosition(ax-wx, ay-wy+t.h)
+	rows := minI(len(t.suggestionList.suggestions), maxVisibleSuggestions)
+	h := rows*t.suggestionList.rowHeight() + 4
+	t.suggestionPopup.SetSize(t.w, h)
+	t.suggestionPopup.Layout().OnPerformLayout(t.suggestionPopup, nil)
+}
+
+// suggestionLayout keeps the list inside the autocomplete popup with a small vertical padding
+type suggestionLayout struct{}
+
+func (s *suggestionLayout) OnPerformLayout(widget Widget, ctx DrawContext) {
+	for _, child := range widget.Children() {
+		child.SetPosition(0, 2)
+		child.SetSize(widget.Width(), widget.Height()-4)
+	}
+}
+
+func (s *suggestionLayout) PreferredSize(widget Widget, ctx DrawContext) (int, int) {
+	return widget.Size()
+}
+
+func (s *suggestionLayout) String() string {
+	return "suggestionLayout"
+}
+
+// suggestionKeyboardEvent() handles the keys of the autocomplete list. It returns false for the keys the text box handles
+func (t *TextBox) suggestionKeyboardEvent(key glfw.Key, editAction EditAction) bool {
+	if !t.SuggestionsVisible() {
+		if editAction == EditActionMoveDown && (t.completer != nil || t.asyncCompleter != nil) {
+			t.ShowSuggestions()
+			return true
+		}
+		return false
+	}
+	switch {
+	case key == glfw.KeyEscape:
+		t.HideSuggestions()
+	case editAction == EditActionMoveUp:
+		t.suggestionList.moveSelection(-1)
+	case editAction == EditActionMoveDown:
+		t.suggestionList.moveSelection(1)
+	case editAction == EditActionPageUp:
+		t.suggestionList.moveSelection(-maxVisibleSuggestions)
+	case editAction == EditActionPageDown:
+		t.suggestionList.moveSelection(maxVisibleSuggestions)
+	case editAction == EditActionEnter && t.suggestionList.selected >= 0:
+		t.acceptSuggestion(t.suggestionList.selected)
+	default:
+		return false
+	}
+	return true
+}
+
+// acceptSuggestion() replaces the text before the cursor with the suggestion as an undoable edit
+func (t *TextBox) acceptSuggestion(index int) {
+	if !t.SuggestionsVisible() || index < 0 || index >= len(t.suggestionList.suggestions) {
+		return
+	}
+	suggestion := t.suggestionList.suggestions[index]
+	t.HideSuggestions()
+	if t.committed {
+		return
+	}
+	before := t.editState()
+	if t.mask != nil {
+		t.valueTemp = t.mask.fill(suggestion.Text)
+		t.cursorPos = t.mask.end()
+	} else {
+		text := []rune(suggestion.Text)
+		t.valueTemp = append(text, t.valueTemp[t.cursorPos:]...)
+		t.cursorPos = len(text)
+	}
+	t.selectionPos = -1
+	t.history.record(before, t.valueTemp, t.cursorPos, editKindOther)
+	t.updateValidation()
+}
+
+// suggestionList is the content of the autocomplete popup of a TextBox
+//
+// It handles the clicks by itself, so the text box keeps the focus while a suggestion is picked.
+type suggestionList struct {
+	WidgetImplement
+
+	textBox     *TextBox
+	suggestions []Suggestion
+	selected    int
+	firstRow    int
+}
+
+func newSuggestionList(parent Widget, textBox *TextBox) *suggestionList {
+	list := &suggestionList{
+		textBox:  textBox,
+		selected: -1,
+	}
+	InitWidget(list, parent)
+	return list
+}
+
+func (l *suggestionList) setSuggestions(suggestions []Suggestion) {
+	l.suggestions = suggestions
+	l.selected = -1
+	l.firstRow = 0
+}
+
+func (l *suggestionList) rowHeight() int {
+	return int(float32(l.textBox.FontSize()) * 1.4)
+}
+
+// rowAt() returns the index of the suggestion at the position in the parent coordinates, or -1
+func (l *suggestionList) rowAt(y int) int {
+	if y < l.y {
+		return -1
+	}
+	row := (y-l.y)/l.rowHeight() + l.firstRow
+	if row >= len(l.suggestions) {
+		return -1
+	}
+	return row
+}
+
+func (l *suggestionList) moveSelection(delta int) {
+	if l.selected < 0 && delta < 0 {
+		l.selected = len(l.suggestions)
+	}
+	l.selected = clampI(l.selected+delta, 0, len(l.suggestions)-1)
+	if l.selected < l.firstRow {
+		l.firstRow = l.selected
+	} else if l.selected >= l.firstRow+maxVisibleSuggestions {
+		l.firstRow = l.selected - maxVisibleSuggestions + 1
+	}
+}
+
+func (l *suggestionList) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	if button == glfw.MouseButton1 && down {
+		if row := l.rowAt(y); row >= 0 {
+			l.textBox.acceptSuggestion(row)
+		}
+	}
+	return true
+}
+
+func (l *suggestionList) MouseMotionEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	if row := l.rowAt(y); row >= 0 {
+		l.selected = row
+	}
+	return true
+}
+
+func (l *suggestionList) ScrollEvent(self Widget, x, y, relX, relY int) bool {
+	l.firstRow = clampI(l.firstRow-relY, 0, maxI(len(l.suggestions)-maxVisibleSuggestions, 0))
+	return true
+}
+
+func (l *suggestionList) PreferredSize(self Widget, ctx DrawContext) (int, int) {
+	return l.textBox.Width(), minI(len(l.suggestions), maxVisibleSuggestions) * l.rowHeight()
+}
+
+func (l *suggestionList) Draw(self Widget, ctx DrawContext) {
+	x := float32(l.x)
+	w := float32(l.w)
+	rh := float32(l.rowHeight())
+	xSpacing := rh * 0.3
+	fontSize := float32(l.textBox.FontSize())
+
+	ctx.Scissor(x, float32(l.y), w, float32(l.h))
+	ctx.SetFontSize(fontSize)
+	ctx.SetFillColor(l.theme.TextColor)
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+	last := minI(len(l.suggestions), l.firstRow+maxVisibleSuggestions)
+	for i := l.firstRow; i < last; i++ {
+		y := float32(l.y) + float32(i-l.firstRow)*rh
+		if i == l.selected {
+			ctx.BeginPath()
+			ctx.RoundedRect(x+2, y, w-4, rh, 3)
+			ctx.SetFillColor(nanovgo.MONO(255, 40))
+			ctx.Fill()
+		}
+		l.drawLabel(ctx, x+xSpacing, y+rh*0.5, l.suggestions[i])
+	}
+	if len(l.suggestions) > maxVisibleSuggestions {
+		// scroll bar
+		barH := float32(l.h) * float32(maxVisibleSuggestions) / float32(len(l.suggestions))
+		barY := float32(l.y) + float32(l.h)*float32(l.firstRow)/float32(len(l.suggestions))
+		ctx.BeginPath()
+		ctx.RoundedRect(x+w-6, barY, 4, barH, 2)
+		ctx.SetFillColor(nanovgo.MONO(255, 64))
+		ctx.Fill()
+	}
+	ctx.ResetScissor()
+}
+
+// drawLabel() draws the label of a suggestion; the matched ranges are drawn in the bold font
+func (l *suggestionList) drawLabel(ctx DrawContext, x, y float32, suggestion Suggestion) {
+	label := []rune(suggestion.label())
+	begin := 0
+	for begin < len(label) {
+		matched := inSuggestionMatch(suggestion.Matches, begin)
+		end := begin + 1
+		for end < len(label) && inSuggestionMatch(suggestion.Matches, end) == matched {
+			end++
+		}
+		if matched {
+			ctx.SetFontFace(l.theme.FontBold)
+		} else {
+			ctx.SetFontFace(l.textBox.Font())
+		}
+		x = ctx.TextRune(x, y, label[begin:end])
+		begin = end
+	}
+}
+
+func (l *suggestionList) String() string {
+	return l.StringHelper(fmt.Sprintf("SuggestionList(%d)", len(l.suggestions)), "")
+}
+
+func inSuggestionMatch(matches [][2]int, index int) bool {
+	for _, match := range matches {
+		if index >= match[0] && index < match[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func lowerRunes(text []rune) []rune {
+	result := make([]rune, len(text))
+	for i, r := range text {
+		result[i] = unicode.ToLower(r)
+	}
+	return result
+}
+
+// indexRunes() returns the index of the first pattern in text, or -1
+func indexRunes(text, pattern []rune) int {
+	for i := 0; i+len(pattern) <= len(text); i++ {
+		if runesEqual(text[i:i+len(pattern)], pattern) {
+			return i
+		}
+	}
+	return -1
+}
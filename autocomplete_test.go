@@ -0,0 +1,116 @@
+package nanogui
+
+import (
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+// newHostBox() creates a text box which completes host names
+func newHostBox(window *Window) *TextBox {
+	textBox := NewTextBox(window, "")
+	textBox.SetEditable(true)
+	textBox.SetAlignment(TextLeft)
+	textBox.SetFixedSize(300, 0)
+	hosts := []string{"alpha.example.com", "beta.example.com", "Alphabet.org", "gamma"}
+	textBox.SetCompleter(func(prefix string) []Suggestion {
+		return FilterSuggestions(prefix, hosts)
+	})
+	return textBox
+}
+
+func TestAutocompleteKeyboard(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := newHostBox(window)
+	NewTextBox(window, "next").SetEditable(true)
+	screen.PerformLayout()
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.TypeText("alp")
+	if found := textBox.Suggestions(); len(found) != 2 || found[1].Matches[0] != [2]int{0, 3} {
+		t.Fatalf("Suggestions() = %+v, want two case insensitive prefix matches", found)
+	}
+	in.PressKey(glfw.KeyDown, 0)
+	in.PressKey(glfw.KeyDown, 0)
+	if textBox.SelectedSuggestion() != 1 {
+		t.Fatalf("SelectedSuggestion() = %d, want 1", textBox.SelectedSuggestion())
+	}
+	in.PressKey(glfw.KeyEnter, 0)
+	if string(textBox.valueTemp) != "Alphabet.org" || textBox.SuggestionsVisible() || !textBox.Focused() {
+		t.Fatalf("Enter should accept the suggestion and keep the focus: %q", string(textBox.valueTemp))
+	}
+	in.PressKey(glfw.KeyZ, glfw.ModControl)
+	if got := string(textBox.valueTemp); got != "alp" {
+		t.Fatalf("accepting a suggestion should be undoable: %q", got)
+	}
+
+	in.TypeText("h")
+	screen.DrawAll()
+	if !textBox.SuggestionsVisible() {
+		t.Fatal("typing should show the suggestions again")
+	}
+	in.PressKey(glfw.KeyEscape, 0)
+	if textBox.SuggestionsVisible() {
+		t.Fatal("Escape should hide the suggestions")
+	}
+	in.PressKey(glfw.KeyBackspace, 0)
+	if !textBox.SuggestionsVisible() {
+		t.Fatal("Backspace should show the suggestions again")
+	}
+	in.PressKey(glfw.KeyTab, 0)
+	if textBox.SuggestionsVisible() {
+		t.Fatal("losing the focus should hide the suggestions")
+	}
+}
+
+func TestAutocompleteClick(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := newHostBox(window)
+	screen.PerformLayout()
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.TypeText("alph")
+	screen.DrawAll()
+	x, y := textBox.suggestionPopup.Position()
+	in.ClickAt(x+10, y+5)
+	if string(textBox.valueTemp) != "alpha.example.com" || !textBox.Focused() {
+		t.Fatalf("clicking the first row: %q, focused %v", string(textBox.valueTemp), textBox.Focused())
+	}
+}
+
+func TestAutocompleteDuringPreedit(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := newHostBox(window)
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.TypeText("g")
+	in.Preedit("あ", []int{1}, 0)
+	if textBox.SuggestionsVisible() {
+		t.Fatal("suggestions should be hidden while composing")
+	}
+	in.CommitPreedit()
+	if textBox.SuggestionsVisible() {
+		t.Fatal("nothing matches the committed text")
+	}
+}
+
+func TestAsyncCompleter(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := NewTextBox(window, "")
+	textBox.SetEditable(true)
+	results := make(chan []Suggestion, 1)
+	textBox.SetAsyncCompleter(func(prefix string) <-chan []Suggestion {
+		return results
+	})
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.TypeText("z")
+	if textBox.SuggestionsVisible() {
+		t.Fatal("suggestions shouldn't be shown before the completer answers")
+	}
+	results <- []Suggestion{{Text: "zzz"}}
+	screen.DrawAll()
+	if found := textBox.Suggestions(); len(found) != 1 || found[0].Text != "zzz" {
+		t.Errorf("Suggestions() = %+v, want the async result", found)
+	}
+}
@@ -0,0 +1,462 @@
+package nanogui
+
+import (
+	"unicode"
+)
+
+// TextDirection is the paragraph direction of text
+type TextDirection int
+
+const (
+	// TextDirectionAuto takes the direction of the first strong character (left-to-right if there is none)
+	TextDirectionAuto TextDirection = iota
+	// TextDirectionLTR lays out the paragraph left-to-right
+	TextDirectionLTR
+	// TextDirectionRTL lays out the paragraph right-to-left
+	TextDirectionRTL
+)
+
+func (d TextDirection) String() string {
+	switch d {
+	case TextDirectionLTR:
+		return "ltr"
+	case TextDirectionRTL:
+		return "rtl"
+	}
+	return "auto"
+}
+
+// bidiClass is the Bidi_Class property of UAX #9
+type bidiClass int
+
+const (
+	bidiL bidiClass = iota
+	bidiR
+	bidiAL
+	bidiEN
+	bidiES
+	bidiET
+	bidiAN
+	bidiCS
+	bidiNSM
+	bidiBN
+	bidiB
+	bidiS
+	bidiWS
+	bidiON
+)
+
+var bidiRightToLeft = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x0590, 0x05ff, 1}, {0x07c0, 0x085f, 1}, {0x200f, 0x200f, 1}, {0xfb1d, 0xfb4f, 1},
+	},
+	R32: []unicode.Range32{
+		{0x10800, 0x10cff, 1}, {0x10d40, 0x10eff, 1}, {0x1e800, 0x1ec6f, 1},
+	},
+}
+
+var bidiArabicLetter = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x0600, 0x07bf, 1}, {0x0860, 0x08ff, 1}, {0xfb50, 0xfdcf, 1}, {0xfdf0, 0xfdff, 1},
+		{0xfe70, 0xfeff, 1},
+	},
+	R32: []unicode.Range32{
+		{0x10d00, 0x10d3f, 1}, {0x1ec70, 0x1efff, 1},
+	},
+}
+
+var bidiArabicNumber = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x0600, 0x0605, 1}, {0x0660, 0x0669, 1}, {0x066b, 0x066c, 1}, {0x06dd, 0x06dd, 1},
+		{0x0890, 0x0891, 1}, {0x08e2, 0x08e2, 1},
+	},
+	R32: []unicode.Range32{
+		{0x10e60, 0x10e7e, 1},
+	},
+}
+
+var bidiCommonSeparator = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x002c, 0x002c, 1}, {0x002e, 0x002f, 1}, {0x003a, 0x003a, 1}, {0x00a0, 0x00a0, 1},
+		{0x060c, 0x060c, 1}, {0x202f, 0x202f, 1}, {0x2044, 0x2044, 1}, {0xfe50, 0xfe50, 1},
+		{0xfe52, 0xfe52, 1}, {0xfe55, 0xfe55, 1}, {0xff0c, 0xff0c, 1}, {0xff0e, 0xff0f, 1},
+		{0xff1a, 0xff1a, 1},
+	},
+}
+
+// bidiMirrors are the Bidi_Mirroring_Glyph pairs drawn mirrored in right-to-left runs
+var bidiMirrors = map[rune]rune{
+	'(': ')', ')': '(', '<': '>', '>': '<', '[': ']', ']': '[', '{': '}', '}': '{',
+	'«': '»', '»': '«', '‹': '›', '›': '‹', '⁅': '⁆', '⁆': '⁅', '≤': '≥', '≥': '≤',
+	'「': '」', '」': '「', '『': '』', '』': '『', '（': '）', '）': '（', '［': '］', '］': '［',
+}
+
+func bidiClassOf(r rune) bidiClass {
+	switch {
+	case r >= '0' && r <= '9', r >= 0x06f0 && r <= 0x06f9, r >= 0x2070 && r <= 0x2079 && r != 0x2071, r >= 0x2080 && r <= 0x2089,
+		r >= 0xff10 && r <= 0xff19, r == 0xb2, r == 0xb3, r == 0xb9:
+		return bidiEN
+	case r == '+', r == '-', r == 0x207a, r == 0x207b, r == 0x208a, r == 0x208b, r == 0x2212, r == 0xfb29,
+		r == 0xfe62, r == 0xfe63, r == 0xff0b, r == 0xff0d:
+		return bidiES
+	case r == '#', r == '$', r == '%', r == 0xb0, r == 0xb1, r >= 0x2030 && r <= 0x2034, r == 0x066a,
+		unicode.Is(unicode.Sc, r):
+		return bidiET
+	case unicode.Is(bidiArabicNumber, r):
+		return bidiAN
+	case unicode.Is(bidiCommonSeparator, r):
+		return bidiCS
+	case r == '\n', r == '\r', r >= 0x1c && r <= 0x1e, r == 0x85, r == 0x2029:
+		return bidiB
+	case r == '\t', r == 0x0b, r == 0x1f:
+		return bidiS
+	case r == ' ', r == '\f', r == 0x2028, unicode.Is(unicode.Zs, r):
+		return bidiWS
+	case r == 0x200e:
+		return bidiL
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return bidiNSM
+	case unicode.Is(bidiRightToLeft, r):
+		return bidiR
+	case r == 0x061c, unicode.Is(bidiArabicLetter, r):
+		return bidiAL
+	case unicode.In(r, unicode.Cc, unicode.Cf):
+		// explicit embedding, override and isolate controls are ignored like boundary neutrals (X9)
+		return bidiBN
+	case unicode.In(r, unicode.P, unicode.S):
+		return bidiON
+	}
+	return bidiL
+}
+
+// isStrongRTL() returns whether or not the class is a strong right-to-left class
+func isStrongRTL(class bidiClass) bool {
+	return class == bidiR || class == bidiAL
+}
+
+// resolveParagraphRTL() returns whether or not the paragraph direction of text is right-to-left (P2, P3)
+func resolveParagraphRTL(text []rune, direction TextDirection) bool {
+	switch direction {
+	case TextDirectionLTR:
+		return false
+	case TextDirectionRTL:
+		return true
+	}
+	for _, r := range text {
+		switch class := bidiClassOf(r); {
+		case class == bidiL:
+			return false
+		case isStrongRTL(class):
+			return true
+		}
+	}
+	return false
+}
+
+// resolveBidiLevels() returns the embedding level of each rune of a paragraph by the rules W1-W7, N1-N2, I1-I2 and L1 of UAX #9
+func resolveBidiLevels(text []rune, rtl bool) []int {
+	base := toI(rtl, 1, 0)
+	levels := make([]int, len(text))
+	original := make([]bidiClass, len(text))
+	hasRTL := rtl
+	for i, r := range text {
+		original[i] = bidiClassOf(r)
+		hasRTL = hasRTL || isStrongRTL(original[i]) || original[i] == bidiAN
+	}
+	if !hasRTL {
+		return levels
+	}
+	sos := bidiClass(toI(rtl, int(bidiR), int(bidiL)))
+
+	// boundary neutrals are removed (X9)
+	var indexes []int
+	for i, class := range original {
+		if class != bidiBN {
+			indexes = append(indexes, i)
+		}
+	}
+	types := make([]bidiClass, len(indexes))
+	for i, index := range indexes {
+		types[i] = original[index]
+	}
+
+	// W1: NSM takes the type of the previous character
+	for i := range types {
+		if types[i] == bidiNSM {
+			if i == 0 {
+				types[i] = sos
+			} else {
+				types[i] = types[i-1]
+			}
+		}
+	}
+	// W2: EN after AL becomes AN
+	lastStrong := sos
+	for i, class := range types {
+		switch class {
+		case bidiL, bidiR, bidiAL:
+			lastStrong = class
+		case bidiEN:
+			if lastStrong == bidiAL {
+				types[i] = bidiAN
+			}
+		}
+	}
+	// W3: AL becomes R
+	for i := range types {
+		if types[i] == bidiAL {
+			types[i] = bidiR
+		}
+	}
+	// W4: a single separator between numbers of the same type takes their type
+	for i := 1; i+1 < len(types); i++ {
+		before, after := types[i-1], types[i+1]
+		switch {
+		case types[i] == bidiES && before == bidiEN && after == bidiEN:
+			types[i] = bidiEN
+		case types[i] == bidiCS && before == after && (before == bidiEN || before == bidiAN):
+			types[i] = before
+		}
+	}
+	// W5: terminators adjacent to European numbers become EN
+	for i := 0; i < len(types); i++ {
+		if types[i] != bidiET {
+			continue
+		}
+		end := i
+		for end < len(types) && types[end] == bidiET {
+			end++
+		}
+		if (i > 0 && types[i-1] == bidiEN) || (end < len(types) && types[end] == bidiEN) {
+			for j := i; j < end; j++ {
+				types[j] = bidiEN
+			}
+		}
+		i = end - 1
+	}
+	// W6: remaining separators and terminators become ON
+	for i, class := range types {
+		if class == bidiES || class == bidiET || class == bidiCS {
+			types[i] = bidiON
+		}
+	}
+	// W7: EN after L becomes L
+	lastStrong = sos
+	for i, class := range types {
+		switch class {
+		case bidiL, bidiR:
+			lastStrong = class
+		case bidiEN:
+			if lastStrong == bidiL {
+				types[i] = bidiL
+			}
+		}
+	}
+	// N1, N2: neutrals take the direction of the surrounding strong text, or the embedding direction
+	strongOf := func(class bidiClass) bidiClass {
+		if class == bidiEN || class == bidiAN {
+			return bidiR
+		}
+		return class
+	}
+	for i := 0; i < len(types); i++ {
+		if !isBidiNeutral(types[i]) {
+			continue
+		}
+		end := i
+		for end < len(types) && isBidiNeutral(types[end]) {
+			end++
+		}
+		before, after := sos, sos
+		if i > 0 {
+			before = strongOf(types[i-1])
+		}
+		if end < len(types) {
+			after = strongOf(types[end])
+		}
+		direction := sos
+		if before == after {
+			direction = before
+		}
+		for j := i; j < end; j++ {
+			types[j] = direction
+		}
+		i = end - 1
+	}
+	// I1, I2
+	for i, index := range indexes {
+		switch {
+		case base == 0 && types[i] == bidiR:
+			levels[index] = 1
+		case base == 0 && (types[i] == bidiAN || types[i] == bidiEN):
+			levels[index] = 2
+		case base == 1 && (types[i] == bidiL || types[i] == bidiAN || types[i] == bidiEN):
+			levels[index] = 2
+		default:
+			levels[index] = base
+		}
+	}
+	// boundary neutrals take the level of the previous character
+	for i, class := range original {
+		if class == bidiBN {
+			levels[i] = toI(i > 0, levels[maxI(i-1, 0)], base)
+		}
+	}
+	// L1: separators and the trailing white space go back to the paragraph level
+	trailing := true
+	for i := len(original) - 1; i >= 0; i-- {
+		switch original[i] {
+		case bidiS, bidiB:
+			levels[i] = base
+			trailing = true
+		case bidiWS, bidiBN:
+			if trailing {
+				levels[i] = base
+			}
+		default:
+			trailing = false
+		}
+	}
+	return levels
+}
+
+func isBidiNeutral(class bidiClass) bool {
+	return class == bidiB || class == bidiS || class == bidiWS || class == bidiON
+}
+
+// bidiText is a line of text laid out in visual order by the Unicode Bidirectional Algorithm
+//
+// Grapheme clusters are kept together while reordering. A cursor position is a
+// logical index; its caret is drawn at a "slot" between two visual clusters.
+type bidiText struct {
+	visual     []rune   // runes in visual order, mirrored in right-to-left runs
+	clusters   [][2]int // logical rune ranges of the grapheme clusters in visual order
+	rtl        []bool   // whether or not each visual cluster runs right-to-left
+	runeStarts []int    // visual rune index of each visual cluster
+}
+
+// newBidiText() lays out a line. The paragraph direction is resolved from the line itself
+func newBidiText(text []rune, direction TextDirection) *bidiText {
+	return newBidiLine(text, resolveParagraphRTL(text, direction))
+}
+
+// newBidiLine() lays out a line of a paragraph whose direction is already resolved
+func newBidiLine(text []rune, rtl bool) *bidiText {
+	levels := resolveBidiLevels(text, rtl)
+	boundaries := graphemeBoundaries(text)
+	n := len(boundaries) - 1
+	order := make([]int, n)
+	clusterLevels := make([]int, n)
+	maxLevel, minOddLevel := 0, 1<<30
+	for i := range order {
+		order[i] = i
+		if n > 0 && boundaries[i] < len(levels) {
+			clusterLevels[i] = levels[boundaries[i]]
+		}
+		maxLevel = maxI(maxLevel, clusterLevels[i])
+		if clusterLevels[i]%2 == 1 {
+			minOddLevel = minI(minOddLevel, clusterLevels[i])
+		}
+	}
+	// L2: reverse the runs from the highest level to the lowest odd level
+	for level := maxLevel; level >= minOddLevel && level > 0; level-- {
+		for i := 0; i < n; i++ {
+			if clusterLevels[order[i]] < level {
+				continue
+			}
+			end := i
+			for end < n && clusterLevels[order[end]] >= level {
+				end++
+			}
+			for a, b := i, end-1; a < b; a, b = a+1, b-1 {
+				order[a], order[b] = order[b], order[a]
+			}
+			i = end
+		}
+	}
+	b := &bidiText{
+		visual:     make([]rune, 0, len(text)),
+		clusters:   make([][2]int, n),
+		rtl:        make([]bool, n),
+		runeStarts: make([]int, n),
+	}
+	for v, c := range order {
+		begin, end := boundaries[c], boundaries[c+1]
+		b.clusters[v] = [2]int{begin, end}
+		b.rtl[v] = clusterLevels[c]%2 == 1
+		b.runeStarts[v] = len(b.visual)
+		for _, r := range text[begin:end] {
+			if mirror, ok := bidiMirrors[r]; ok && b.rtl[v] {
+				r = mirror
+			}
+			b.visual = append(b.visual, r)
+		}
+	}
+	return b
+}
+
+// caretSlot() returns the slot of the caret at a logical position. The caret sticks to the character before it
+func (b *bidiText) caretSlot(pos int) int {
+	for v, c := range b.clusters {
+		if pos > 0 && c[1] == pos {
+			return toI(b.rtl[v], v, v+1)
+		}
+	}
+	for v, c := range b.clusters {
+		if c[0] == pos {
+			return toI(b.rtl[v], v+1, v)
+		}
+	}
+	return 0
+}
+
+// slotPosition() returns the logical position of the caret at a slot
+func (b *bidiText) slotPosition(slot int) int {
+	if len(b.clusters) == 0 {
+		return 0
+	}
+	if slot > 0 {
+		c := b.clusters[slot-1]
+		return toI(b.rtl[slot-1], c[0], c[1])
+	}
+	c := b.clusters[0]
+	return toI(b.rtl[0], c[1], c[0])
+}
+
+// slotRuneIndex() returns the visual rune index where the slot is drawn
+func (b *bidiText) slotRuneIndex(slot int) int {
+	if slot >= len(b.runeStarts) {
+		return len(b.visual)
+	}
+	return b.runeStarts[slot]
+}
+
+// moveVisual() moves the cursor at pos by delta slots to the right (or to the left if delta is negative)
+//
+// At the boundary of runs one logical position can be drawn at two slots; only the slot
+// where its caret is drawn is a stop, so the cursor never jumps back.
+func (b *bidiText) moveVisual(pos, delta int) int {
+	for slot := b.caretSlot(pos) + delta; slot >= 0 && slot <= len(b.clusters); slot += delta {
+		if next := b.slotPosition(slot); next != pos && b.caretSlot(next) == slot {
+			return next
+		}
+	}
+	return pos
+}
+
+// visualRanges() returns the visual rune ranges which show the logical range [begin, end)
+func (b *bidiText) visualRanges(begin, end int) [][2]int {
+	var ranges [][2]int
+	for v, c := range b.clusters {
+		if c[0] < begin || c[1] > end {
+			continue
+		}
+		start, stop := b.runeStarts[v], b.slotRuneIndex(v+1)
+		if last := len(ranges) - 1; last >= 0 && ranges[last][1] == start {
+			ranges[last][1] = stop
+		} else {
+			ranges = append(ranges, [2]int{start, stop})
+		}
+	}
+	return ranges
+}
@@ -0,0 +1,95 @@
+package nanogui
+
+import (
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+func TestBidiReordering(t *testing.T) {
+	cases := []struct {
+		logical, visual string
+		direction       TextDirection
+	}{
+		{"abc", "abc", TextDirectionAuto},
+		{"אבג", "גבא", TextDirectionAuto},
+		{"abc אבג def", "abc גבא def", TextDirectionAuto},
+		{"אבג abc דהו", "והד abc גבא", TextDirectionAuto},
+		{"אבג 123 דה", "הד 123 גבא", TextDirectionAuto},
+		{"abc (אבג)", "abc (גבא)", TextDirectionAuto},
+		{"אב (cd)", "(cd) בא", TextDirectionAuto},
+		{"abc", "abc", TextDirectionRTL},
+		{"abc!", "!abc", TextDirectionRTL},
+		{"سلام 12", "12 مالس", TextDirectionAuto},
+	}
+	for _, c := range cases {
+		if got := string(newBidiText([]rune(c.logical), c.direction).visual); got != c.visual {
+			t.Errorf("%q (direction %v): visual %q, want %q", c.logical, c.direction, got, c.visual)
+		}
+	}
+}
+
+func TestBidiVisualMovement(t *testing.T) {
+	text := newBidiText([]rune("ab אבג"), TextDirectionAuto)
+	// the RTL run is entered at its canonical slot and walked backward in logical order
+	want := []int{0, 1, 2, 3, 5, 4}
+	pos := 0
+	for i, expected := range want {
+		if pos != expected {
+			t.Fatalf("step %d: position %d, want %d", i, pos, expected)
+		}
+		pos = text.moveVisual(pos, 1)
+	}
+}
+
+func TestBidiTextBox(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := NewTextBox(window, "")
+	textBox.SetEditable(true)
+	textBox.SetAlignment(TextLeft)
+	textBox.SetFixedSize(300, 0)
+	screen.PerformLayout()
+	screen.DrawAll()
+
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.TypeText("אבג")
+	if textBox.cursorPos != 3 {
+		t.Fatalf("typing: cursor %d, want 3", textBox.cursorPos)
+	}
+	// the logical end of RTL text is at the visual left, so Right moves backward
+	in.PressKey(glfw.KeyRight, 0)
+	if textBox.cursorPos != 2 {
+		t.Fatalf("Right: cursor %d, want 2", textBox.cursorPos)
+	}
+	in.PressKey(glfw.KeyLeft, glfw.ModShift)
+	in.PressKey(glfw.KeyLeft, glfw.ModShift)
+	if textBox.cursorPos != 3 || textBox.selectionPos != 2 {
+		t.Fatalf("Shift+Left: selection %d-%d, want 2-3", textBox.selectionPos, textBox.cursorPos)
+	}
+
+	ctx := screen.Context().(*RecordingContext)
+	ctx.Reset()
+	screen.DrawAll()
+	drawn := false
+	for _, call := range ctx.Calls() {
+		if call.Name == "TextRune" && string(call.Args[2].([]rune)) == "גבא" {
+			drawn = true
+		}
+	}
+	if !drawn {
+		t.Error("the text box should draw the text in visual order")
+	}
+}
+
+func TestBidiLabel(t *testing.T) {
+	screen, window := newTestWindow(t)
+	label := NewLabel(window, "שלום world")
+	label.SetTextDirection(TextDirectionRTL)
+	screen.PerformLayout()
+	screen.DrawAll()
+	texts := screen.Context().(*RecordingContext).Texts()
+	if !containsText(texts, "world םולש") {
+		t.Errorf("the RTL label isn't drawn in visual order: %q", texts)
+	}
+}
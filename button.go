@@ -37,6 +37,7 @@ type Button struct {
 	callback        func()
 	changeCallback  func(bool)
 	buttonGroup     []*Button
+	keyPressed      bool
 }
 
 func NewButton(parent Widget, captions ...string) *Button {
@@ -56,6 +57,7 @@ func NewButton(parent Widget, captions ...string) *Button {
 		flags:        NormalButtonType,
 	}
 	InitWidget(button, parent)
+	button.SetTabStop(true)
 	return button
 }
 
@@ -82,7 +84,10 @@ func (b *Button) Caption() string {
 }
 
 func (b *Button) SetCaption(caption string) {
-	b.caption = caption
+	if b.caption != caption {
+		b.caption = caption
+		b.InvalidateLayout()
+	}
 }
 
 func (b *Button) BackgroundColor() nanovgo.Color {
@@ -111,8 +116,11 @@ func (b *Button) Icon() Icon {
 }
 
 func (b *Button) SetIcon(i Icon) {
-	b.icon = i
-	b.imageIcon = 0
+	if b.icon != i || b.imageIcon != 0 {
+		b.icon = i
+		b.imageIcon = 0
+		b.InvalidateLayout()
+	}
 }
 
 func (b *Button) ImageIcon() int {
@@ -120,8 +128,11 @@ func (b *Button) ImageIcon() int {
 }
 
 func (b *Button) SetImageIcon(i int) {
-	b.imageIcon = i
-	b.icon = 0
+	if b.imageIcon != i || b.icon != 0 {
+		b.imageIcon = i
+		b.icon = 0
+		b.InvalidateLayout()
+	}
 }
 func (b *Button) Flags() ButtonFlags {
 	return b.flags
@@ -175,40 +186,57 @@ func (b *Button) FontSize() int {
 }
 
 func (b *Button) SetFontSize(size int) {
-	b.fontSize = size
+	if b.fontSize != size {
+		b.fontSize = size
+		b.InvalidateLayout()
+	}
 }
 
 func (b *Button) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
-	b.WidgetImplement.MouseButtonEvent(b, x, y, button, down, modifier)
+	b.WidgetImplement.MouseButtonEvent(self, x, y, button, down, modifier)
 
 	if button == glfw.MouseButton1 && b.enabled {
-		pushedBackup := b.pushed
-		if down {
-			if b.flags&RadioButtonType != 0 {
-				if len(b.buttonGroup) == 0 {
-					for _, child := range self.Parent().Children() {
-						button, ok := child.(*Button)
-						if ok && button != b && button.Flags()&RadioButtonType != 0 && button.Pushed() {
-							button.SetPushed(false)
-							if button.changeCallback != nil {
-								button.changeCallback(false)
-							}
-						}
-					}
-				} else {
-					for _, button := range b.buttonGroup {
-						if button != b && button.Flags()&RadioButtonType != 0 && button.Pushed() {
-							button.SetPushed(false)
-							if button.changeCallback != nil {
-								button.changeCallback(false)
-							}
+		b.press(self, down, b.Contains(x, y))
+		return true
+	}
+	return false
+}
+
+// KeyboardEvent() pushes the focused button by Space or Enter
+func (b *Button) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	if !b.enabled || !b.focused || !isActivationKey(key) {
+		return false
+	}
+	switch action {
+	case glfw.Press:
+		b.keyPressed = true
+		b.press(self, true, true)
+	case glfw.Release:
+		if b.keyPressed {
+			b.keyPressed = false
+			b.press(self, false, true)
+		}
+	}
+	return true
+}
+
+func (b *Button) press(self Widget, down, inside bool) {
+	pushedBackup := b.pushed
+	if down {
+		if b.flags&RadioButtonType != 0 {
+			if len(b.buttonGroup) == 0 {
+				for _, child := range self.Parent().Children() {
+					button, ok := child.(*Button)
+					if ok && button != b && button.Flags()&RadioButtonType != 0 && button.Pushed() {
+						button.SetPushed(false)
+						if button.changeCallback != nil {
+							button.changeCallback(false)
 						}
 					}
 				}
-			} else if b.flags&PopupButtonType != 0 {
-				for _, widget := range b.Parent().Children() {
-					button, ok := widget.(*Button)
-					if ok && button != b && button.Flags()&PopupButtonType != 0 && button.Pushed() {
+			} else {
+				for _, button := range b.buttonGroup {
+					if button != b && button.Flags()&RadioButtonType != 0 && button.Pushed() {
 						button.SetPushed(false)
 						if button.changeCallback != nil {
 							button.changeCallback(false)
@@ -216,28 +244,36 @@ func (b *Button) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton
 					}
 				}
 			}
-			if b.flags&ToggleButtonType != 0 {
-				b.pushed = !b.pushed
-			} else {
-				b.pushed = true
-			}
-		} else if b.pushed {
-			if b.Contains(x, y) && b.callback != nil {
-				b.callback()
-			}
-			if b.flags&NormalButtonType != 0 {
-				b.pushed = false
+		} else if b.flags&PopupButtonType != 0 {
+			for _, widget := range b.Parent().Children() {
+				button, ok := widget.(*Button)
+				if ok && button != b && button.Flags()&PopupButtonType != 0 && button.Pushed() {
+					button.SetPushed(false)
+					if button.changeCallback != nil {
+						button.changeCallback(false)
+					}
+				}
 			}
 		}
-		if pushedBackup != b.pushed && b.changeCallback != nil {
-			b.changeCallback(b.pushed)
+		if b.flags&ToggleButtonType != 0 {
+			b.pushed = !b.pushed
+		} else {
+			b.pushed = true
+		}
+	} else if b.pushed {
+		if inside && b.callback != nil {
+			b.callback()
+		}
+		if b.flags&NormalButtonType != 0 {
+			b.pushed = false
 		}
-		return true
 	}
-	return false
+	if pushedBackup != b.pushed && b.changeCallback != nil {
+		b.changeCallback(b.pushed)
+	}
 }
 
-func (b *Button) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+func (b *Button) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	fontSize := float32(b.FontSize())
 
 	ctx.SetFontSize(fontSize)
@@ -260,7 +296,7 @@ func (b *Button) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
 	return int(tw + iw + 20), int(fontSize) + 10
 }
 
-func (b *Button) Draw(self Widget, ctx *nanovgo.Context) {
+func (b *Button) Draw(self Widget, ctx DrawContext) {
 	b.WidgetImplement.Draw(self, ctx)
 
 	bx := float32(b.x)
@@ -383,6 +419,10 @@ func (b *Button) Draw(self Widget, ctx *nanovgo.Context) {
 	ctx.Text(textPosX, textPosY, caption)
 	ctx.SetFillColor(textColor)
 	ctx.Text(textPosX, textPosY+1.0, caption)
+
+	if b.focused {
+		drawFocusRing(ctx, b.theme, bx, by, bw, bh, float32(b.theme.ButtonCornerRadius))
+	}
 }
 
 func (b *Button) String() string {
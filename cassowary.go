@@ -0,0 +1,822 @@
+package nanogui
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Strengths of constraints. Non-required constraints are satisfied as well as possible,
+// and a stronger constraint always wins over any number of weaker ones
+const (
+	StrengthWeak     float64 = 1
+	StrengthMedium   float64 = 1e3
+	StrengthStrong   float64 = 1e6
+	StrengthRequired float64 = 1001001000
+)
+
+var (
+	// ErrUnsatisfiableConstraint is returned when a required constraint conflicts with the other required constraints
+	ErrUnsatisfiableConstraint = errors.New("nanogui: unsatisfiable constraint")
+	// ErrDuplicateConstraint is returned when a constraint is added twice
+	ErrDuplicateConstraint = errors.New("nanogui: duplicated constraint")
+	// ErrUnknownConstraint is returned when a constraint which wasn't added is removed
+	ErrUnknownConstraint = errors.New("nanogui: unknown constraint")
+	// ErrDuplicateEditVariable is returned when an edit variable is added twice
+	ErrDuplicateEditVariable = errors.New("nanogui: duplicated edit variable")
+	// ErrUnknownEditVariable is returned when a variable which isn't an edit variable is suggested or removed
+	ErrUnknownEditVariable = errors.New("nanogui: unknown edit variable")
+	// ErrRequiredEditVariable is returned when an edit variable is added with the required strength
+	ErrRequiredEditVariable = errors.New("nanogui: edit variable can't be required")
+)
+
+const cassowaryEpsilon = 1e-8
+
+func nearZero(value float64) bool {
+	return math.Abs(value) < cassowaryEpsilon
+}
+
+// ConstraintVariable is a variable of a linear constraint
+type ConstraintVariable struct {
+	name  string
+	value float64
+}
+
+func NewConstraintVariable(name string) *ConstraintVariable {
+	return &ConstraintVariable{name: name}
+}
+
+func (v *ConstraintVariable) Name() string {
+	return v.name
+}
+
+// Value() returns the value set by the last ConstraintSolver.UpdateVariables()
+func (v *ConstraintVariable) Value() float64 {
+	return v.value
+}
+
+// Expression() returns an expression which consists of the variable
+func (v *ConstraintVariable) Expression() Expression {
+	return Expression{terms: []constraintTerm{{variable: v, coefficient: 1}}}
+}
+
+func (v *ConstraintVariable) String() string {
+	return v.name
+}
+
+type constraintTerm struct {
+	variable    *ConstraintVariable
+	coefficient float64
+}
+
+// Expression is a linear expression of constraint variables. Expressions are immutable values
+type Expression struct {
+	terms    []constraintTerm
+	constant float64
+}
+
+// Constant() returns an expression of a constant value
+func Constant(value float64) Expression {
+	return Expression{constant: value}
+}
+
+// Plus() returns e + other
+func (e Expression) Plus(other Expression) Expression {
+	terms := make([]constraintTerm, 0, len(e.terms)+len(other.terms))
+	terms = append(append(terms, e.terms...), other.terms...)
+	return Expression{terms: terms, constant: e.constant + other.constant}
+}
+
+// Minus() returns e - other
+func (e Expression) Minus(other Expression) Expression {
+	return e.Plus(other.Times(-1))
+}
+
+// Add() returns e + value
+func (e Expression) Add(value float64) Expression {
+	return Expression{terms: e.terms, constant: e.constant + value}
+}
+
+// Times() returns e * value
+func (e Expression) Times(value float64) Expression {
+	terms := make([]constraintTerm, len(e.terms))
+	for i, term := range e.terms {
+		terms[i] = constraintTerm{variable: term.variable, coefficient: term.coefficient * value}
+	}
+	return Expression{terms: terms, constant: e.constant * value}
+}
+
+// Equal() returns the constraint e == rhs. The optional parameter is the strength (default: StrengthRequired)
+func (e Expression) Equal(rhs Expression, strength ...float64) *Constraint {
+	return NewConstraint(e, RelationEqual, rhs, strength...)
+}
+
+// LessOrEqual() returns the constraint e <= rhs. The optional parameter is the strength (default: StrengthRequired)
+func (e Expression) LessOrEqual(rhs Expression, strength ...float64) *Constraint {
+	return NewConstraint(e, RelationLessOrEqual, rhs, strength...)
+}
+
+// GreaterOrEqual() returns the constraint e >= rhs. The optional parameter is the strength (default: StrengthRequired)
+func (e Expression) GreaterOrEqual(rhs Expression, strength ...float64) *Constraint {
+	return NewConstraint(e, RelationGreaterOrEqual, rhs, strength...)
+}
+
+func (e Expression) String() string {
+	s := ""
+	for _, term := range e.terms {
+		if s != "" {
+			s += " + "
+		}
+		s += fmt.Sprintf("%g*%s", term.coefficient, term.variable.name)
+	}
+	if s == "" || e.constant != 0 {
+		if s != "" {
+			s += " + "
+		}
+		s += fmt.Sprintf("%g", e.constant)
+	}
+	return s
+}
+
+// Relation is the operator of a constraint
+type Relation int
+
+const (
+	RelationEqual Relation = iota
+	RelationLessOrEqual
+	RelationGreaterOrEqual
+)
+
+func (r Relation) String() string {
+	switch r {
+	case RelationEqual:
+		return "=="
+	case RelationLessOrEqual:
+		return "<="
+	case RelationGreaterOrEqual:
+		return ">="
+	}
+	panic("you should not reach here")
+	return ""
+}
+
+// Constraint is a linear equality or inequality with a strength
+type Constraint struct {
+	expression Expression // expression <relation> 0
+	relation   Relation
+	strength   float64
+}
+
+// NewConstraint() creates the constraint lhs <relation> rhs. The optional parameter is the strength (default: StrengthRequired)
+func NewConstraint(lhs Expression, relation Relation, rhs Expression, strength ...float64) *Constraint {
+	strengthParam := StrengthRequired
+	switch len(strength) {
+	case 0:
+	case 1:
+		strengthParam = math.Min(math.Max(strength[0], 0), StrengthRequired)
+	default:
+		panic("NewConstraint can accept only one extra parameter (strength)")
+	}
+	return &Constraint{
+		expression: lhs.Minus(rhs),
+		relation:   relation,
+		strength:   strengthParam,
+	}
+}
+
+func (c *Constraint) Relation() Relation {
+	return c.relation
+}
+
+func (c *Constraint) Strength() float64 {
+	return c.strength
+}
+
+func (c *Constraint) String() string {
+	return fmt.Sprintf("%s %s 0 (strength=%g)", c.expression, c.relation, c.strength)
+}
+
+type symbolType int
+
+const (
+	symbolInvalid symbolType = iota
+	symbolExternal
+	symbolSlack
+	symbolError
+	symbolDummy
+)
+
+type symbol struct {
+	id  uint64
+	typ symbolType
+}
+
+func (s symbol) valid() bool {
+	return s.typ != symbolInvalid
+}
+
+// symbolRow is a row of the simplex tableau: basic = constant + sum(cells)
+type symbolRow struct {
+	cells    map[symbol]float64
+	constant float64
+}
+
+func newSymbolRow(constant float64) *symbolRow {
+	return &symbolRow{cells: make(map[symbol]float64), constant: constant}
+}
+
+func (r *symbolRow) copy() *symbolRow {
+	row := newSymbolRow(r.constant)
+	for s, c := range r.cells {
+		row.cells[s] = c
+	}
+	return row
+}
+
+// symbols() returns the symbols of the row in the order they were created, which keeps the pivoting deterministic
+func (r *symbolRow) symbols() []symbol {
+	symbols := make([]symbol, 0, len(r.cells))
+	for s := range r.cells {
+		symbols = append(symbols, s)
+	}
+	sortSymbols(symbols)
+	return symbols
+}
+
+func (r *symbolRow) insertSymbol(s symbol, coefficient float64) {
+	value := r.cells[s] + coefficient
+	if nearZero(value) {
+		delete(r.cells, s)
+	} else {
+		r.cells[s] = value
+	}
+}
+
+func (r *symbolRow) insertRow(other *symbolRow, coefficient float64) {
+	r.constant += other.constant * coefficient
+	for s, c := range other.cells {
+		r.insertSymbol(s, c*coefficient)
+	}
+}
+
+func (r *symbolRow) reverseSign() {
+	r.constant = -r.constant
+	for s, c := range r.cells {
+		r.cells[s] = -c
+	}
+}
+
+// solveFor() solves the row for s, which is removed from the row
+func (r *symbolRow) solveFor(s symbol) {
+	coefficient := -1 / r.cells[s]
+	delete(r.cells, s)
+	r.constant *= coefficient
+	for k, c := range r.cells {
+		r.cells[k] = c * coefficient
+	}
+}
+
+// solveForPair() solves the row, whose basic symbol is lhs, for rhs
+func (r *symbolRow) solveForPair(lhs, rhs symbol) {
+	r.insertSymbol(lhs, -1)
+	r.solveFor(rhs)
+}
+
+// substitute() replaces s in the row with the expression of row
+func (r *symbolRow) substitute(s symbol, row *symbolRow) {
+	if c, ok := r.cells[s]; ok {
+		delete(r.cells, s)
+		r.insertRow(row, c)
+	}
+}
+
+type constraintTag struct {
+	marker symbol
+	other  symbol
+}
+
+type editInfo struct {
+	tag        constraintTag
+	constraint *Constraint
+	constant   float64
+}
+
+// ConstraintSolver is an incremental solver of linear constraints by the Cassowary algorithm
+//
+// Constraints and edit variables can be added and removed at any time; the solver keeps the
+// simplex tableau and only re-optimizes the part which changed.
+type ConstraintSolver struct {
+	constraints    map[*Constraint]constraintTag
+	rows           map[symbol]*symbolRow
+	vars           map[*ConstraintVariable]symbol
+	edits          map[*ConstraintVariable]*editInfo
+	infeasibleRows []symbol
+	objective      *symbolRow
+	artificial     *symbolRow
+	nextID         uint64
+}
+
+func NewConstraintSolver() *ConstraintSolver {
+	return &ConstraintSolver{
+		constraints: make(map[*Constraint]constraintTag),
+		rows:        make(map[symbol]*symbolRow),
+		vars:        make(map[*ConstraintVariable]symbol),
+		edits:       make(map[*ConstraintVariable]*editInfo),
+		objective:   newSymbolRow(0),
+	}
+}
+
+// AddConstraint() adds a constraint. It returns ErrUnsatisfiableConstraint when a required constraint can't be satisfied
+func (s *ConstraintSolver) AddConstraint(constraint *Constraint) error {
+	if _, ok := s.constraints[constraint]; ok {
+		return ErrDuplicateConstraint
+	}
+	row, tag := s.createRow(constraint)
+	subject := s.chooseSubject(row, tag)
+	if !subject.valid() && allDummies(row) {
+		if !nearZero(row.constant) {
+			return ErrUnsatisfiableConstraint
+		}
+		subject = tag.marker
+	}
+	if !subject.valid() {
+		if !s.addWithArtificialVariable(row) {
+			return ErrUnsatisfiableConstraint
+		}
+	} else {
+		row.solveFor(subject)
+		s.substitute(subject, row)
+		s.rows[subject] = row
+	}
+	s.constraints[constraint] = tag
+	return s.optimize(s.objective)
+}
+
+// RemoveConstraint() removes a constraint added by AddConstraint()
+func (s *ConstraintSolver) RemoveConstraint(constraint *Constraint) error {
+	tag, ok := s.constraints[constraint]
+	if !ok {
+		return ErrUnknownConstraint
+	}
+	delete(s.constraints, constraint)
+	s.removeConstraintEffects(constraint, tag)
+	if _, ok := s.rows[tag.marker]; ok {
+		delete(s.rows, tag.marker)
+	} else {
+		leaving, ok := s.markerLeavingSymbol(tag.marker)
+		if !ok {
+			return errors.New("nanogui: constraint solver failed to find a leaving row")
+		}
+		row := s.rows[leaving]
+		delete(s.rows, leaving)
+		row.solveForPair(leaving, tag.marker)
+		s.substitute(tag.marker, row)
+	}
+	return s.optimize(s.objective)
+}
+
+// HasConstraint() returns whether or not the constraint was added
+func (s *ConstraintSolver) HasConstraint(constraint *Constraint) bool {
+	_, ok := s.constraints[constraint]
+	return ok
+}
+
+// AddEditVariable() makes the variable editable with SuggestValue()
+func (s *ConstraintSolver) AddEditVariable(variable *ConstraintVariable, strength float64) error {
+	if _, ok := s.edits[variable]; ok {
+		return ErrDuplicateEditVariable
+	}
+	if strength >= StrengthRequired {
+		return ErrRequiredEditVariable
+	}
+	constraint := NewConstraint(variable.Expression(), RelationEqual, Constant(0), strength)
+	if err := s.AddConstraint(constraint); err != nil {
+		return err
+	}
+	s.edits[variable] = &editInfo{
+		tag:        s.constraints[constraint],
+		constraint: constraint,
+	}
+	return nil
+}
+
+// RemoveEditVariable() removes an edit variable added by AddEditVariable()
+func (s *ConstraintSolver) RemoveEditVariable(variable *ConstraintVariable) error {
+	info, ok := s.edits[variable]
+	if !ok {
+		return ErrUnknownEditVariable
+	}
+	delete(s.edits, variable)
+	return s.RemoveConstraint(info.constraint)
+}
+
+// HasEditVariable() returns whether or not the variable is an edit variable
+func (s *ConstraintSolver) HasEditVariable(variable *ConstraintVariable) bool {
+	_, ok := s.edits[variable]
+	return ok
+}
+
+// SuggestValue() suggests the value of an edit variable. The solution is updated by the dual simplex method
+func (s *ConstraintSolver) SuggestValue(variable *ConstraintVariable, value float64) error {
+	info, ok := s.edits[variable]
+	if !ok {
+		return ErrUnknownEditVariable
+	}
+	delta := value - info.constant
+	info.constant = value
+
+	if row, ok := s.rows[info.tag.marker]; ok {
+		row.constant -= delta
+		if row.constant < 0 {
+			s.infeasibleRows = append(s.infeasibleRows, info.tag.marker)
+		}
+		return s.dualOptimize()
+	}
+	if row, ok := s.rows[info.tag.other]; ok {
+		row.constant += delta
+		if row.constant < 0 {
+			s.infeasibleRows = append(s.infeasibleRows, info.tag.other)
+		}
+		return s.dualOptimize()
+	}
+	for _, basic := range s.sortedRows() {
+		row := s.rows[basic]
+		coefficient := row.cells[info.tag.marker]
+		if coefficient == 0 {
+			continue
+		}
+		row.constant += delta * coefficient
+		if row.constant < 0 && basic.typ != symbolExternal {
+			s.infeasibleRows = append(s.infeasibleRows, basic)
+		}
+	}
+	return s.dualOptimize()
+}
+
+// UpdateVariables() stores the current solution to the variables
+func (s *ConstraintSolver) UpdateVariables() {
+	for variable, sym := range s.vars {
+		if row, ok := s.rows[sym]; ok {
+			variable.value = row.constant
+		} else {
+			variable.value = 0
+		}
+	}
+}
+
+// IsDetermined() returns whether or not the constraints determine the value of the variable
+//
+// A variable isn't determined when it doesn't appear in any constraint, or when it can
+// change without violating any constraint or making the solution worse.
+func (s *ConstraintSolver) IsDetermined(variable *ConstraintVariable) bool {
+	sym, ok := s.vars[variable]
+	if !ok {
+		return false
+	}
+	row, ok := s.rows[sym]
+	if !ok {
+		return false
+	}
+	for _, parameter := range row.symbols() {
+		switch parameter.typ {
+		case symbolExternal:
+			return false
+		case symbolSlack, symbolError:
+			if nearZero(s.objective.cells[parameter]) && s.canIncrease(parameter) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// canIncrease() returns whether or not a parametric symbol can be increased without making a restricted basic symbol negative
+func (s *ConstraintSolver) canIncrease(parameter symbol) bool {
+	for basic, row := range s.rows {
+		if basic.typ == symbolExternal {
+			continue
+		}
+		if row.cells[parameter] < 0 && nearZero(row.constant) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *ConstraintSolver) newSymbol(typ symbolType) symbol {
+	s.nextID++
+	return symbol{id: s.nextID, typ: typ}
+}
+
+func (s *ConstraintSolver) variableSymbol(variable *ConstraintVariable) symbol {
+	if sym, ok := s.vars[variable]; ok {
+		return sym
+	}
+	sym := s.newSymbol(symbolExternal)
+	s.vars[variable] = sym
+	return sym
+}
+
+// createRow() creates the tableau row of a constraint. The row is expressed with the parametric symbols
+func (s *ConstraintSolver) createRow(constraint *Constraint) (*symbolRow, constraintTag) {
+	expression := constraint.expression
+	row := newSymbolRow(expression.constant)
+	for _, term := range expression.terms {
+		if nearZero(term.coefficient) {
+			continue
+		}
+		sym := s.variableSymbol(term.variable)
+		if other, ok := s.rows[sym]; ok {
+			row.insertRow(other, term.coefficient)
+		} else {
+			row.insertSymbol(sym, term.coefficient)
+		}
+	}
+
+	var tag constraintTag
+	switch constraint.relation {
+	case RelationLessOrEqual, RelationGreaterOrEqual:
+		coefficient := 1.0
+		if constraint.relation == RelationGreaterOrEqual {
+			coefficient = -1
+		}
+		slack := s.newSymbol(symbolSlack)
+		tag.marker = slack
+		row.insertSymbol(slack, coefficient)
+		if constraint.strength < StrengthRequired {
+			errorSymbol := s.newSymbol(symbolError)
+			tag.other = errorSymbol
+			row.insertSymbol(errorSymbol, -coefficient)
+			s.objective.insertSymbol(errorSymbol, constraint.strength)
+		}
+	case RelationEqual:
+		if constraint.strength < StrengthRequired {
+			errorPlus := s.newSymbol(symbolError)
+			errorMinus := s.newSymbol(symbolError)
+			tag.marker = errorPlus
+			tag.other = errorMinus
+			row.insertSymbol(errorPlus, -1)
+			row.insertSymbol(errorMinus, 1)
+			s.objective.insertSymbol(errorPlus, constraint.strength)
+			s.objective.insertSymbol(errorMinus, constraint.strength)
+		} else {
+			dummy := s.newSymbol(symbolDummy)
+			tag.marker = dummy
+			row.insertSymbol(dummy, 1)
+		}
+	}
+	if row.constant < 0 {
+		row.reverseSign()
+	}
+	return row, tag
+}
+
+// chooseSubject() chooses the symbol to solve the row for
+func (s *ConstraintSolver) chooseSubject(row *symbolRow, tag constraintTag) symbol {
+	for _, sym := range row.symbols() {
+		if sym.typ == symbolExternal {
+			return sym
+		}
+	}
+	if tag.marker.typ == symbolSlack || tag.marker.typ == symbolError {
+		if row.cells[tag.marker] < 0 {
+			return tag.marker
+		}
+	}
+	if tag.other.typ == symbolSlack || tag.other.typ == symbolError {
+		if row.cells[tag.other] < 0 {
+			return tag.other
+		}
+	}
+	return symbol{}
+}
+
+func allDummies(row *symbolRow) bool {
+	for sym := range row.cells {
+		if sym.typ != symbolDummy {
+			return false
+		}
+	}
+	return true
+}
+
+// addWithArtificialVariable() adds a row which has no valid subject by solving an artificial objective
+func (s *ConstraintSolver) addWithArtificialVariable(row *symbolRow) bool {
+	art := s.newSymbol(symbolSlack)
+	s.rows[art] = row.copy()
+	s.artificial = row.copy()
+	s.optimize(s.artificial)
+	success := nearZero(s.artificial.constant)
+	s.artificial = nil
+
+	if artRow, ok := s.rows[art]; ok {
+		delete(s.rows, art)
+		if len(artRow.cells) == 0 {
+			return success
+		}
+		entering := anyPivotableSymbol(artRow)
+		if !entering.valid() {
+			return false
+		}
+		artRow.solveForPair(art, entering)
+		s.substitute(entering, artRow)
+		s.rows[entering] = artRow
+	}
+	for _, row := range s.rows {
+		delete(row.cells, art)
+	}
+	delete(s.objective.cells, art)
+	return success
+}
+
+func anyPivotableSymbol(row *symbolRow) symbol {
+	for _, sym := range row.symbols() {
+		if sym.typ == symbolSlack || sym.typ == symbolError {
+			return sym
+		}
+	}
+	return symbol{}
+}
+
+// substitute() replaces the parametric symbol in the tableau and the objective with row
+func (s *ConstraintSolver) substitute(sym symbol, row *symbolRow) {
+	for _, basic := range s.sortedRows() {
+		other := s.rows[basic]
+		other.substitute(sym, row)
+		if basic.typ != symbolExternal && other.constant < 0 {
+			s.infeasibleRows = append(s.infeasibleRows, basic)
+		}
+	}
+	s.objective.substitute(sym, row)
+	if s.artificial != nil {
+		s.artificial.substitute(sym, row)
+	}
+}
+
+// optimize() runs the primal simplex method until the objective is minimized
+func (s *ConstraintSolver) optimize(objective *symbolRow) error {
+	for {
+		entering := enteringSymbol(objective)
+		if !entering.valid() {
+			return nil
+		}
+		leaving, ok := s.leavingSymbol(entering)
+		if !ok {
+			return errors.New("nanogui: constraint objective is unbounded")
+		}
+		row := s.rows[leaving]
+		delete(s.rows, leaving)
+		row.solveForPair(leaving, entering)
+		s.substitute(entering, row)
+		s.rows[entering] = row
+	}
+}
+
+// dualOptimize() restores the feasibility of the rows after an edit by the dual simplex method
+func (s *ConstraintSolver) dualOptimize() error {
+	for len(s.infeasibleRows) > 0 {
+		leaving := s.infeasibleRows[len(s.infeasibleRows)-1]
+		s.infeasibleRows = s.infeasibleRows[:len(s.infeasibleRows)-1]
+		row, ok := s.rows[leaving]
+		if !ok || row.constant >= 0 {
+			continue
+		}
+		entering := s.dualEnteringSymbol(row)
+		if !entering.valid() {
+			return errors.New("nanogui: constraint solver failed to find an entering symbol")
+		}
+		delete(s.rows, leaving)
+		row.solveForPair(leaving, entering)
+		s.substitute(entering, row)
+		s.rows[entering] = row
+	}
+	return nil
+}
+
+func enteringSymbol(objective *symbolRow) symbol {
+	for _, sym := range objective.symbols() {
+		if sym.typ != symbolDummy && objective.cells[sym] < 0 {
+			return sym
+		}
+	}
+	return symbol{}
+}
+
+func (s *ConstraintSolver) dualEnteringSymbol(row *symbolRow) symbol {
+	var entering symbol
+	ratio := math.MaxFloat64
+	for _, sym := range row.symbols() {
+		coefficient := row.cells[sym]
+		if coefficient > 0 && sym.typ != symbolDummy {
+			r := s.objective.cells[sym] / coefficient
+			if r < ratio {
+				ratio = r
+				entering = sym
+			}
+		}
+	}
+	return entering
+}
+
+// leavingSymbol() finds the row which limits the increase of the entering symbol most
+func (s *ConstraintSolver) leavingSymbol(entering symbol) (symbol, bool) {
+	ratio := math.MaxFloat64
+	var leaving symbol
+	found := false
+	for _, basic := range s.sortedRows() {
+		if basic.typ == symbolExternal {
+			continue
+		}
+		row := s.rows[basic]
+		coefficient := row.cells[entering]
+		if coefficient < 0 {
+			r := -row.constant / coefficient
+			if r < ratio {
+				ratio = r
+				leaving = basic
+				found = true
+			}
+		}
+	}
+	return leaving, found
+}
+
+// markerLeavingSymbol() finds the row to pivot out the marker of a removed constraint
+func (s *ConstraintSolver) markerLeavingSymbol(marker symbol) (symbol, bool) {
+	r1 := math.MaxFloat64
+	r2 := math.MaxFloat64
+	var first, second, third symbol
+	for _, basic := range s.sortedRows() {
+		row := s.rows[basic]
+		coefficient := row.cells[marker]
+		if coefficient == 0 {
+			continue
+		}
+		if basic.typ == symbolExternal {
+			third = basic
+		} else if coefficient < 0 {
+			if r := -row.constant / coefficient; r < r1 {
+				r1 = r
+				first = basic
+			}
+		} else {
+			if r := row.constant / coefficient; r < r2 {
+				r2 = r
+				second = basic
+			}
+		}
+	}
+	switch {
+	case first.valid():
+		return first, true
+	case second.valid():
+		return second, true
+	}
+	return third, third.valid()
+}
+
+// removeConstraintEffects() removes the error symbols of a constraint from the objective
+func (s *ConstraintSolver) removeConstraintEffects(constraint *Constraint, tag constraintTag) {
+	if tag.marker.typ == symbolError {
+		s.removeMarkerEffects(tag.marker, constraint.strength)
+	}
+	if tag.other.typ == symbolError {
+		s.removeMarkerEffects(tag.other, constraint.strength)
+	}
+}
+
+func (s *ConstraintSolver) removeMarkerEffects(marker symbol, strength float64) {
+	if row, ok := s.rows[marker]; ok {
+		s.objective.insertRow(row, -strength)
+	} else {
+		s.objective.insertSymbol(marker, -strength)
+	}
+}
+
+func (s *ConstraintSolver) sortedRows() []symbol {
+	symbols := make([]symbol, 0, len(s.rows))
+	for sym := range s.rows {
+		symbols = append(symbols, sym)
+	}
+	sortSymbols(symbols)
+	return symbols
+}
+
+type symbolsByID []symbol
+
+func (s symbolsByID) Len() int {
+	return len(s)
+}
+
+func (s symbolsByID) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s symbolsByID) Less(i, j int) bool {
+	return s[i].id < s[j].id
+}
+
+func sortSymbols(symbols []symbol) {
+	sort.Sort(symbolsByID(symbols))
+}
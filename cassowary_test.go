@@ -0,0 +1,54 @@
+package nanogui
+
+import "testing"
+
+func TestConstraintSolver(t *testing.T) {
+	solver := NewConstraintSolver()
+	x := NewConstraintVariable("x")
+	y := NewConstraintVariable("y")
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	must(solver.AddConstraint(x.Expression().GreaterOrEqual(Constant(10))))
+	must(solver.AddConstraint(y.Expression().Equal(x.Expression().Add(5))))
+	must(solver.AddConstraint(x.Expression().Equal(Constant(0), StrengthWeak)))
+	solver.UpdateVariables()
+	if x.Value() != 10 || y.Value() != 15 {
+		t.Fatalf("x, y = %v, %v, want 10, 15", x.Value(), y.Value())
+	}
+
+	must(solver.AddEditVariable(x, StrengthStrong))
+	must(solver.SuggestValue(x, 42))
+	solver.UpdateVariables()
+	if x.Value() != 42 || y.Value() != 47 {
+		t.Fatalf("suggesting 42: x, y = %v, %v, want 42, 47", x.Value(), y.Value())
+	}
+	must(solver.SuggestValue(x, 3))
+	solver.UpdateVariables()
+	if x.Value() != 10 {
+		t.Fatalf("a suggestion can't break a required constraint: x = %v, want 10", x.Value())
+	}
+	if err := solver.AddConstraint(y.Expression().LessOrEqual(Constant(12))); err != ErrUnsatisfiableConstraint {
+		t.Fatalf("conflicting required constraint: error %v, want ErrUnsatisfiableConstraint", err)
+	}
+	must(solver.RemoveEditVariable(x))
+
+	limit := y.Expression().LessOrEqual(Constant(100))
+	must(solver.AddConstraint(limit))
+	must(solver.RemoveConstraint(limit))
+	if err := solver.RemoveConstraint(limit); err != ErrUnknownConstraint {
+		t.Errorf("removing twice: error %v, want ErrUnknownConstraint", err)
+	}
+	solver.UpdateVariables()
+	if x.Value() != 10 || !solver.IsDetermined(x) {
+		t.Errorf("x = %v, determined %v, want 10 and determined", x.Value(), solver.IsDetermined(x))
+	}
+	z := NewConstraintVariable("z")
+	must(solver.AddConstraint(z.Expression().GreaterOrEqual(Constant(0))))
+	if solver.IsDetermined(z) {
+		t.Error("z has only an inequality and shouldn't be determined")
+	}
+}
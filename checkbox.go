@@ -21,6 +21,7 @@ func NewCheckBox(parent Widget, caption string) *CheckBox {
 		caption: caption,
 	}
 	InitWidget(checkBox, parent)
+	checkBox.SetTabStop(true)
 	return checkBox
 }
 
@@ -29,7 +30,10 @@ func (c *CheckBox) Caption() string {
 }
 
 func (c *CheckBox) SetCaption(caption string) {
-	c.caption = caption
+	if c.caption != caption {
+		c.caption = caption
+		c.InvalidateLayout()
+	}
 }
 
 func (c *CheckBox) Checked() bool {
@@ -74,7 +78,27 @@ func (c *CheckBox) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButt
 	return false
 }
 
-func (c *CheckBox) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+// KeyboardEvent() toggles the focused check box by Space or Enter
+func (c *CheckBox) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	if !c.enabled || !c.focused || !isActivationKey(key) {
+		return false
+	}
+	switch action {
+	case glfw.Press:
+		c.pushed = true
+	case glfw.Release:
+		if c.pushed {
+			c.checked = !c.checked
+			if c.callback != nil {
+				c.callback(c.checked)
+			}
+			c.pushed = false
+		}
+	}
+	return true
+}
+
+func (c *CheckBox) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	fw, fh := c.FixedSize()
 	if fw > 0 || fh > 0 {
 		return fw, fh
@@ -86,7 +110,7 @@ func (c *CheckBox) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
 	return int(w + 1.7*fontSize), int(fontSize * 1.3)
 }
 
-func (c *CheckBox) Draw(self Widget, ctx *nanovgo.Context) {
+func (c *CheckBox) Draw(self Widget, ctx DrawContext) {
 	cx := float32(c.x)
 	cy := float32(c.y)
 	ch := float32(c.h)
@@ -124,6 +148,10 @@ func (c *CheckBox) Draw(self Widget, ctx *nanovgo.Context) {
 		ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignMiddle)
 		ctx.Text(cx+ch*0.5+1.0, cy+ch*0.5, string([]rune{rune(IconCheck)}))
 	}
+
+	if c.focused {
+		drawFocusRing(ctx, c.theme, cx, cy, float32(c.w), ch, 3)
+	}
 }
 
 func (c *CheckBox) String() string {
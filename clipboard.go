@@ -0,0 +1,157 @@
+package nanogui
+
+import (
+	"github.com/shibukawa/glfw"
+)
+
+// ClipboardFormatText is the format of plain text clipboard data
+//
+// Applications can use their own MIME-like format names (e.g. "application/x-myapp-node")
+// to exchange structured data between their widgets.
+const ClipboardFormatText = "text/plain"
+
+// ClipboardData is a clipboard payload in one format
+type ClipboardData struct {
+	Format string
+	Data   []byte
+}
+
+// ClipboardText() returns a plain text clipboard payload
+func ClipboardText(text string) ClipboardData {
+	return ClipboardData{Format: ClipboardFormatText, Data: []byte(text)}
+}
+
+// Clipboard is the clipboard service of a Screen
+//
+// One copy operation can store the same content in several formats; paste
+// operations pick the richest format they understand.
+type Clipboard interface {
+	// SetData() replaces the clipboard content
+	SetData(items ...ClipboardData)
+	// Data() returns the content in the given format
+	Data(format string) ([]byte, bool)
+	// Formats() returns the available formats of the current content
+	Formats() []string
+}
+
+// SetClipboardText() replaces the clipboard content with plain text
+func SetClipboardText(clipboard Clipboard, text string) {
+	clipboard.SetData(ClipboardText(text))
+}
+
+// GetClipboardText() returns the plain text content of the clipboard
+func GetClipboardText(clipboard Clipboard) (string, bool) {
+	data, ok := clipboard.Data(ClipboardFormatText)
+	return string(data), ok
+}
+
+// GLFWClipboard is the default Clipboard of a Screen. Plain text goes through the system clipboard
+//
+// Other formats can't be stored by GLFW, so they are kept in process and
+// dropped as soon as another application changes the system clipboard.
+type GLFWClipboard struct {
+	window *glfw.Window
+	text   string
+	items  []ClipboardData
+}
+
+func NewGLFWClipboard(window *glfw.Window) *GLFWClipboard {
+	return &GLFWClipboard{
+		window: window,
+	}
+}
+
+func (c *GLFWClipboard) SetData(items ...ClipboardData) {
+	c.items = copyClipboardData(items)
+	c.text = ""
+	for _, item := range items {
+		if item.Format == ClipboardFormatText {
+			c.text = string(item.Data)
+			break
+		}
+	}
+	c.window.SetClipboardString(c.text)
+}
+
+func (c *GLFWClipboard) Data(format string) ([]byte, bool) {
+	text, err := c.window.GetClipboardString()
+	if format == ClipboardFormatText {
+		return []byte(text), err == nil && text != ""
+	}
+	if err != nil || text != c.text {
+		return nil, false
+	}
+	return findClipboardData(c.items, format)
+}
+
+func (c *GLFWClipboard) Formats() []string {
+	text, err := c.window.GetClipboardString()
+	if err != nil {
+		return nil
+	}
+	var formats []string
+	if text != "" {
+		formats = append(formats, ClipboardFormatText)
+	}
+	if text == c.text {
+		for _, item := range c.items {
+			if item.Format != ClipboardFormatText {
+				formats = append(formats, item.Format)
+			}
+		}
+	}
+	return formats
+}
+
+// MemoryClipboard is a Clipboard which keeps its content in memory. Headless screens use it
+type MemoryClipboard struct {
+	items []ClipboardData
+}
+
+func NewMemoryClipboard() *MemoryClipboard {
+	return &MemoryClipboard{}
+}
+
+func (c *MemoryClipboard) SetData(items ...ClipboardData) {
+	c.items = copyClipboardData(items)
+}
+
+func (c *MemoryClipboard) Data(format string) ([]byte, bool) {
+	return findClipboardData(c.items, format)
+}
+
+func (c *MemoryClipboard) Formats() []string {
+	var formats []string
+	for _, item := range c.items {
+		formats = append(formats, item.Format)
+	}
+	return formats
+}
+
+func copyClipboardData(items []ClipboardData) []ClipboardData {
+	result := make([]ClipboardData, len(items))
+	for i, item := range items {
+		result[i] = ClipboardData{
+			Format: item.Format,
+			Data:   append([]byte{}, item.Data...),
+		}
+	}
+	return result
+}
+
+func findClipboardData(items []ClipboardData, format string) ([]byte, bool) {
+	for _, item := range items {
+		if item.Format == format {
+			return append([]byte{}, item.Data...), true
+		}
+	}
+	return nil, false
+}
+
+// findClipboard() returns the clipboard of the screen which contains widget, or nil
+func findClipboard(widget Widget) Clipboard {
+	if screen := findScreen(widget); screen != nil {
+		return screen.Clipboard()
+	}
+	return nil
+}
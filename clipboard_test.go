@@ -0,0 +1,48 @@
+package nanogui
+
+import (
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+func TestMemoryClipboard(t *testing.T) {
+	clipboard := NewMemoryClipboard()
+	if _, ok := GetClipboardText(clipboard); ok {
+		t.Error("a new clipboard shouldn't have text")
+	}
+	payload := []byte{1, 2}
+	clipboard.SetData(ClipboardText("X"), ClipboardData{Format: "application/x-test", Data: payload})
+	payload[0] = 9
+	if formats := clipboard.Formats(); len(formats) != 2 {
+		t.Errorf("Formats() = %v, want two formats", formats)
+	}
+	if data, ok := clipboard.Data("application/x-test"); !ok || data[0] != 1 {
+		t.Errorf("Data() = %v, %v, want a copy of the stored bytes", data, ok)
+	}
+	if text, ok := GetClipboardText(clipboard); !ok || text != "X" {
+		t.Errorf("GetClipboardText() = %q, %v, want %q", text, ok, "X")
+	}
+}
+
+func TestTextBoxUsesScreenClipboard(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := NewTextBox(window, "hello")
+	textBox.SetEditable(true)
+	screen.PerformLayout()
+	screen.DrawAll()
+
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.PressKey(glfw.KeyA, glfw.ModControl)
+	in.PressKey(glfw.KeyC, glfw.ModControl)
+	if text, ok := GetClipboardText(screen.Clipboard()); !ok || text != "hello" {
+		t.Fatalf("Ctrl+C: clipboard has %q, %v, want %q", text, ok, "hello")
+	}
+	SetClipboardText(screen.Clipboard(), "X")
+	in.PressKey(glfw.KeyEnd, 0)
+	in.PressKey(glfw.KeyV, glfw.ModControl)
+	if got := string(textBox.valueTemp); got != "helloX" {
+		t.Errorf("Ctrl+V: %q, want %q", got, "helloX")
+	}
+}
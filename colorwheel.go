@@ -75,11 +75,11 @@ func (c *ColorWheel) MouseButtonEvent(self Widget, x, y int, button glfw.MouseBu
 	return true
 }
 
-func (c *ColorWheel) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+func (c *ColorWheel) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	return 100, 100
 }
 
-func (c *ColorWheel) Draw(self Widget, ctx *nanovgo.Context) {
+func (c *ColorWheel) Draw(self Widget, ctx DrawContext) {
 	c.WidgetImplement.Draw(self, ctx)
 
 	if !c.visible {
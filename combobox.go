@@ -1,5 +1,9 @@
 package nanogui
 
+import (
+	"github.com/shibukawa/glfw"
+)
+
 type ComboBox struct {
 	PopupButton
 	callback      func(int)
@@ -36,6 +40,7 @@ func NewComboBox(parent Widget, items ...[]string) *ComboBox {
 	combobox.popup = NewPopup(parentWindow.Parent(), parentWindow)
 	combobox.popup.SetSize(320, 250)
 	InitWidget(combobox, parent)
+	combobox.SetTabStop(true)
 	combobox.SetItems(itemsParam, shortItemsParam)
 	return combobox
 }
@@ -107,6 +112,36 @@ func (c *ComboBox) SetItems(items []string, shortItems ...[]string) {
 	c.SetSelectedIndex(c.selectedIndex)
 }
 
+// KeyboardEvent() opens the popup by Space or Enter and changes the selection by Up/Down and Home/End
+func (c *ComboBox) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	if !c.enabled || !c.focused {
+		return false
+	}
+	index := c.selectedIndex
+	switch key {
+	case glfw.KeyUp:
+		index--
+	case glfw.KeyDown:
+		index++
+	case glfw.KeyHome:
+		index = 0
+	case glfw.KeyEnd:
+		index = len(c.items) - 1
+	default:
+		return c.PopupButton.KeyboardEvent(self, key, scanCode, action, modifier)
+	}
+	if action != glfw.Release && len(c.items) > 0 {
+		index = clampI(index, 0, len(c.items)-1)
+		if index != c.selectedIndex {
+			c.SetSelectedIndex(index)
+			if c.callback != nil {
+				c.callback(index)
+			}
+		}
+	}
+	return true
+}
+
 func (c *ComboBox) Items() []string {
 	return c.items
 }
@@ -0,0 +1,493 @@
+package nanogui
+
+import (
+	"fmt"
+	"math"
+)
+
+// ConstraintAttribute is an edge, a size or a center of a widget in a ConstraintLayout
+type ConstraintAttribute int
+
+const (
+	AttributeLeft ConstraintAttribute = iota
+	AttributeTop
+	AttributeWidth
+	AttributeHeight
+	AttributeRight
+	AttributeBottom
+	AttributeCenterX
+	AttributeCenterY
+)
+
+func (a ConstraintAttribute) String() string {
+	switch a {
+	case AttributeLeft:
+		return "Left"
+	case AttributeTop:
+		return "Top"
+	case AttributeWidth:
+		return "Width"
+	case AttributeHeight:
+		return "Height"
+	case AttributeRight:
+		return "Right"
+	case AttributeBottom:
+		return "Bottom"
+	case AttributeCenterX:
+		return "CenterX"
+	case AttributeCenterY:
+		return "CenterY"
+	}
+	panic("you should not reach here")
+	return ""
+}
+
+// AmbiguousLayoutError is reported when the constraints of a ConstraintLayout don't determine the geometry of a widget
+type AmbiguousLayoutError struct {
+	Widget    Widget // nil for the container
+	Attribute ConstraintAttribute
+}
+
+func (e *AmbiguousLayoutError) Error() string {
+	if e.Widget == nil {
+		return fmt.Sprintf("nanogui: ambiguous constraints: %s of the container isn't determined", e.Attribute)
+	}
+	return fmt.Sprintf("nanogui: ambiguous constraints: %s of %s isn't determined", e.Attribute, e.Widget.String())
+}
+
+// ConstraintError is returned when a constraint of a ConstraintLayout can't be added to the solver
+//
+// Err is the error of the solver, e.g. ErrUnsatisfiableConstraint.
+type ConstraintError struct {
+	Constraint *Constraint
+	Err        error
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Err, e.Constraint)
+}
+
+// Unwrap() returns Err
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
+
+// constraintItem holds the variables of a widget (or the container) and its implicit constraints
+type constraintItem struct {
+	widget    Widget
+	variables [4]*ConstraintVariable // left, top, width, height
+	static    []*Constraint
+	size      [2][3]implicitConstraint // size, lower bound, upper bound
+}
+
+// implicitConstraint is a size constraint which the layout maintains for a widget
+type implicitConstraint struct {
+	constraint *Constraint
+	relation   Relation
+	value      float64
+	strength   float64
+}
+
+func (c *constraintItem) expression(attribute ConstraintAttribute) Expression {
+	switch attribute {
+	case AttributeRight:
+		return c.variables[0].Expression().Plus(c.variables[2].Expression())
+	case AttributeBottom:
+		return c.variables[1].Expression().Plus(c.variables[3].Expression())
+	case AttributeCenterX:
+		return c.variables[0].Expression().Plus(c.variables[2].Expression().Times(0.5))
+	case AttributeCenterY:
+		return c.variables[1].Expression().Plus(c.variables[3].Expression().Times(0.5))
+	}
+	return c.variables[attribute].Expression()
+}
+
+// Constraint based layout
+//
+// The geometry of the children is described by linear equalities and inequalities between
+// their edges, sizes and centers, and solved by the Cassowary algorithm:
+//
+//	layout := nanogui.NewConstraintLayout()
+//	layout.AddConstraints(
+//	    layout.Left(a).Equal(layout.Left(nil).Add(8)),
+//	    layout.Right(a).Add(8).Equal(layout.Left(b)),
+//	    layout.Width(a).Equal(layout.Width(b), nanogui.StrengthStrong),
+//	    ...
+//	)
+//
+// nil stands for the container; its area starts below the title of a window. The layout
+// adds its own constraints: the children stay inside the container, and their sizes equal
+// the fixed size (required) or the preferred size (StrengthMedium by default, StrengthStrong
+// for SizeFixed and StrengthWeak for SizeExpanding). The minimum/maximum size and the bounds
+// of the size policy are required.
+//
+// AddConstraint() reports a required constraint which conflicts with the others at once.
+// Constraints which leave the position or the size of a visible child open are reported by
+// Err() after layout (and by Validate()), as well as a size of a child which conflicts with
+// the required constraints (a *ConstraintError); the widgets aren't moved in that case.
+type ConstraintLayout struct {
+	solver       *ConstraintSolver
+	container    *constraintItem
+	items        map[Widget]*constraintItem
+	constraints  []*Constraint
+	sizeStrength float64
+	top          *Constraint
+	topValue     float64
+	err          error
+}
+
+func NewConstraintLayout() *ConstraintLayout {
+	c := &ConstraintLayout{
+		solver:       NewConstraintSolver(),
+		items:        make(map[Widget]*constraintItem),
+		sizeStrength: StrengthMedium,
+	}
+	c.container = newConstraintItem(nil, "container")
+	return c
+}
+
+func newConstraintItem(widget Widget, name string) *constraintItem {
+	item := &constraintItem{widget: widget}
+	for i := range item.variables {
+		item.variables[i] = NewConstraintVariable(name + "." + ConstraintAttribute(i).String())
+	}
+	return item
+}
+
+// Anchor() returns an expression of an attribute of the widget (nil: the container)
+func (c *ConstraintLayout) Anchor(widget Widget, attribute ConstraintAttribute) Expression {
+	return c.item(widget).expression(attribute)
+}
+
+func (c *ConstraintLayout) Left(widget Widget) Expression {
+	return c.Anchor(widget, AttributeLeft)
+}
+
+func (c *ConstraintLayout) Top(widget Widget) Expression {
+	return c.Anchor(widget, AttributeTop)
+}
+
+func (c *ConstraintLayout) Right(widget Widget) Expression {
+	return c.Anchor(widget, AttributeRight)
+}
+
+func (c *ConstraintLayout) Bottom(widget Widget) Expression {
+	return c.Anchor(widget, AttributeBottom)
+}
+
+func (c *ConstraintLayout) Width(widget Widget) Expression {
+	return c.Anchor(widget, AttributeWidth)
+}
+
+func (c *ConstraintLayout) Height(widget Widget) Expression {
+	return c.Anchor(widget, AttributeHeight)
+}
+
+func (c *ConstraintLayout) CenterX(widget Widget) Expression {
+	return c.Anchor(widget, AttributeCenterX)
+}
+
+func (c *ConstraintLayout) CenterY(widget Widget) Expression {
+	return c.Anchor(widget, AttributeCenterY)
+}
+
+// AddConstraint() adds a constraint. It returns ErrUnsatisfiableConstraint when a required constraint conflicts with the others
+func (c *ConstraintLayout) AddConstraint(constraint *Constraint) error {
+	if err := c.solver.AddConstraint(constraint); err != nil {
+		return err
+	}
+	c.constraints = append(c.constraints, constraint)
+	return nil
+}
+
+// AddConstraints() adds constraints until one of them fails. The error is a *ConstraintError with the failed constraint
+func (c *ConstraintLayout) AddConstraints(constraints ...*Constraint) error {
+	for _, constraint := range constraints {
+		if err := c.AddConstraint(constraint); err != nil {
+			return &ConstraintError{Constraint: constraint, Err: err}
+		}
+	}
+	return nil
+}
+
+func (c *ConstraintLayout) RemoveConstraint(constraint *Constraint) error {
+	if err := c.solver.RemoveConstraint(constraint); err != nil {
+		return err
+	}
+	for i, other := range c.constraints {
+		if other == constraint {
+			c.constraints = append(c.constraints[:i], c.constraints[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Constraints() returns the constraints added by AddConstraint()
+func (c *ConstraintLayout) Constraints() []*Constraint {
+	return c.constraints
+}
+
+// SizeStrength() returns the strength which keeps the children at their preferred size
+func (c *ConstraintLayout) SizeStrength() float64 {
+	return c.sizeStrength
+}
+
+// SetSizeStrength() sets the strength which keeps the children at their preferred size (default: StrengthMedium)
+func (c *ConstraintLayout) SetSizeStrength(strength float64) {
+	c.sizeStrength = math.Min(strength, StrengthStrong)
+}
+
+// Err() returns the error of the last layout, or nil
+func (c *ConstraintLayout) Err() error {
+	return c.err
+}
+
+// Validate() solves the constraints for the current size of the widget and returns the error, or nil
+func (c *ConstraintLayout) Validate(widget Widget, ctx DrawContext) error {
+	_, err := c.solve(widget, ctx, true)
+	return err
+}
+
+func (c *ConstraintLayout) OnPerformLayout(widget Widget, ctx DrawContext) {
+	children, err := c.solve(widget, ctx, true)
+	c.err = err
+	for _, child := range children {
+		if err == nil {
+			v := c.items[child].variables
+			child.SetPosition(roundInt(v[0].Value()), roundInt(v[1].Value()))
+			child.SetSize(roundInt(v[2].Value()), roundInt(v[3].Value()))
+		}
+		child.LayoutIfNeeded(child, ctx)
+	}
+}
+
+func (c *ConstraintLayout) PreferredSize(widget Widget, ctx DrawContext) (int, int) {
+	_, err := c.solve(widget, ctx, false)
+	c.err = err
+	if err != nil {
+		return widget.Size()
+	}
+	v := c.container.variables
+	return roundInt(v[2].Value()), roundInt(v[3].Value()) + c.headerHeight(widget)
+}
+
+func (c *ConstraintLayout) String() string {
+	return fmt.Sprintf("ConstraintLayout[%d]", len(c.constraints))
+}
+
+func (c *ConstraintLayout) item(widget Widget) *constraintItem {
+	if widget == nil {
+		return c.container
+	}
+	item, ok := c.items[widget]
+	if !ok {
+		name := widget.ID()
+		if name == "" {
+			name = fmt.Sprintf("%s%d", WidgetTypeName(widget), len(c.items))
+		}
+		item = newConstraintItem(widget, name)
+		c.items[widget] = item
+	}
+	return item
+}
+
+func (c *ConstraintLayout) headerHeight(widget Widget) int {
+	if window, ok := widget.(*Window); ok && window.Title() != "" {
+		return widget.Theme().WindowHeaderHeight
+	}
+	return 0
+}
+
+// setSize() replaces an implicit size constraint of an item when its relation, value or strength changed
+func (c *ConstraintLayout) setSize(item *constraintItem, axis, slot int, relation Relation, value, strength float64) error {
+	current := &item.size[axis][slot]
+	if current.constraint != nil && current.relation == relation && current.value == value && current.strength == strength {
+		return nil
+	}
+	c.clearSize(item, axis, slot)
+	constraint := NewConstraint(item.variables[2+axis].Expression(), relation, Constant(value), strength)
+	if err := c.solver.AddConstraint(constraint); err != nil {
+		return &ConstraintError{Constraint: constraint, Err: err}
+	}
+	*current = implicitConstraint{constraint: constraint, relation: relation, value: value, strength: strength}
+	return nil
+}
+
+// clearSize() removes an implicit size constraint of an item
+func (c *ConstraintLayout) clearSize(item *constraintItem, axis, slot int) {
+	if item.size[axis][slot].constraint != nil {
+		c.solver.RemoveConstraint(item.size[axis][slot].constraint)
+	}
+	item.size[axis][slot] = implicitConstraint{}
+}
+
+// setChildSize() updates the implicit size constraints of a child on an axis
+func (c *ConstraintLayout) setChildSize(child Widget, item *constraintItem, axis int, ctx DrawContext) error {
+	lW, lH := LayoutSize(child, ctx)
+	fW, fH := child.FixedSize()
+	minW, minH := child.MinimumSize()
+	maxW, maxH := child.MaximumSize()
+	hPolicy, vPolicy := child.SizePolicy()
+	size := float64(toI(axis == 0, lW, lH))
+	lower := float64(toI(axis == 0, minW, minH))
+	upper := float64(toI(axis == 0, maxW, maxH))
+	policy := toPolicy(axis == 0, hPolicy, vPolicy)
+
+	if toI(axis == 0, fW, fH) > 0 || policy == SizeFixed {
+		c.clearSize(item, axis, 1)
+		c.clearSize(item, axis, 2)
+		return c.setSize(item, axis, 0, RelationEqual, size, toF64(policy == SizeFixed, StrengthStrong, StrengthRequired))
+	}
+	strength := c.sizeStrength
+	if policy == SizeExpanding {
+		strength = StrengthWeak
+	}
+	if err := c.setSize(item, axis, 0, RelationEqual, size, strength); err != nil {
+		return err
+	}
+	if !policy.CanShrink() {
+		lower = size
+	}
+	if !policy.CanGrow() {
+		upper = size
+	}
+	for slot, bound := range [2]struct {
+		relation Relation
+		value    float64
+	}{{RelationGreaterOrEqual, lower}, {RelationLessOrEqual, upper}} {
+		if bound.value <= 0 {
+			c.clearSize(item, axis, slot+1)
+		} else if err := c.setSize(item, axis, slot+1, bound.relation, bound.value, StrengthRequired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// solve() updates the implicit constraints and solves the layout
+//
+// When fit is true, the container has the size of widget; otherwise the container shrinks
+// to the smallest size which satisfies the constraints (the preferred size).
+func (c *ConstraintLayout) solve(widget Widget, ctx DrawContext, fit bool) ([]Widget, error) {
+	var children []Widget
+	current := make(map[Widget]bool)
+	for _, child := range widget.Children() {
+		current[child] = true
+		if child.Visible() {
+			children = append(children, child)
+		}
+	}
+	// the implicit constraints of removed children are dropped; the variables are kept for the user's constraints
+	for child, item := range c.items {
+		if current[child] || item.static == nil {
+			continue
+		}
+		for _, constraint := range item.static {
+			c.solver.RemoveConstraint(constraint)
+		}
+		item.static = nil
+		for axis := 0; axis < 2; axis++ {
+			for slot := range item.size[axis] {
+				c.clearSize(item, axis, slot)
+			}
+		}
+	}
+
+	container := c.container
+	if container.static == nil {
+		container.static = []*Constraint{
+			container.variables[0].Expression().Equal(Constant(0)),
+			container.variables[2].Expression().GreaterOrEqual(Constant(0)),
+			container.variables[3].Expression().GreaterOrEqual(Constant(0)),
+		}
+		for _, constraint := range container.static {
+			if err := c.solver.AddConstraint(constraint); err != nil {
+				return nil, &ConstraintError{Constraint: constraint, Err: err}
+			}
+		}
+	}
+	if err := c.setContainerTop(float64(c.headerHeight(widget))); err != nil {
+		return nil, err
+	}
+	if fit {
+		fW, fH := widget.FixedSize()
+		width := toI(fW > 0, fW, widget.Width())
+		height := toI(fH > 0, fH, widget.Height()) - c.headerHeight(widget)
+		if err := c.setSize(container, 0, 0, RelationEqual, float64(width), StrengthStrong); err != nil {
+			return nil, err
+		}
+		if err := c.setSize(container, 1, 0, RelationEqual, float64(maxI(height, 0)), StrengthStrong); err != nil {
+			return nil, err
+		}
+	} else {
+		for axis := 0; axis < 2; axis++ {
+			if err := c.setSize(container, axis, 0, RelationEqual, 0, StrengthWeak); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, child := range children {
+		item := c.item(child)
+		if item.static == nil {
+			item.static = []*Constraint{
+				item.variables[2].Expression().GreaterOrEqual(Constant(0)),
+				item.variables[3].Expression().GreaterOrEqual(Constant(0)),
+				c.Left(child).GreaterOrEqual(c.Left(nil)),
+				c.Top(child).GreaterOrEqual(c.Top(nil)),
+				c.Right(child).LessOrEqual(c.Right(nil)),
+				c.Bottom(child).LessOrEqual(c.Bottom(nil)),
+			}
+			for _, constraint := range item.static {
+				if err := c.solver.AddConstraint(constraint); err != nil {
+					return nil, &ConstraintError{Constraint: constraint, Err: err}
+				}
+			}
+		}
+		for axis := 0; axis < 2; axis++ {
+			if err := c.setChildSize(child, item, axis, ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+	c.solver.UpdateVariables()
+
+	for _, child := range children {
+		for i, variable := range c.items[child].variables {
+			if !c.solver.IsDetermined(variable) {
+				return children, &AmbiguousLayoutError{Widget: child, Attribute: ConstraintAttribute(i)}
+			}
+		}
+	}
+	if !fit {
+		for i, variable := range container.variables[2:] {
+			if !c.solver.IsDetermined(variable) {
+				return children, &AmbiguousLayoutError{Attribute: ConstraintAttribute(i + 2)}
+			}
+		}
+	}
+	return children, nil
+}
+
+// setContainerTop() pins the top of the container below the title of a window
+func (c *ConstraintLayout) setContainerTop(top float64) error {
+	if c.top != nil {
+		if c.topValue == top {
+			return nil
+		}
+		c.solver.RemoveConstraint(c.top)
+		c.top = nil
+	}
+	constraint := c.container.variables[1].Expression().Equal(Constant(top))
+	if err := c.solver.AddConstraint(constraint); err != nil {
+		return &ConstraintError{Constraint: constraint, Err: err}
+	}
+	c.top = constraint
+	c.topValue = top
+	return nil
+}
+
+func roundInt(value float64) int {
+	return int(math.Floor(value + 0.5))
+}
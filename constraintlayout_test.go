@@ -0,0 +1,139 @@
+package nanogui
+
+import "testing"
+
+func TestConstraintLayout(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	layout := NewConstraintLayout()
+	panel.SetLayout(layout)
+	a := newSized(panel, 50, 20)
+	b := newSized(panel, 80, 30)
+	err := layout.AddConstraints(
+		layout.Left(a).Equal(layout.Left(nil).Add(8)),
+		layout.Top(a).Equal(layout.Top(nil).Add(8)),
+		layout.Right(a).Add(8).Equal(layout.Left(b)),
+		layout.Top(b).Equal(layout.Top(a)),
+		layout.Right(b).Add(8).Equal(layout.Right(nil)),
+		layout.Bottom(b).Add(8).Equal(layout.Bottom(nil), StrengthStrong),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := screen.Context()
+	if w, h := layout.PreferredSize(panel, ctx); layout.Err() != nil || w != 154 || h != 46 {
+		t.Fatalf("PreferredSize() = %d, %d (error %v), want 154, 46", w, h, layout.Err())
+	}
+
+	panel.SetSize(300, 100)
+	layout.OnPerformLayout(panel, ctx)
+	if e, ok := layout.Err().(*AmbiguousLayoutError); !ok || e.Attribute != AttributeWidth {
+		t.Fatalf("the extra width can go to either widget: Err() = %v, want an ambiguous width", layout.Err())
+	}
+	if err := layout.AddConstraint(layout.Width(a).Equal(layout.Width(b), StrengthStrong)); err != nil {
+		t.Fatal(err)
+	}
+	layout.OnPerformLayout(panel, ctx)
+	if a.Width() != 138 || b.Width() != 138 {
+		t.Fatalf("equal widths: %d, %d, want 138, 138", a.Width(), b.Width())
+	}
+}
+
+func TestConstraintLayoutErrors(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	layout := NewConstraintLayout()
+	panel.SetLayout(layout)
+	a := newSized(panel, 50, 20)
+	err := layout.AddConstraints(
+		layout.Left(a).Equal(layout.Left(nil)),
+		layout.Top(a).Equal(layout.Top(nil)),
+		layout.Width(a).Equal(Constant(10)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := layout.AddConstraint(layout.Width(a).Equal(Constant(20))); err != ErrUnsatisfiableConstraint {
+		t.Fatalf("conflicting width: error %v, want ErrUnsatisfiableConstraint", err)
+	}
+	conflicting := layout.Width(a).Equal(Constant(30))
+	err = layout.AddConstraints(conflicting)
+	if e, ok := err.(*ConstraintError); !ok || e.Err != ErrUnsatisfiableConstraint || e.Constraint != conflicting {
+		t.Fatalf("AddConstraints() with a conflicting width: error %v, want a ConstraintError with ErrUnsatisfiableConstraint", err)
+	}
+
+	ctx := screen.Context()
+	panel.SetSize(300, 100)
+	unconstrained := newSized(panel, 10, 10)
+	layout.OnPerformLayout(panel, ctx)
+	if e, ok := layout.Err().(*AmbiguousLayoutError); !ok || e.Widget != unconstrained {
+		t.Fatalf("a widget without position constraints: Err() = %v", layout.Err())
+	}
+	layout.AddConstraints(layout.Left(unconstrained).Equal(layout.Left(nil)), layout.Top(unconstrained).Equal(layout.Top(nil)))
+	layout.OnPerformLayout(panel, ctx)
+	if layout.Err() != nil || a.Width() != 10 {
+		t.Fatalf("Err() = %v, width %d, want no error and width 10", layout.Err(), a.Width())
+	}
+	panel.RemoveChild(unconstrained)
+	layout.OnPerformLayout(panel, ctx)
+	if layout.Err() != nil {
+		t.Errorf("removing a widget should drop its constraints: Err() = %v", layout.Err())
+	}
+	a.SetFixedSize(40, 0)
+	layout.OnPerformLayout(panel, ctx)
+	if e, ok := layout.Err().(*ConstraintError); !ok || e.Err != ErrUnsatisfiableConstraint {
+		t.Errorf("a fixed width which conflicts with the constraints: Err() = %v, want a ConstraintError with ErrUnsatisfiableConstraint", layout.Err())
+	}
+}
+
+func TestConstraintLayoutExport(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	layout := NewConstraintLayout()
+	layout.SetSizeStrength(StrengthWeak)
+	panel.SetLayout(layout)
+	a := NewWidget(panel)
+	a.SetFixedSize(50, 20)
+	b := NewWidget(panel)
+	b.SetFixedSize(80, 30)
+	err := layout.AddConstraints(
+		layout.Left(a).Equal(layout.Left(nil).Add(8)),
+		layout.Top(a).Equal(layout.Top(nil).Add(8)),
+		layout.Right(a).Add(8).Equal(layout.Left(b)),
+		layout.CenterY(b).Equal(layout.CenterY(a)),
+		layout.Right(b).Add(8).Equal(layout.Right(nil), StrengthStrong),
+		layout.Bottom(b).Add(8).LessOrEqual(layout.Bottom(nil)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	screen.PerformLayout()
+
+	doc, err := ExportUI(panel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loadedScreen := NewHeadlessScreen(800, 600)
+	ui, err := BuildUI(loadedScreen, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := ui.Roots()[0]
+	loadedLayout := loaded.Layout().(*ConstraintLayout)
+	if len(loadedLayout.Constraints()) != 6 || loadedLayout.SizeStrength() != StrengthWeak {
+		t.Fatalf("%d constraints, size strength %g, want 6 and %g", len(loadedLayout.Constraints()), loadedLayout.SizeStrength(), StrengthWeak)
+	}
+	loadedScreen.PerformLayout()
+	for i, child := range panel.Children() {
+		x, y := child.Position()
+		loadedX, loadedY := loaded.Children()[i].Position()
+		if x != loadedX || y != loadedY {
+			t.Errorf("child %d: position %d, %d, want %d, %d", i, loadedX, loadedY, x, y)
+		}
+	}
+
+	doc.Widgets[0].Layout.Constraints[0].Relation = "=<"
+	if _, err := BuildUI(NewHeadlessScreen(800, 600), doc); err == nil {
+		t.Error("an unknown relation should be an error")
+	}
+}
@@ -0,0 +1,60 @@
+package nanogui
+
+import (
+	"github.com/shibukawa/nanovgo"
+)
+
+// DrawContext is the drawing and text measuring API used by widgets and layouts
+//
+// *nanovgo.Context implements it and is used by screens backed by a GLFW window.
+// Screens created by NewHeadlessScreen() use a RecordingContext instead, so the
+// same widget code can run without an OpenGL context.
+type DrawContext interface {
+	BeginFrame(windowWidth, windowHeight int, devicePixelRatio float32)
+	EndFrame()
+
+	Save()
+	Restore()
+
+	SetStrokeWidth(width float32)
+	SetStrokeColor(color nanovgo.Color)
+	SetStrokePaint(paint nanovgo.Paint)
+	SetFillColor(color nanovgo.Color)
+	SetFillPaint(paint nanovgo.Paint)
+	SetGlobalAlpha(alpha float32)
+
+	Translate(x, y float32)
+	Rotate(angle float32)
+	SkewX(angle float32)
+
+	Scissor(x, y, w, h float32)
+	ResetScissor()
+
+	BeginPath()
+	MoveTo(x, y float32)
+	LineTo(x, y float32)
+	ClosePath()
+	PathWinding(winding nanovgo.Winding)
+	Arc(cx, cy, r, a0, a1 float32, dir nanovgo.Direction)
+	Rect(x, y, w, h float32)
+	RoundedRect(x, y, w, h, r float32)
+	Circle(cx, cy, r float32)
+	Fill()
+	Stroke()
+
+	ImageSize(image int) (int, int, error)
+	CreateFontFromMemory(name string, data []byte, freeData uint8) int
+
+	SetFontSize(size float32)
+	SetFontBlur(blur float32)
+	SetTextLineHeight(lineHeight float32)
+	SetTextAlign(align nanovgo.Align)
+	SetFontFace(font string)
+	Text(x, y float32, str string) float32
+	TextRune(x, y float32, runes []rune) float32
+	TextBox(x, y, breakRowWidth float32, str string)
+	TextBounds(x, y float32, str string) (float32, []float32)
+	TextBoxBounds(x, y, breakRowWidth float32, str string) [4]float32
+	TextGlyphPositionsRune(x, y float32, runes []rune) []nanovgo.GlyphPosition
+	TextMetrics() (float32, float32, float32)
+}
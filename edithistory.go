@@ -0,0 +1,134 @@
+package nanogui
+
+import (
+	"unicode"
+)
+
+// DefaultEditHistoryLimit is the default number of undo steps kept by text editing widgets
+const DefaultEditHistoryLimit = 100
+
+type editKind int
+
+const (
+	editKindNone editKind = iota
+	editKindTyping
+	editKindOther
+)
+
+// editState is a snapshot of an editing text
+type editState struct {
+	text      []rune
+	cursor    int
+	selection int
+}
+
+// editHistory is an undo/redo stack of editState
+//
+// Consecutive typing is coalesced into word-sized steps: a new step starts when
+// the cursor was moved, a selection was replaced or a new word starts.
+type editHistory struct {
+	undo, redo []editState
+	limit      int
+	lastKind   editKind
+	lastCursor int
+}
+
+func newEditState(text []rune, cursor, selection int) editState {
+	return editState{
+		text:      append([]rune{}, text...),
+		cursor:    cursor,
+		selection: selection,
+	}
+}
+
+// record() stores the state before an edit. Edits which don't change the text are ignored
+func (h *editHistory) record(before editState, after []rune, afterCursor int, kind editKind) {
+	if runesEqual(before.text, after) {
+		return
+	}
+	if !h.coalesce(before, after, afterCursor, kind) {
+		h.undo = append(h.undo, before)
+		if h.limit > 0 && len(h.undo) > h.limit {
+			h.undo = h.undo[len(h.undo)-h.limit:]
+		}
+	}
+	h.redo = nil
+	h.lastKind = kind
+	h.lastCursor = afterCursor
+}
+
+func (h *editHistory) coalesce(before editState, after []rune, afterCursor int, kind editKind) bool {
+	if kind != editKindTyping || h.lastKind != editKindTyping || len(h.undo) == 0 {
+		return false
+	}
+	if before.selection != -1 || before.cursor != h.lastCursor || before.cursor == 0 || afterCursor < 1 {
+		return false
+	}
+	// a new word starts
+	if unicode.IsSpace(before.text[before.cursor-1]) && !unicode.IsSpace(after[afterCursor-1]) {
+		return false
+	}
+	return true
+}
+
+// breakCoalescing() makes the next typing start a new undo step
+func (h *editHistory) breakCoalescing() {
+	h.lastKind = editKindNone
+}
+
+func (h *editHistory) canUndo() bool {
+	return len(h.undo) > 0
+}
+
+func (h *editHistory) canRedo() bool {
+	return len(h.redo) > 0
+}
+
+// undoStep() returns the state to restore and saves current for redo
+func (h *editHistory) undoStep(current editState) (editState, bool) {
+	if len(h.undo) == 0 {
+		return editState{}, false
+	}
+	state := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, current)
+	h.lastKind = editKindNone
+	return state, true
+}
+
+// redoStep() returns the state to restore and saves current for undo
+func (h *editHistory) redoStep(current editState) (editState, bool) {
+	if len(h.redo) == 0 {
+		return editState{}, false
+	}
+	state := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, current)
+	h.lastKind = editKindNone
+	return state, true
+}
+
+func (h *editHistory) clear() {
+	h.undo = nil
+	h.redo = nil
+	h.lastKind = editKindNone
+}
+
+func (h *editHistory) setLimit(limit int) {
+	h.limit = limit
+	if limit > 0 && len(h.undo) > limit {
+		h.undo = h.undo[len(h.undo)-limit:]
+	}
+}
+
+func runesEqual(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
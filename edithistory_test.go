@@ -0,0 +1,68 @@
+package nanogui
+
+import (
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+func TestTextBoxUndoRedo(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := NewTextBox(window, "")
+	textBox.SetEditable(true)
+	screen.PerformLayout()
+	screen.DrawAll()
+
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.TypeText("hello world foo")
+	expect := func(step, want string) {
+		t.Helper()
+		if got := string(textBox.valueTemp); got != want {
+			t.Fatalf("after %s: %q, want %q", step, got, want)
+		}
+	}
+	expect("typing", "hello world foo")
+	in.PressKey(glfw.KeyZ, glfw.ModControl)
+	expect("undo", "hello world ")
+	in.PressKey(glfw.KeyZ, glfw.ModControl)
+	expect("second undo", "hello ")
+	in.PressKey(glfw.KeyZ, glfw.ModControl|glfw.ModShift)
+	expect("redo", "hello world ")
+
+	in.PressKey(glfw.KeyBackspace, 0)
+	if textBox.CanRedo() {
+		t.Fatal("a new edit should drop the redo history")
+	}
+	in.PressKey(glfw.KeyZ, glfw.ModControl)
+	expect("undoing the deletion", "hello world ")
+	if textBox.cursorPos != 12 {
+		t.Errorf("undo should restore the cursor: %d, want 12", textBox.cursorPos)
+	}
+
+	textBox.SetHistoryLimit(1)
+	if !textBox.Undo() || textBox.Undo() {
+		t.Error("SetHistoryLimit(1) should keep only one undo step")
+	}
+	textBox.ClearHistory()
+	if textBox.CanUndo() || textBox.CanRedo() {
+		t.Error("ClearHistory() should drop all steps")
+	}
+}
+
+func TestTextAreaUndo(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textArea := NewTextArea(window, "")
+	textArea.SetEditable(true)
+	screen.PerformLayout()
+
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.TypeText("ab cd")
+	in.PressKey(glfw.KeyEnter, 0)
+	in.PressKey(glfw.KeyZ, glfw.ModControl)
+	in.PressKey(glfw.KeyZ, glfw.ModControl)
+	if got := string(textArea.valueTemp); got != "ab " {
+		t.Errorf("two undo steps: %q, want %q", got, "ab ")
+	}
+}
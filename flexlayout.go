@@ -0,0 +1,513 @@
+package nanogui
+
+import (
+	"fmt"
+)
+
+// FlexJustify specifies how a FlexLayout distributes the free space on the main axis
+type FlexJustify int
+
+const (
+	JustifyStart FlexJustify = iota
+	JustifyCenter
+	JustifyEnd
+	JustifySpaceBetween
+	JustifySpaceAround
+)
+
+func (j FlexJustify) String() string {
+	switch j {
+	case JustifyStart:
+		return "Start"
+	case JustifyCenter:
+		return "Center"
+	case JustifyEnd:
+		return "End"
+	case JustifySpaceBetween:
+		return "SpaceBetween"
+	case JustifySpaceAround:
+		return "SpaceAround"
+	}
+	panic("you should not reach here")
+	return ""
+}
+
+// FlexItem is the setting of a child widget of a FlexLayout
+type FlexItem struct {
+	grow, shrink float32
+	basis        int
+	min, max     int
+	align        Alignment
+	alignSelf    bool
+}
+
+// NewFlexItem() creates a flex item setting
+//
+// grow and shrink are the shares of the free space the widget takes or gives up on the
+// main axis. The optional parameters are the basis, the minimum and the maximum size on
+// the main axis. A negative basis (default) starts from the preferred size and a bound of 0
+// means no limit.
+func NewFlexItem(grow, shrink float32, setting ...int) FlexItem {
+	item := FlexItem{
+		grow:   grow,
+		shrink: shrink,
+		basis:  -1,
+	}
+	switch len(setting) {
+	case 0:
+	case 1:
+		item.basis = setting[0]
+	case 2:
+		item.basis = setting[0]
+		item.min = setting[1]
+	case 3:
+		item.basis = setting[0]
+		item.min = setting[1]
+		item.max = setting[2]
+	default:
+		panic("NewFlexItem can accept extra parameter upto 3 (basis, min, max).")
+	}
+	return item
+}
+
+func (f FlexItem) Grow() float32 {
+	return f.grow
+}
+
+func (f *FlexItem) SetGrow(grow float32) {
+	f.grow = grow
+}
+
+func (f FlexItem) Shrink() float32 {
+	return f.shrink
+}
+
+func (f *FlexItem) SetShrink(shrink float32) {
+	f.shrink = shrink
+}
+
+func (f FlexItem) Basis() int {
+	return f.basis
+}
+
+func (f *FlexItem) SetBasis(basis int) {
+	f.basis = basis
+}
+
+func (f FlexItem) Min() int {
+	return f.min
+}
+
+func (f FlexItem) Max() int {
+	return f.max
+}
+
+// SetBounds() sets the minimum and the maximum size on the main axis (0: no limit)
+func (f *FlexItem) SetBounds(min, max int) {
+	f.min = min
+	f.max = max
+}
+
+// Alignment() returns the cross axis alignment of the item. The second value is false when the item uses the alignment of the layout
+func (f FlexItem) Alignment() (Alignment, bool) {
+	return f.align, f.alignSelf
+}
+
+// SetAlignment() overrides the cross axis alignment of the layout for the item
+func (f *FlexItem) SetAlignment(a Alignment) {
+	f.align = a
+	f.alignSelf = true
+}
+
+func (f FlexItem) clamp(size float32) float32 {
+	if f.max > 0 && size > float32(f.max) {
+		size = float32(f.max)
+	}
+	if size < float32(f.min) {
+		size = float32(f.min)
+	}
+	return size
+}
+
+func (f *FlexItem) String() string {
+	return fmt.Sprintf("FlexItem[grow=%g, shrink=%g, basis=%d, bounds=(%d, %d)]", f.grow, f.shrink, f.basis, f.min, f.max)
+}
+
+// Flexible box layout
+//
+// This layout arranges the widgets along the main axis like BoxLayout, but the widgets
+// grow and shrink to fill the container according to their FlexItem settings. The free
+// space left on a line is distributed by the justification, the widgets are aligned on
+// the cross axis and, when wrapping is enabled, the widgets which don't fit start a new
+// line. The minimum/maximum size and the size policy of the widgets bound the result of
+// the FlexItem settings; the grow factors alone decide who takes the free space.
+//
+// Wrapping needs the extent of the main axis: PreferredSize() wraps at the fixed size of
+// the container and returns a single line otherwise.
+type FlexLayout struct {
+	orientation Orientation
+	justify     FlexJustify
+	alignment   Alignment
+	wrap        bool
+	margin      int
+	spacing     int
+	lineSpacing int
+	items       map[Widget]FlexItem
+}
+
+func NewFlexLayout(orientation Orientation, justify FlexJustify, alignment Alignment, setting ...int) *FlexLayout {
+	var margin, spacing int
+	lineSpacing := -1
+	switch len(setting) {
+	case 0:
+	case 1:
+		margin = setting[0]
+	case 2:
+		margin = setting[0]
+		spacing = setting[1]
+	case 3:
+		margin = setting[0]
+		spacing = setting[1]
+		lineSpacing = setting[2]
+	default:
+		panic("NewFlexLayout can accept extra parameter upto 3 (margin, spacing, lineSpacing).")
+	}
+	if lineSpacing < 0 {
+		lineSpacing = spacing
+	}
+	return &FlexLayout{
+		orientation: orientation,
+		justify:     justify,
+		alignment:   alignment,
+		margin:      margin,
+		spacing:     spacing,
+		lineSpacing: lineSpacing,
+		items:       make(map[Widget]FlexItem),
+	}
+}
+
+func (f *FlexLayout) Orientation() Orientation {
+	return f.orientation
+}
+
+func (f *FlexLayout) SetOrientation(o Orientation) {
+	f.orientation = o
+}
+
+func (f *FlexLayout) Justify() FlexJustify {
+	return f.justify
+}
+
+func (f *FlexLayout) SetJustify(j FlexJustify) {
+	f.justify = j
+}
+
+// Alignment() returns the cross axis alignment of the items
+func (f *FlexLayout) Alignment() Alignment {
+	return f.alignment
+}
+
+func (f *FlexLayout) SetAlignment(a Alignment) {
+	f.alignment = a
+}
+
+func (f *FlexLayout) Wrap() bool {
+	return f.wrap
+}
+
+// SetWrap() enables starting a new line when the widgets don't fit on the main axis
+func (f *FlexLayout) SetWrap(wrap bool) {
+	f.wrap = wrap
+}
+
+func (f *FlexLayout) Margin() int {
+	return f.margin
+}
+
+func (f *FlexLayout) SetMargin(m int) {
+	f.margin = m
+}
+
+func (f *FlexLayout) Spacing() int {
+	return f.spacing
+}
+
+func (f *FlexLayout) SetSpacing(s int) {
+	f.spacing = s
+}
+
+// LineSpacing() returns the spacing between wrapped lines
+func (f *FlexLayout) LineSpacing() int {
+	return f.lineSpacing
+}
+
+func (f *FlexLayout) SetLineSpacing(s int) {
+	f.lineSpacing = s
+}
+
+// SetItem() sets how a child widget flexes. The setting is dropped when the widget is removed from the container
+func (f *FlexLayout) SetItem(widget Widget, item FlexItem) {
+	f.items[widget] = item
+	widget.InvalidateLayout()
+}
+
+// Item() returns the setting of the widget. Widgets without setting don't grow, shrink by 1 and start from the preferred size
+func (f *FlexLayout) Item(widget Widget) FlexItem {
+	if item, ok := f.items[widget]; ok {
+		return item
+	}
+	return NewFlexItem(0, 1)
+}
+
+type flexEntry struct {
+	widget Widget
+	item   FlexItem
+	size   [2]int // target size before flexing
+	fixed  [2]int
+	main   float32
+	frozen bool
+}
+
+type flexLine struct {
+	entries []*flexEntry
+	cross   int
+}
+
+// contentRect() returns the origin and the size of the area inside the margins
+func (f *FlexLayout) contentRect(widget Widget, size [2]int) ([2]int, [2]int) {
+	origin := [2]int{f.margin, f.margin}
+	if _, ok := widget.(*Window); ok {
+		origin[1] += widget.Theme().WindowHeaderHeight - f.margin/2
+	}
+	return origin, [2]int{size[0] - origin[0] - f.margin, size[1] - origin[1] - f.margin}
+}
+
+func (f *FlexLayout) entries(widget Widget, ctx DrawContext) []*flexEntry {
+	/* Drop the settings of removed children */
+	children := make(map[Widget]bool, widget.ChildCount())
+	for _, child := range widget.Children() {
+		children[child] = true
+	}
+	for w := range f.items {
+		if !children[w] {
+			delete(f.items, w)
+		}
+	}
+
+	var entries []*flexEntry
+	axis1 := int(f.orientation)
+	for _, child := range widget.Children() {
+		if !child.Visible() {
+			continue
+		}
+		lW, lH := LayoutSize(child, ctx)
+		fW, fH := child.FixedSize()
+		entry := &flexEntry{
+			widget: child,
+			item:   f.Item(child),
+			fixed:  [2]int{fW, fH},
+			size:   [2]int{lW, lH},
+		}
+		if entry.fixed[axis1] == 0 {
+			if entry.item.basis >= 0 {
+				entry.size[axis1] = entry.item.basis
+			}
+			entry.size[axis1] = int(entry.item.clamp(float32(entry.size[axis1])))
+			minW, minH := child.MinimumSize()
+			maxW, maxH := child.MaximumSize()
+			entry.size[axis1] = boundSize(entry.size[axis1], toI(axis1 == 0, minW, minH), toI(axis1 == 0, maxW, maxH), 0)
+		}
+		entry.main = float32(entry.size[axis1])
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// breakLines() splits the entries into lines. available < 0 puts all the entries on one line
+func (f *FlexLayout) breakLines(entries []*flexEntry, available int) []*flexLine {
+	axis1 := int(f.orientation)
+	axis2 := 1 - axis1
+	var lines []*flexLine
+	var line *flexLine
+	position := 0
+	for _, entry := range entries {
+		if line == nil || (f.wrap && available >= 0 && position+f.spacing+entry.size[axis1] > available) {
+			line = &flexLine{}
+			lines = append(lines, line)
+			position = entry.size[axis1]
+		} else {
+			position += f.spacing + entry.size[axis1]
+		}
+		line.entries = append(line.entries, entry)
+		line.cross = maxI(line.cross, entry.size[axis2])
+	}
+	return lines
+}
+
+// resolve() grows or shrinks the entries of a line to fill available
+//
+// The free space is shared by the grow factors (or taken by the shrink factors weighted by
+// the size), and the entries which hit their bounds are frozen before the rest is shared again.
+func (f *FlexLayout) resolve(line *flexLine, available int) {
+	axis1 := int(f.orientation)
+	for _, entry := range line.entries {
+		entry.main = float32(entry.size[axis1])
+		entry.frozen = entry.fixed[axis1] > 0
+	}
+	for {
+		free := float32(available - f.spacing*(len(line.entries)-1))
+		for _, entry := range line.entries {
+			if entry.frozen {
+				free -= entry.main
+			} else {
+				free -= float32(entry.size[axis1])
+			}
+		}
+		growing := free >= 0
+		var totalFactor float32
+		for _, entry := range line.entries {
+			if !entry.frozen {
+				totalFactor += f.factor(entry, growing)
+			}
+		}
+		if totalFactor == 0 || free == 0 {
+			for _, entry := range line.entries {
+				if !entry.frozen {
+					entry.main = float32(entry.size[axis1])
+				}
+			}
+			return
+		}
+		violated := false
+		for _, entry := range line.entries {
+			if entry.frozen {
+				continue
+			}
+			target := float32(entry.size[axis1]) + free*f.factor(entry, growing)/totalFactor
+			if target < 0 {
+				target = 0
+			}
+			entry.main = f.bound(entry, target)
+			if entry.main != target {
+				entry.frozen = true
+				violated = true
+			}
+		}
+		if !violated {
+			return
+		}
+	}
+}
+
+// bound() limits the main size of an entry by the item and by the size policy and the minimum/maximum size of the widget
+func (f *FlexLayout) bound(entry *flexEntry, target float32) float32 {
+	target = entry.item.clamp(target)
+	axis1 := int(f.orientation)
+	if fitted := FitSize(entry.widget, axis1, entry.size[axis1], int(target)); fitted != int(target) {
+		return float32(fitted)
+	}
+	return target
+}
+
+func (f *FlexLayout) factor(entry *flexEntry, growing bool) float32 {
+	if growing {
+		return entry.item.grow
+	}
+	return entry.item.shrink * float32(entry.size[int(f.orientation)])
+}
+
+func (f *FlexLayout) OnPerformLayout(widget Widget, ctx DrawContext) {
+	fW, fH := widget.FixedSize()
+	containerSize := [2]int{
+		toI(fW > 0, fW, widget.Width()),
+		toI(fH > 0, fH, widget.Height()),
+	}
+	origin, content := f.contentRect(widget, containerSize)
+	axis1 := int(f.orientation)
+	axis2 := 1 - axis1
+
+	lines := f.breakLines(f.entries(widget, ctx), content[axis1])
+	if len(lines) == 1 {
+		// a single line fills the cross axis of the container
+		lines[0].cross = maxI(lines[0].cross, content[axis2])
+	}
+	crossPosition := origin[axis2]
+	for _, line := range lines {
+		f.resolve(line, content[axis1])
+		used := float32(f.spacing * (len(line.entries) - 1))
+		for _, entry := range line.entries {
+			used += entry.main
+		}
+		free := maxF(float32(content[axis1])-used, 0)
+		position := float32(origin[axis1])
+		gap := float32(f.spacing)
+		switch f.justify {
+		case JustifyCenter:
+			position += free / 2
+		case JustifyEnd:
+			position += free
+		case JustifySpaceBetween:
+			if len(line.entries) > 1 {
+				gap += free / float32(len(line.entries)-1)
+			}
+		case JustifySpaceAround:
+			around := free / float32(len(line.entries))
+			position += around / 2
+			gap += around
+		}
+		for _, entry := range line.entries {
+			var pos, size [2]int
+			pos[axis1] = int(position + 0.5)
+			size[axis1] = int(position+entry.main+0.5) - pos[axis1]
+			size[axis2] = entry.size[axis2]
+			pos[axis2] = crossPosition
+			align := f.alignment
+			if a, ok := entry.item.Alignment(); ok {
+				align = a
+			}
+			switch align {
+			case Minimum:
+			case Middle:
+				pos[axis2] += (line.cross - size[axis2]) / 2
+			case Maximum:
+				pos[axis2] += line.cross - size[axis2]
+			case Fill:
+				size[axis2] = FitSize(entry.widget, axis2, entry.size[axis2], line.cross)
+			}
+			entry.widget.SetPosition(pos[0], pos[1])
+			entry.widget.SetSize(size[0], size[1])
+			entry.widget.LayoutIfNeeded(entry.widget, ctx)
+			position += entry.main + gap
+		}
+		crossPosition += line.cross + f.lineSpacing
+	}
+}
+
+func (f *FlexLayout) PreferredSize(widget Widget, ctx DrawContext) (int, int) {
+	fW, fH := widget.FixedSize()
+	origin, content := f.contentRect(widget, [2]int{fW, fH})
+	axis1 := int(f.orientation)
+	axis2 := 1 - axis1
+	available := -1
+	if [2]int{fW, fH}[axis1] > 0 {
+		available = content[axis1]
+	}
+	var size [2]int
+	lines := f.breakLines(f.entries(widget, ctx), available)
+	for i, line := range lines {
+		main := f.spacing * (len(line.entries) - 1)
+		for _, entry := range line.entries {
+			main += entry.size[axis1]
+		}
+		size[axis1] = maxI(size[axis1], main)
+		if i > 0 {
+			size[axis2] += f.lineSpacing
+		}
+		size[axis2] += line.cross
+	}
+	return size[0] + origin[0] + f.margin, size[1] + origin[1] + f.margin
+}
+
+func (f *FlexLayout) String() string {
+	return fmt.Sprintf("FlexLayout[%s,%s,%s]", f.orientation, f.justify, f.alignment)
+}
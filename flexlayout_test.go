@@ -0,0 +1,125 @@
+package nanogui
+
+import (
+	"strings"
+	"testing"
+)
+
+// sized is a widget with a fixed preferred size
+type sized struct {
+	WidgetImplement
+	pw, ph int
+}
+
+func (s *sized) PreferredSize(self Widget, ctx DrawContext) (int, int) {
+	return s.pw, s.ph
+}
+
+func newSized(parent Widget, w, h int) *sized {
+	s := &sized{pw: w, ph: h}
+	InitWidget(s, parent)
+	return s
+}
+
+func newFlexFixture(width, height int) (DrawContext, Widget, *FlexLayout, [3]*sized) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	layout := NewFlexLayout(Horizontal, JustifyStart, Fill, 0, 10)
+	panel.SetLayout(layout)
+	items := [3]*sized{newSized(panel, 50, 20), newSized(panel, 50, 30), newSized(panel, 50, 20)}
+	panel.SetFixedSize(width, height)
+	panel.SetSize(width, height)
+	return screen.Context(), panel, layout, items
+}
+
+func TestFlexLayoutGrowAndShrink(t *testing.T) {
+	ctx, panel, layout, items := newFlexFixture(400, 40)
+	a, b, c := items[0], items[1], items[2]
+	layout.SetItem(a, NewFlexItem(1, 1))
+	layout.SetItem(b, NewFlexItem(2, 1, -1, 0, 100))
+	if w, h := layout.PreferredSize(panel, ctx); w != 170 || h != 30 {
+		t.Fatalf("PreferredSize() = %d, %d, want 170, 30", w, h)
+	}
+
+	layout.OnPerformLayout(panel, ctx)
+	// b is clamped to its max width, so a takes the rest of the free space
+	if a.Width() != 230 || b.Width() != 100 || c.Width() != 50 {
+		t.Fatalf("grow: widths %d, %d, %d, want 230, 100, 50", a.Width(), b.Width(), c.Width())
+	}
+	if x, _ := c.Position(); x != 350 {
+		t.Errorf("third item x = %d, want 350", x)
+	}
+	if a.Height() != 40 {
+		t.Errorf("Fill alignment: height %d, want 40", a.Height())
+	}
+
+	panel.SetFixedSize(120, 40)
+	layout.OnPerformLayout(panel, ctx)
+	if total := a.Width() + b.Width() + c.Width() + 20; total != 120 {
+		t.Errorf("shrink: items and spacing take %d, want 120", total)
+	}
+}
+
+func TestFlexLayoutJustify(t *testing.T) {
+	ctx, panel, layout, items := newFlexFixture(400, 40)
+	a, b, c := items[0], items[1], items[2]
+	layout.SetJustify(JustifySpaceBetween)
+	layout.SetAlignment(Middle)
+	layout.OnPerformLayout(panel, ctx)
+	ax, ay := a.Position()
+	bx, _ := b.Position()
+	cx, _ := c.Position()
+	if ax != 0 || bx != 175 || cx != 350 || ay != 10 {
+		t.Errorf("SpaceBetween: a at (%d, %d), b at %d, c at %d, want (0, 10), 175, 350", ax, ay, bx, cx)
+	}
+
+	layout.SetJustify(JustifyCenter)
+	layout.OnPerformLayout(panel, ctx)
+	if x, _ := a.Position(); x != 115 {
+		t.Errorf("Center: first item x = %d, want 115", x)
+	}
+}
+
+func TestFlexLayoutWrap(t *testing.T) {
+	ctx, panel, layout, items := newFlexFixture(120, 0)
+	layout.SetWrap(true)
+	if w, h := layout.PreferredSize(panel, ctx); w != 110 || h != 60 {
+		t.Fatalf("PreferredSize() = %d, %d, want 110, 60", w, h)
+	}
+	panel.SetSize(120, 60)
+	layout.OnPerformLayout(panel, ctx)
+	if _, y := items[2].Position(); y != 40 {
+		t.Errorf("the third item should wrap to the second line: y = %d, want 40", y)
+	}
+}
+
+func TestFlexLayoutDropsRemovedItems(t *testing.T) {
+	ctx, panel, layout, items := newFlexFixture(400, 40)
+	layout.SetItem(items[0], NewFlexItem(1, 1))
+	layout.SetItem(items[1], NewFlexItem(1, 1))
+	panel.RemoveChild(items[0])
+	layout.OnPerformLayout(panel, ctx)
+	if _, ok := layout.items[items[0]]; ok || len(layout.items) != 1 {
+		t.Errorf("%d items after removing a child, want only the remaining one", len(layout.items))
+	}
+}
+
+func TestFlexLayoutDeclarative(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	ui, err := LoadUI(screen, strings.NewReader(`{"widgets": [{"type": "Widget", "id": "row",
+		"layout": {"type": "FlexLayout", "justify": "space-around", "wrap": true},
+		"children": [{"type": "Label", "caption": "a", "flex": {"grow": 1, "align": "middle"}}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := ExportWidget(ui.Widget("row"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Layout.Justify != "SpaceAround" || !node.Layout.Wrap {
+		t.Errorf("exported layout %+v", node.Layout)
+	}
+	if flex := node.Children[0].Flex; flex.Grow != 1 || flex.Align != "Middle" {
+		t.Errorf("exported flex item %+v", flex)
+	}
+}
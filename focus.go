@@ -0,0 +1,137 @@
+package nanogui
+
+import (
+	"github.com/shibukawa/glfw"
+	"sort"
+)
+
+// FocusNext() moves the keyboard focus to the next tab stop in the focused window
+//
+// It returns false if the window has no widget which can receive the focus.
+func (s *Screen) FocusNext() bool {
+	return s.moveFocus(1)
+}
+
+// FocusPrevious() moves the keyboard focus to the previous tab stop in the focused window
+//
+// It returns false if the window has no widget which can receive the focus.
+func (s *Screen) FocusPrevious() bool {
+	return s.moveFocus(-1)
+}
+
+// TabOrder() returns the widgets under root which can receive the focus by Tab / Shift+Tab in visiting order
+//
+// Invisible and disabled widgets (and their descendants) are skipped.
+func TabOrder(root Widget) []Widget {
+	var order tabOrderWidgets
+	collectTabStops(root, &order)
+	sort.Stable(order)
+	return order
+}
+
+func collectTabStops(widget Widget, order *tabOrderWidgets) {
+	if !widget.Visible() || !widget.Enabled() {
+		return
+	}
+	if widget.TabStop() && widget.TabIndex() >= 0 {
+		*order = append(*order, widget)
+	}
+	for _, child := range widget.Children() {
+		collectTabStops(child, order)
+	}
+}
+
+func (s *Screen) moveFocus(direction int) bool {
+	scope := s.focusScope()
+	if scope == nil {
+		return false
+	}
+	order := TabOrder(scope)
+	if len(order) == 0 {
+		return false
+	}
+	current := -1
+	if len(s.focusPath) > 0 {
+		for i, widget := range order {
+			if widget == s.focusPath[0] {
+				current = i
+				break
+			}
+		}
+	}
+	var next int
+	switch {
+	case current == -1 && direction > 0:
+		next = 0
+	case current == -1:
+		next = len(order) - 1
+	default:
+		next = (current + direction + len(order)) % len(order)
+	}
+	order[next].RequestFocus(order[next])
+	return true
+}
+
+// focusScope() returns the window in which Tab / Shift+Tab moves the focus
+func (s *Screen) focusScope() Widget {
+	var frontMost, modal Widget
+	for _, child := range s.Children() {
+		if _, ok := child.(*Popup); ok || !child.Visible() {
+			continue
+		}
+		if frontMost == nil || frontMost.Depth() <= child.Depth() {
+			frontMost = child
+		}
+		if window, ok := child.(*Window); ok && window.Modal() {
+			if modal == nil || modal.Depth() <= child.Depth() {
+				modal = child
+			}
+		}
+	}
+	switch {
+	case modal != nil:
+		return modal
+	case len(s.focusPath) > 1:
+		return s.focusPath[len(s.focusPath)-2]
+	}
+	return frontMost
+}
+
+// Sort Interface
+type tabOrderWidgets []Widget
+
+func (w tabOrderWidgets) Len() int {
+	return len(w)
+}
+
+func (w tabOrderWidgets) Less(i, j int) bool {
+	a, b := w[i].TabIndex(), w[j].TabIndex()
+	if a > 0 && b > 0 {
+		return a < b
+	}
+	return a > 0 && b <= 0
+}
+
+func (w tabOrderWidgets) Swap(i, j int) {
+	w[i], w[j] = w[j], w[i]
+}
+
+// isActivationKey() returns whether the key pushes buttons and toggles check boxes
+func isActivationKey(key glfw.Key) bool {
+	return key == glfw.KeySpace || key == glfw.KeyEnter || key == glfw.KeyKPEnter
+}
+
+// drawFocusRing() draws the keyboard focus indicator of the theme inside the given rectangle
+func drawFocusRing(ctx DrawContext, theme *Theme, x, y, w, h, radius float32) {
+	if theme.FocusRingWidth <= 0 {
+		return
+	}
+	width := float32(theme.FocusRingWidth)
+	ctx.Save()
+	ctx.BeginPath()
+	ctx.RoundedRect(x+width*0.5, y+width*0.5, w-width, h-width, radius)
+	ctx.SetStrokeWidth(width)
+	ctx.SetStrokeColor(theme.FocusRingColor)
+	ctx.Stroke()
+	ctx.Restore()
+}
@@ -0,0 +1,63 @@
+package nanogui
+
+import (
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+func TestTabFocusTraversal(t *testing.T) {
+	screen, window := newTestWindow(t)
+	NewLabel(window, "not focusable")
+	first := NewTextBox(window, "first")
+	first.SetEditable(true)
+	button := NewButton(window, "button")
+	checkBox := NewCheckBox(window, "check")
+	NewButton(window, "disabled").SetEnabled(false)
+	indexed := NewTextBox(window, "indexed")
+	indexed.SetEditable(true)
+	indexed.SetTabIndex(1)
+	screen.PerformLayout()
+
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	if !indexed.Focused() {
+		t.Fatal("the widget with a positive tab index should be focused first")
+	}
+	in.PressKey(glfw.KeyTab, 0)
+	if !first.Focused() || indexed.Focused() {
+		t.Fatal("Tab should move the focus to the first widget in tree order")
+	}
+	in.PressKey(glfw.KeyTab, 0)
+	in.PressKey(glfw.KeyTab, 0)
+	if !checkBox.Focused() {
+		t.Fatal("Tab should skip non-focusable widgets")
+	}
+	in.PressKey(glfw.KeyTab, glfw.ModShift)
+	if !button.Focused() {
+		t.Fatal("Shift+Tab should move the focus backward")
+	}
+	in.PressKey(glfw.KeyTab, 0)
+	in.PressKey(glfw.KeyTab, 0)
+	if !indexed.Focused() {
+		t.Fatal("Tab should skip disabled widgets and wrap around")
+	}
+}
+
+func TestTabFocusStaysInModalWindow(t *testing.T) {
+	screen, window := newTestWindow(t)
+	NewButton(window, "behind")
+	modal := NewWindow(screen, "Modal")
+	modal.SetModal(true)
+	modal.SetLayout(NewGroupLayout())
+	ok := NewButton(modal, "OK")
+	screen.PerformLayout()
+
+	in := screen.Input()
+	for i := 0; i < 3; i++ {
+		in.PressKey(glfw.KeyTab, 0)
+		if !ok.Focused() {
+			t.Fatalf("Tab #%d moves the focus out of the modal window", i+1)
+		}
+	}
+}
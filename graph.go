@@ -88,12 +88,12 @@ func (g *Graph) SetValues(values []float32) {
 	g.values = values
 }
 
-func (g *Graph) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+func (g *Graph) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	return 180, 45
 
 }
 
-func (g *Graph) Draw(self Widget, ctx *nanovgo.Context) {
+func (g *Graph) Draw(self Widget, ctx DrawContext) {
 	g.WidgetImplement.Draw(self, ctx)
 
 	x := float32(g.x)
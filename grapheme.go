@@ -0,0 +1,205 @@
+package nanogui
+
+import (
+	"unicode"
+)
+
+// graphemeBreak is the Grapheme_Cluster_Break property of UAX #29
+type graphemeBreak int
+
+const (
+	graphemeOther graphemeBreak = iota
+	graphemeCR
+	graphemeLF
+	graphemeControl
+	graphemeExtend
+	graphemeZWJ
+	graphemeRegionalIndicator
+	graphemePrepend
+	graphemeSpacingMark
+	graphemeL
+	graphemeV
+	graphemeT
+	graphemeLV
+	graphemeLVT
+)
+
+// otherGraphemeExtend is the Other_Grapheme_Extend property plus the emoji modifiers and the tag characters
+var otherGraphemeExtend = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x09be, 0x09be, 1}, {0x09d7, 0x09d7, 1}, {0x0b3e, 0x0b3e, 1}, {0x0b57, 0x0b57, 1},
+		{0x0bbe, 0x0bbe, 1}, {0x0bd7, 0x0bd7, 1}, {0x0cc2, 0x0cc2, 1}, {0x0cd5, 0x0cd6, 1},
+		{0x0d3e, 0x0d3e, 1}, {0x0d57, 0x0d57, 1}, {0x0dcf, 0x0dcf, 1}, {0x0ddf, 0x0ddf, 1},
+		{0x1b35, 0x1b35, 1}, {0x200c, 0x200c, 1}, {0x302e, 0x302f, 1}, {0xff9e, 0xff9f, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1133e, 0x1133e, 1}, {0x11357, 0x11357, 1}, {0x114b0, 0x114b0, 1}, {0x114bd, 0x114bd, 1},
+		{0x115af, 0x115af, 1}, {0x11930, 0x11930, 1}, {0x1d165, 0x1d165, 1}, {0x1d16e, 0x1d172, 1},
+		{0x1f3fb, 0x1f3ff, 1}, {0xe0020, 0xe007f, 1},
+	},
+}
+
+// graphemePrependTable is the Prepend value of Grapheme_Cluster_Break
+var graphemePrependTable = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x0600, 0x0605, 1}, {0x06dd, 0x06dd, 1}, {0x070f, 0x070f, 1}, {0x0890, 0x0891, 1},
+		{0x08e2, 0x08e2, 1}, {0x0d4e, 0x0d4e, 1},
+	},
+	R32: []unicode.Range32{
+		{0x110bd, 0x110bd, 1}, {0x110cd, 0x110cd, 1}, {0x111c2, 0x111c3, 1}, {0x1193f, 0x1193f, 1},
+		{0x11941, 0x11941, 1}, {0x11a3a, 0x11a3a, 1}, {0x11a84, 0x11a89, 1}, {0x11d46, 0x11d46, 1},
+	},
+}
+
+// extendedPictographic approximates the Extended_Pictographic property of UTS #51
+var extendedPictographic = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x00a9, 0x00a9, 1}, {0x00ae, 0x00ae, 1}, {0x203c, 0x203c, 1}, {0x2049, 0x2049, 1},
+		{0x2122, 0x2122, 1}, {0x2139, 0x2139, 1}, {0x2194, 0x2199, 1}, {0x21a9, 0x21aa, 1},
+		{0x231a, 0x231b, 1}, {0x2328, 0x2328, 1}, {0x2388, 0x2388, 1}, {0x23cf, 0x23cf, 1},
+		{0x23e9, 0x23f3, 1}, {0x23f8, 0x23fa, 1}, {0x24c2, 0x24c2, 1}, {0x25aa, 0x25ab, 1},
+		{0x25b6, 0x25b6, 1}, {0x25c0, 0x25c0, 1}, {0x25fb, 0x25fe, 1}, {0x2600, 0x2605, 1},
+		{0x2607, 0x2612, 1}, {0x2614, 0x2685, 1}, {0x2690, 0x2705, 1}, {0x2708, 0x2712, 1},
+		{0x2714, 0x2714, 1}, {0x2716, 0x2716, 1}, {0x271d, 0x271d, 1}, {0x2721, 0x2721, 1},
+		{0x2728, 0x2728, 1}, {0x2733, 0x2734, 1}, {0x2744, 0x2744, 1}, {0x2747, 0x2747, 1},
+		{0x274c, 0x274c, 1}, {0x274e, 0x274e, 1}, {0x2753, 0x2755, 1}, {0x2757, 0x2757, 1},
+		{0x2763, 0x2767, 1}, {0x2795, 0x2797, 1}, {0x27a1, 0x27a1, 1}, {0x27b0, 0x27b0, 1},
+		{0x27bf, 0x27bf, 1}, {0x2934, 0x2935, 1}, {0x2b05, 0x2b07, 1}, {0x2b1b, 0x2b1c, 1},
+		{0x2b50, 0x2b50, 1}, {0x2b55, 0x2b55, 1}, {0x3030, 0x3030, 1}, {0x303d, 0x303d, 1},
+		{0x3297, 0x3297, 1}, {0x3299, 0x3299, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1f000, 0x1f0ff, 1}, {0x1f10d, 0x1f10f, 1}, {0x1f12f, 0x1f12f, 1}, {0x1f16c, 0x1f171, 1},
+		{0x1f17e, 0x1f17f, 1}, {0x1f18e, 0x1f18e, 1}, {0x1f191, 0x1f19a, 1}, {0x1f1ad, 0x1f1e5, 1},
+		{0x1f201, 0x1f20f, 1}, {0x1f21a, 0x1f21a, 1}, {0x1f22f, 0x1f22f, 1}, {0x1f232, 0x1f23a, 1},
+		{0x1f23c, 0x1f23f, 1}, {0x1f249, 0x1f3fa, 1}, {0x1f400, 0x1f53d, 1}, {0x1f546, 0x1f64f, 1},
+		{0x1f680, 0x1f6ff, 1}, {0x1f774, 0x1f77f, 1}, {0x1f7d5, 0x1f7ff, 1}, {0x1f80c, 0x1f80f, 1},
+		{0x1f848, 0x1f84f, 1}, {0x1f85a, 0x1f85f, 1}, {0x1f888, 0x1f88f, 1}, {0x1f8ae, 0x1f8ff, 1},
+		{0x1f90c, 0x1f93a, 1}, {0x1f93c, 0x1f945, 1}, {0x1f947, 0x1faff, 1}, {0x1fc00, 0x1fffd, 1},
+	},
+}
+
+func graphemeBreakOf(r rune) graphemeBreak {
+	switch {
+	case r == '\r':
+		return graphemeCR
+	case r == '\n':
+		return graphemeLF
+	case r == 0x200d:
+		return graphemeZWJ
+	case r >= 0x1f1e6 && r <= 0x1f1ff:
+		return graphemeRegionalIndicator
+	case r >= 0x1100 && r <= 0x115f, r >= 0xa960 && r <= 0xa97c:
+		return graphemeL
+	case r >= 0x1160 && r <= 0x11a7, r >= 0xd7b0 && r <= 0xd7c6:
+		return graphemeV
+	case r >= 0x11a8 && r <= 0x11ff, r >= 0xd7cb && r <= 0xd7fb:
+		return graphemeT
+	case r >= 0xac00 && r <= 0xd7a3:
+		if (r-0xac00)%28 == 0 {
+			return graphemeLV
+		}
+		return graphemeLVT
+	case unicode.In(r, unicode.Mn, unicode.Me, otherGraphemeExtend):
+		return graphemeExtend
+	case unicode.In(r, graphemePrependTable):
+		return graphemePrepend
+	case unicode.In(r, unicode.Cc, unicode.Zl, unicode.Zp), unicode.Is(unicode.Cf, r) && r != 0x200c:
+		return graphemeControl
+	case unicode.Is(unicode.Mc, r), r == 0x0e33, r == 0x0eb3:
+		return graphemeSpacingMark
+	}
+	return graphemeOther
+}
+
+// isGraphemeBreak() applies the rules GB3-GB999 of UAX #29 between text[pos-1] and text[pos]
+func isGraphemeBreak(text []rune, breaks []graphemeBreak, pos int) bool {
+	before, after := breaks[pos-1], breaks[pos]
+	switch {
+	case before == graphemeCR && after == graphemeLF: // GB3
+		return false
+	case before == graphemeCR, before == graphemeLF, before == graphemeControl: // GB4
+		return true
+	case after == graphemeCR, after == graphemeLF, after == graphemeControl: // GB5
+		return true
+	case before == graphemeL && (after == graphemeL || after == graphemeV || after == graphemeLV || after == graphemeLVT): // GB6
+		return false
+	case (before == graphemeLV || before == graphemeV) && (after == graphemeV || after == graphemeT): // GB7
+		return false
+	case (before == graphemeLVT || before == graphemeT) && after == graphemeT: // GB8
+		return false
+	case after == graphemeExtend, after == graphemeZWJ: // GB9
+		return false
+	case after == graphemeSpacingMark: // GB9a
+		return false
+	case before == graphemePrepend: // GB9b
+		return false
+	case before == graphemeZWJ && unicode.Is(extendedPictographic, text[pos]): // GB11
+		i := pos - 2
+		for i >= 0 && breaks[i] == graphemeExtend {
+			i--
+		}
+		return i < 0 || !unicode.Is(extendedPictographic, text[i])
+	case before == graphemeRegionalIndicator && after == graphemeRegionalIndicator: // GB12, GB13
+		count := 0
+		for i := pos - 1; i >= 0 && breaks[i] == graphemeRegionalIndicator; i-- {
+			count++
+		}
+		return count%2 == 0
+	}
+	return true // GB999
+}
+
+// graphemeBoundaries() returns the start index of each extended grapheme cluster of text, followed by len(text)
+//
+// Cursor movement, deletion and line wrapping use these boundaries, so emoji sequences,
+// flags and combining marks are handled as one character.
+func graphemeBoundaries(text []rune) []int {
+	boundaries := make([]int, 0, len(text)+1)
+	if len(text) == 0 {
+		return append(boundaries, 0)
+	}
+	breaks := make([]graphemeBreak, len(text))
+	for i, r := range text {
+		breaks[i] = graphemeBreakOf(r)
+	}
+	boundaries = append(boundaries, 0)
+	for i := 1; i < len(text); i++ {
+		if isGraphemeBreak(text, breaks, i) {
+			boundaries = append(boundaries, i)
+		}
+	}
+	return append(boundaries, len(text))
+}
+
+// graphemeClusters() splits text into extended grapheme clusters
+func graphemeClusters(text []rune) [][]rune {
+	boundaries := graphemeBoundaries(text)
+	clusters := make([][]rune, 0, len(boundaries)-1)
+	for i := 1; i < len(boundaries); i++ {
+		clusters = append(clusters, text[boundaries[i-1]:boundaries[i]])
+	}
+	return clusters
+}
+
+// previousGraphemeBoundary() returns the start of the grapheme cluster before pos
+func previousGraphemeBoundary(text []rune, pos int) int {
+	result := 0
+	for _, boundary := range graphemeBoundaries(text) {
+		if boundary >= pos {
+			break
+		}
+		result = boundary
+	}
+	return result
+}
+
+// nextGraphemeBoundary() returns the end of the grapheme cluster after pos
+func nextGraphemeBoundary(text []rune, pos int) int {
+	for _, boundary := range graphemeBoundaries(text) {
+		if boundary > pos {
+			return boundary
+		}
+	}
+	return len(text)
+}
@@ -0,0 +1,134 @@
+package nanogui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shibukawa/glfw"
+	"github.com/shibukawa/nanovgo"
+)
+
+func TestGraphemeClusters(t *testing.T) {
+	cases := []struct {
+		text  string
+		count int
+	}{
+		{"e\u0301a", 2}, // combining mark
+		{"\U0001f468\u200d\U0001f469\u200d\U0001f467\u200d\U0001f466x", 2}, // ZWJ sequence
+		{"\U0001f1ef\U0001f1f5\U0001f1fa\U0001f1f8", 2},                    // regional indicator pairs
+		{"\U0001f44d\U0001f3fd", 1},                                        // emoji modifier
+		{"\r\n", 1},
+		{"한국어", 3},                // precomposed Hangul syllables
+		{"\u1100\u1161\u11a8", 1}, // conjoining Hangul jamo
+		{"a\u200db", 2},           // ZWJ without emoji
+		{"\u2764\ufe0f", 1},       // variation selector
+	}
+	for _, c := range cases {
+		if got := len(graphemeClusters([]rune(c.text))); got != c.count {
+			t.Errorf("graphemeClusters(%+q) has %d clusters, want %d", c.text, got, c.count)
+		}
+	}
+}
+
+func TestTextBoxGraphemeEditing(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := NewTextBox(window, "")
+	textBox.SetEditable(true)
+	textBox.SetAlignment(TextLeft)
+	textBox.SetFixedSize(300, 0)
+	screen.PerformLayout()
+	screen.DrawAll()
+
+	family := "\U0001f468\u200d\U0001f469\u200d\U0001f467"
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.TypeText("a" + family + "b\U0001f1ef\U0001f1f5")
+	in.PressKey(glfw.KeyBackspace, 0)
+	if got := string(textBox.valueTemp); got != "a"+family+"b" {
+		t.Fatalf("Backspace should delete the whole flag: %+q", got)
+	}
+	in.PressKey(glfw.KeyLeft, 0)
+	in.PressKey(glfw.KeyLeft, glfw.ModShift)
+	if textBox.cursorPos != 1 || textBox.selectionPos != 6 {
+		t.Fatalf("Shift+Left should select the whole family: selection %d-%d, want 1-6", textBox.cursorPos, textBox.selectionPos)
+	}
+	in.PressKey(glfw.KeyDelete, 0)
+	if got := string(textBox.valueTemp); got != "ab" {
+		t.Fatalf("Delete: %+q, want %q", got, "ab")
+	}
+
+	in.PressKey(glfw.KeyA, glfw.ModControl)
+	in.TypeText("e\u0301e\u0301")
+	screen.DrawAll()
+	x, y := textBox.AbsolutePosition()
+	// click on the combining mark of the first cluster
+	in.ClickAt(x+textBox.Height()*3/10+textBox.FontSize()/2+2, y+5)
+	if textBox.cursorPos%2 != 0 {
+		t.Errorf("clicking shouldn't put the cursor inside a cluster: cursor %d", textBox.cursorPos)
+	}
+}
+
+func TestWrapTextKeepsClusters(t *testing.T) {
+	ctx := NewRecordingContext()
+	ctx.SetFontSize(10) // 5px per narrow rune
+	lines := wrapText(ctx, "hello world foo\nverylongword 日本語テキスト", 40)
+	want := []string{"hello", "world", "foo", "verylong", "word 日", "本語テキ", "スト"}
+	if len(lines) != len(want) {
+		t.Fatalf("wrapText() = %q, want %q", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Fatalf("wrapText() = %q, want %q", lines, want)
+		}
+	}
+	for _, line := range wrapText(ctx, "ab\U0001f468\u200d\U0001f469\u200d\U0001f467cd", 10) {
+		if line == "\U0001f468" {
+			t.Fatalf("wrapText() splits the ZWJ sequence")
+		}
+	}
+}
+
+type countingContext struct {
+	DrawContext
+	measures int
+}
+
+func (c *countingContext) TextBounds(x, y float32, str string) (float32, []float32) {
+	c.measures++
+	return c.DrawContext.TextBounds(x, y, str)
+}
+
+func (c *countingContext) TextGlyphPositionsRune(x, y float32, runes []rune) []nanovgo.GlyphPosition {
+	c.measures++
+	return c.DrawContext.TextGlyphPositionsRune(x, y, runes)
+}
+
+func TestWrapTextMeasuresOnce(t *testing.T) {
+	ctx := &countingContext{DrawContext: NewRecordingContext()}
+	ctx.SetFontSize(10)
+	lines := wrapText(ctx, strings.Repeat("word ", 200), 100)
+	if len(lines) != 50 {
+		t.Fatalf("%d lines, want 50", len(lines))
+	}
+	if ctx.measures > 2 {
+		t.Errorf("a paragraph is measured %d times, want at most 2", ctx.measures)
+	}
+}
+
+func TestLabelCachesWrappedLines(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	label := NewLabel(screen, "hello world foo")
+	label.SetFixedWidth(40)
+	label.SetFontSize(10)
+	ctx := &countingContext{DrawContext: screen.Context()}
+	label.PreferredSize(label, ctx)
+	label.Draw(label, ctx)
+	label.Draw(label, ctx)
+	if ctx.measures > 2 {
+		t.Errorf("the caption is measured %d times, want it wrapped once", ctx.measures)
+	}
+	label.SetCaption("hello world")
+	if _, h := label.PreferredSize(label, ctx); len(label.wrapped.lines) != 2 || h == 0 {
+		t.Errorf("wrapped lines after SetCaption() = %q", label.wrapped.lines)
+	}
+}
@@ -0,0 +1,404 @@
+package nanogui
+
+import (
+	"github.com/shibukawa/nanovgo"
+	"time"
+	"unicode"
+)
+
+// NewHeadlessScreen() creates a screen without a GLFW window and OpenGL context
+//
+// The screen has a fixed logical size and draws into a RecordingContext, so
+// layout, event dispatch and drawing can be exercised from plain unit tests.
+func NewHeadlessScreen(width, height int) *Screen {
+	if startTime.IsZero() {
+		startTime = time.Now()
+	}
+	screen := &Screen{}
+	InitWidget(screen, nil)
+	screen.context = NewRecordingContext()
+	screen.w, screen.h = width, height
+	screen.fbW, screen.fbH = width, height
+	screen.pixelRatio = 1.0
+	screen.theme = NewStandardTheme(screen.context)
+	screen.lastInteraction = GetTime()
+	screen.clipboard = NewMemoryClipboard()
+	return screen
+}
+
+// IsHeadless() returns whether the screen was created by NewHeadlessScreen()
+func (s *Screen) IsHeadless() bool {
+	return s.window == nil
+}
+
+// RecordedCall is a call made to a RecordingContext
+type RecordedCall struct {
+	Name string
+	Args []interface{}
+}
+
+type recordingState struct {
+	fontSize   float32
+	fontFace   string
+	align      nanovgo.Align
+	lineHeight float32
+}
+
+// RecordingContext is a DrawContext which records calls instead of rasterizing
+//
+// Text is measured with simple synthetic metrics: narrow glyphs advance by half
+// of the font size and East Asian wide glyphs advance by the full font size.
+// This keeps layout results deterministic regardless of the loaded fonts.
+type RecordingContext struct {
+	calls  []RecordedCall
+	states []recordingState
+	fonts  map[string]int
+	images map[int][2]int
+}
+
+func NewRecordingContext() *RecordingContext {
+	return &RecordingContext{
+		states: []recordingState{{
+			fontSize:   16,
+			align:      nanovgo.AlignLeft | nanovgo.AlignBaseline,
+			lineHeight: 1.0,
+		}},
+		fonts:  make(map[string]int),
+		images: make(map[int][2]int),
+	}
+}
+
+// Calls() returns the calls recorded since the last frame start or Reset()
+func (r *RecordingContext) Calls() []RecordedCall {
+	return r.calls
+}
+
+// Reset() clears the recorded calls
+func (r *RecordingContext) Reset() {
+	r.calls = nil
+}
+
+// Texts() returns the strings drawn by Text(), TextRune() and TextBox() in call order
+func (r *RecordingContext) Texts() []string {
+	var result []string
+	for _, call := range r.calls {
+		switch call.Name {
+		case "Text", "TextBox":
+			result = append(result, call.Args[len(call.Args)-1].(string))
+		case "TextRune":
+			result = append(result, string(call.Args[2].([]rune)))
+		}
+	}
+	return result
+}
+
+// AddImage() registers a fake image with the given size and returns its handle
+func (r *RecordingContext) AddImage(w, h int) int {
+	image := len(r.images) + 1
+	r.images[image] = [2]int{w, h}
+	return image
+}
+
+func (r *RecordingContext) record(name string, args ...interface{}) {
+	r.calls = append(r.calls, RecordedCall{Name: name, Args: args})
+}
+
+func (r *RecordingContext) state() *recordingState {
+	return &r.states[len(r.states)-1]
+}
+
+func (r *RecordingContext) BeginFrame(windowWidth, windowHeight int, devicePixelRatio float32) {
+	r.calls = nil
+	r.states = r.states[:1]
+	r.record("BeginFrame", windowWidth, windowHeight, devicePixelRatio)
+}
+
+func (r *RecordingContext) EndFrame() {
+	r.record("EndFrame")
+}
+
+func (r *RecordingContext) Save() {
+	r.states = append(r.states, *r.state())
+	r.record("Save")
+}
+
+func (r *RecordingContext) Restore() {
+	if len(r.states) > 1 {
+		r.states = r.states[:len(r.states)-1]
+	}
+	r.record("Restore")
+}
+
+func (r *RecordingContext) SetStrokeWidth(width float32) {
+	r.record("SetStrokeWidth", width)
+}
+
+func (r *RecordingContext) SetStrokeColor(color nanovgo.Color) {
+	r.record("SetStrokeColor", color)
+}
+
+func (r *RecordingContext) SetStrokePaint(paint nanovgo.Paint) {
+	r.record("SetStrokePaint", paint)
+}
+
+func (r *RecordingContext) SetFillColor(color nanovgo.Color) {
+	r.record("SetFillColor", color)
+}
+
+func (r *RecordingContext) SetFillPaint(paint nanovgo.Paint) {
+	r.record("SetFillPaint", paint)
+}
+
+func (r *RecordingContext) SetGlobalAlpha(alpha float32) {
+	r.record("SetGlobalAlpha", alpha)
+}
+
+func (r *RecordingContext) Translate(x, y float32) {
+	r.record("Translate", x, y)
+}
+
+func (r *RecordingContext) Rotate(angle float32) {
+	r.record("Rotate", angle)
+}
+
+func (r *RecordingContext) SkewX(angle float32) {
+	r.record("SkewX", angle)
+}
+
+func (r *RecordingContext) Scissor(x, y, w, h float32) {
+	r.record("Scissor", x, y, w, h)
+}
+
+func (r *RecordingContext) ResetScissor() {
+	r.record("ResetScissor")
+}
+
+func (r *RecordingContext) BeginPath() {
+	r.record("BeginPath")
+}
+
+func (r *RecordingContext) MoveTo(x, y float32) {
+	r.record("MoveTo", x, y)
+}
+
+func (r *RecordingContext) LineTo(x, y float32) {
+	r.record("LineTo", x, y)
+}
+
+func (r *RecordingContext) ClosePath() {
+	r.record("ClosePath")
+}
+
+func (r *RecordingContext) PathWinding(winding nanovgo.Winding) {
+	r.record("PathWinding", winding)
+}
+
+func (r *RecordingContext) Arc(cx, cy, radius, a0, a1 float32, dir nanovgo.Direction) {
+	r.record("Arc", cx, cy, radius, a0, a1, dir)
+}
+
+func (r *RecordingContext) Rect(x, y, w, h float32) {
+	r.record("Rect", x, y, w, h)
+}
+
+func (r *RecordingContext) RoundedRect(x, y, w, h, radius float32) {
+	r.record("RoundedRect", x, y, w, h, radius)
+}
+
+func (r *RecordingContext) Circle(cx, cy, radius float32) {
+	r.record("Circle", cx, cy, radius)
+}
+
+func (r *RecordingContext) Fill() {
+	r.record("Fill")
+}
+
+func (r *RecordingContext) Stroke() {
+	r.record("Stroke")
+}
+
+func (r *RecordingContext) ImageSize(image int) (int, int, error) {
+	size := r.images[image]
+	return size[0], size[1], nil
+}
+
+func (r *RecordingContext) CreateFontFromMemory(name string, data []byte, freeData uint8) int {
+	if font, ok := r.fonts[name]; ok {
+		return font
+	}
+	font := len(r.fonts)
+	r.fonts[name] = font
+	return font
+}
+
+func (r *RecordingContext) SetFontSize(size float32) {
+	r.state().fontSize = size
+	r.record("SetFontSize", size)
+}
+
+func (r *RecordingContext) SetFontBlur(blur float32) {
+	r.record("SetFontBlur", blur)
+}
+
+func (r *RecordingContext) SetTextLineHeight(lineHeight float32) {
+	r.state().lineHeight = lineHeight
+	r.record("SetTextLineHeight", lineHeight)
+}
+
+func (r *RecordingContext) SetTextAlign(align nanovgo.Align) {
+	r.state().align = align
+	r.record("SetTextAlign", align)
+}
+
+func (r *RecordingContext) SetFontFace(font string) {
+	r.state().fontFace = font
+	r.record("SetFontFace", font)
+}
+
+func (r *RecordingContext) Text(x, y float32, str string) float32 {
+	r.record("Text", x, y, str)
+	width := r.textWidth([]rune(str))
+	return r.alignX(x, width) + width
+}
+
+func (r *RecordingContext) TextRune(x, y float32, runes []rune) float32 {
+	r.record("TextRune", x, y, runes)
+	width := r.textWidth(runes)
+	return r.alignX(x, width) + width
+}
+
+func (r *RecordingContext) TextBox(x, y, breakRowWidth float32, str string) {
+	r.record("TextBox", x, y, breakRowWidth, str)
+}
+
+func (r *RecordingContext) TextBounds(x, y float32, str string) (float32, []float32) {
+	width := r.textWidth([]rune(str))
+	minX := r.alignX(x, width)
+	minY := r.alignY(y)
+	return width, []float32{minX, minY, minX + width, minY + r.state().fontSize}
+}
+
+func (r *RecordingContext) TextBoxBounds(x, y, breakRowWidth float32, str string) [4]float32 {
+	state := r.state()
+	lineHeight := state.fontSize * state.lineHeight
+	lines := r.breakLines([]rune(str), breakRowWidth)
+	minY := r.alignY(y)
+	bounds := [4]float32{x, minY, x, minY + lineHeight*float32(len(lines))}
+	for i, line := range lines {
+		width := r.textWidth(line)
+		var minX float32
+		switch {
+		case state.align&nanovgo.AlignCenter != 0:
+			minX = x + (breakRowWidth-width)*0.5
+		case state.align&nanovgo.AlignRight != 0:
+			minX = x + breakRowWidth - width
+		default:
+			minX = x
+		}
+		if i == 0 || minX < bounds[0] {
+			bounds[0] = minX
+		}
+		bounds[2] = maxF(bounds[2], minX+width)
+	}
+	return bounds
+}
+
+func (r *RecordingContext) TextGlyphPositionsRune(x, y float32, runes []rune) []nanovgo.GlyphPosition {
+	positions := make([]nanovgo.GlyphPosition, len(runes))
+	posX := r.alignX(x, r.textWidth(runes))
+	for i, c := range runes {
+		advance := r.advance(c)
+		positions[i] = nanovgo.GlyphPosition{
+			Index: i,
+			Runes: runes,
+			X:     posX,
+			MinX:  posX,
+			MaxX:  posX + advance,
+		}
+		posX += advance
+	}
+	return positions
+}
+
+// TextMetrics() returns the ascender, the descender and the line height of the current font size
+func (r *RecordingContext) TextMetrics() (float32, float32, float32) {
+	state := r.state()
+	return state.fontSize * 0.8, -state.fontSize * 0.2, state.fontSize * state.lineHeight
+}
+
+func (r *RecordingContext) advance(c rune) float32 {
+	size := r.state().fontSize
+	if isWideRune(c) {
+		return size
+	}
+	return size * 0.5
+}
+
+func (r *RecordingContext) textWidth(runes []rune) float32 {
+	var width float32
+	for _, c := range runes {
+		width += r.advance(c)
+	}
+	return width
+}
+
+func (r *RecordingContext) alignX(x, width float32) float32 {
+	align := r.state().align
+	if align&nanovgo.AlignCenter != 0 {
+		return x - width*0.5
+	} else if align&nanovgo.AlignRight != 0 {
+		return x - width
+	}
+	return x
+}
+
+func (r *RecordingContext) alignY(y float32) float32 {
+	state := r.state()
+	if state.align&nanovgo.AlignTop != 0 {
+		return y
+	} else if state.align&nanovgo.AlignMiddle != 0 {
+		return y - state.fontSize*0.5
+	} else if state.align&nanovgo.AlignBottom != 0 {
+		return y - state.fontSize
+	}
+	// baseline
+	return y - state.fontSize*0.8
+}
+
+func (r *RecordingContext) breakLines(runes []rune, breakRowWidth float32) [][]rune {
+	var lines [][]rune
+	for len(runes) > 0 {
+		var width float32
+		end := 0
+		lastSpace := -1
+		for end < len(runes) && runes[end] != '\n' {
+			advance := r.advance(runes[end])
+			if width+advance > breakRowWidth && end > 0 {
+				break
+			}
+			if unicode.IsSpace(runes[end]) {
+				lastSpace = end
+			}
+			width += advance
+			end++
+		}
+		next := end
+		if end < len(runes) && runes[end] != '\n' && lastSpace > 0 {
+			end = lastSpace
+			next = lastSpace + 1
+		} else if end < len(runes) && runes[end] == '\n' {
+			next = end + 1
+		}
+		lines = append(lines, runes[:end])
+		runes = runes[next:]
+	}
+	if len(lines) == 0 {
+		lines = append(lines, nil)
+	}
+	return lines
+}
+
+func isWideRune(c rune) bool {
+	return unicode.In(c, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(c >= 0xff01 && c <= 0xff60) || (c >= 0x3000 && c <= 0x303f)
+}
@@ -0,0 +1,54 @@
+package nanogui
+
+import "testing"
+
+func containsText(texts []string, text string) bool {
+	for _, drawn := range texts {
+		if drawn == text {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHeadlessScreenDrawsIntoRecordingContext(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	if !screen.IsHeadless() {
+		t.Fatal("NewHeadlessScreen() should create a headless screen")
+	}
+	window := NewWindow(screen, "Settings")
+	window.SetLayout(NewGroupLayout())
+	NewLabel(window, "Name")
+	NewButton(window, "Save")
+	screen.PerformLayout()
+	screen.DrawAll()
+
+	rc, ok := screen.Context().(*RecordingContext)
+	if !ok {
+		t.Fatalf("context is %T, want *RecordingContext", screen.Context())
+	}
+	texts := rc.Texts()
+	for _, text := range []string{"Settings", "Name", "Save"} {
+		if !containsText(texts, text) {
+			t.Errorf("%q isn't drawn: %q", text, texts)
+		}
+	}
+	if w, h := window.Size(); w == 0 || h == 0 {
+		t.Errorf("window isn't laid out: %dx%d", w, h)
+	}
+}
+
+func TestRecordingContextMetrics(t *testing.T) {
+	rc := NewRecordingContext()
+	rc.SetFontSize(20)
+	if w, _ := rc.TextBounds(0, 0, "abc"); w != 30 {
+		t.Errorf("narrow glyphs advance by half of the font size: got %v, want 30", w)
+	}
+	if w, _ := rc.TextBounds(0, 0, "日本"); w != 40 {
+		t.Errorf("wide glyphs advance by the font size: got %v, want 40", w)
+	}
+	image := rc.AddImage(16, 8)
+	if w, h, err := rc.ImageSize(image); err != nil || w != 16 || h != 8 {
+		t.Errorf("ImageSize() = %d, %d, %v", w, h, err)
+	}
+}
@@ -0,0 +1,12 @@
+package nanogui
+
+import "testing"
+
+// newTestWindow() creates a headless screen with a window which lays out its children with GroupLayout
+func newTestWindow(t *testing.T) (*Screen, *Window) {
+	t.Helper()
+	screen := NewHeadlessScreen(800, 600)
+	window := NewWindow(screen, "Main")
+	window.SetLayout(NewGroupLayout())
+	return screen, window
+}
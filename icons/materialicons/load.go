@@ -1,13 +1,13 @@
 package materialicons
 
 import (
-	"github.com/shibukawa/nanovgo"
+	"github.com/shibukawa/nanogui.go"
 )
 
-func LoadFont(ctx *nanovgo.Context) {
+func LoadFont(ctx nanogui.DrawContext) {
 	ctx.CreateFontFromMemory("materialicons", MustAsset("font/MaterialIcons-Regular.ttf"), 0)
 }
 
-func LoadFontAs(ctx *nanovgo.Context, name string) {
+func LoadFontAs(ctx nanogui.DrawContext, name string) {
 	ctx.CreateFontFromMemory(name, MustAsset("font/MaterialIcons-Regular.ttf"), 0)
 }
\ No newline at end of file
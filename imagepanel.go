@@ -39,6 +39,7 @@ func (i *ImagePanel) Images() []Image {
 
 func (i *ImagePanel) SetImages(images []Image) {
 	i.images = images
+	i.InvalidateLayout()
 }
 
 func (i *ImagePanel) SetCallback(callback func(int)) {
@@ -58,14 +59,14 @@ func (i *ImagePanel) MouseMotionEvent(self Widget, x, y, relX, relY, button int,
 	return true
 }
 
-func (i *ImagePanel) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+func (i *ImagePanel) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	cols, rows := i.gridSize()
 	w := cols*i.thumbSize + (cols-1)*i.spacing + 2*i.margin
 	h := rows*i.thumbSize + (rows-1)*i.spacing + 2*i.margin
 	return w, h
 }
 
-func (i *ImagePanel) Draw(self Widget, ctx *nanovgo.Context) {
+func (i *ImagePanel) Draw(self Widget, ctx DrawContext) {
 	cols, _ := i.gridSize()
 
 	x := float32(i.x)
@@ -41,7 +41,10 @@ func (i *ImageView) Image() int {
 }
 
 func (i *ImageView) SetImage(image int) {
-	i.image = image
+	if i.image != image {
+		i.image = image
+		i.InvalidateLayout()
+	}
 }
 
 func (i *ImageView) Policy() ImageSizePolicy {
@@ -52,7 +55,7 @@ func (i *ImageView) SetPolicy(policy ImageSizePolicy) {
 	i.policy = policy
 }
 
-func (i *ImageView) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+func (i *ImageView) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	if i.image == 0 {
 		return 0, 0
 	}
@@ -60,7 +63,7 @@ func (i *ImageView) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int)
 	return w, h
 }
 
-func (i *ImageView) Draw(self Widget, ctx *nanovgo.Context) {
+func (i *ImageView) Draw(self Widget, ctx DrawContext) {
 	if i.image == 0 {
 		return
 	}
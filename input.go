@@ -0,0 +1,117 @@
+package nanogui
+
+import (
+	"github.com/shibukawa/glfw"
+)
+
+// InputSimulator injects synthetic input events into a Screen
+//
+// Events go through the same entry points as the GLFW callbacks, so modal window
+// filtering, drag tracking and focus handling behave exactly like real input.
+// Coordinates are logical screen coordinates (the value MousePosition() reports).
+type InputSimulator struct {
+	screen *Screen
+}
+
+// Input() returns an input simulator for automated UI tests
+func (s *Screen) Input() *InputSimulator {
+	return &InputSimulator{screen: s}
+}
+
+// MoveTo() moves the mouse cursor to the given position
+func (i *InputSimulator) MoveTo(x, y int) bool {
+	// cursorPositionCallbackEvent() shifts the raw cursor position by (-1, -2)
+	return i.screen.cursorPositionCallbackEvent(float64(x+1), float64(y+2))
+}
+
+// Press() presses a mouse button at the current cursor position
+func (i *InputSimulator) Press(button glfw.MouseButton, modifiers ...glfw.ModifierKey) bool {
+	return i.screen.mouseButtonCallbackEvent(button, glfw.Press, modifierKeys(modifiers))
+}
+
+// Release() releases a mouse button at the current cursor position
+func (i *InputSimulator) Release(button glfw.MouseButton, modifiers ...glfw.ModifierKey) bool {
+	return i.screen.mouseButtonCallbackEvent(button, glfw.Release, modifierKeys(modifiers))
+}
+
+// Click() clicks the left mouse button at the current cursor position
+func (i *InputSimulator) Click(modifiers ...glfw.ModifierKey) bool {
+	pressed := i.Press(glfw.MouseButton1, modifiers...)
+	released := i.Release(glfw.MouseButton1, modifiers...)
+	return pressed || released
+}
+
+// ClickAt() moves the mouse cursor to the given position and clicks the left mouse button
+func (i *InputSimulator) ClickAt(x, y int, modifiers ...glfw.ModifierKey) bool {
+	i.MoveTo(x, y)
+	return i.Click(modifiers...)
+}
+
+// DoubleClick() clicks the left mouse button twice at the current cursor position
+func (i *InputSimulator) DoubleClick(modifiers ...glfw.ModifierKey) bool {
+	first := i.Click(modifiers...)
+	second := i.Click(modifiers...)
+	return first || second
+}
+
+// Drag() presses the left mouse button at (fromX, fromY), moves to (toX, toY) and releases it
+func (i *InputSimulator) Drag(fromX, fromY, toX, toY int, modifiers ...glfw.ModifierKey) bool {
+	i.MoveTo(fromX, fromY)
+	result := i.Press(glfw.MouseButton1, modifiers...)
+	result = i.MoveTo(toX, toY) || result
+	return i.Release(glfw.MouseButton1, modifiers...) || result
+}
+
+// KeyDown() sends a key press event to the focused widget
+func (i *InputSimulator) KeyDown(key glfw.Key, modifiers glfw.ModifierKey) bool {
+	return i.screen.keyCallbackEvent(key, 0, glfw.Press, modifiers)
+}
+
+// KeyUp() sends a key release event to the focused widget
+func (i *InputSimulator) KeyUp(key glfw.Key, modifiers glfw.ModifierKey) bool {
+	return i.screen.keyCallbackEvent(key, 0, glfw.Release, modifiers)
+}
+
+// PressKey() sends a key press and a key release event to the focused widget
+func (i *InputSimulator) PressKey(key glfw.Key, modifiers glfw.ModifierKey) bool {
+	pressed := i.KeyDown(key, modifiers)
+	released := i.KeyUp(key, modifiers)
+	return pressed || released
+}
+
+// TypeText() sends a character event per rune of text to the focused widget
+func (i *InputSimulator) TypeText(text string) bool {
+	result := false
+	for _, codePoint := range text {
+		result = i.screen.charCallbackEvent(codePoint) || result
+	}
+	return result
+}
+
+// Preedit() sends an IME preedit text update to the focused widget
+func (i *InputSimulator) Preedit(text string, blocks []int, focusedBlock int) {
+	i.screen.preeditCallbackEvent([]rune(text), blocks, focusedBlock)
+}
+
+// CommitPreedit() sends an IME status change event which commits the preedit text
+func (i *InputSimulator) CommitPreedit() {
+	i.screen.imeStatusCallbackEvent()
+}
+
+// Scroll() sends a scroll event at the current cursor position
+func (i *InputSimulator) Scroll(x, y float32) bool {
+	return i.screen.scrollCallbackEvent(x, y)
+}
+
+// Drop() sends a file drop event
+func (i *InputSimulator) Drop(files ...string) bool {
+	return i.screen.dropCallbackEvent(files)
+}
+
+func modifierKeys(modifiers []glfw.ModifierKey) glfw.ModifierKey {
+	var result glfw.ModifierKey
+	for _, modifier := range modifiers {
+		result |= modifier
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package nanogui
+
+import (
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+func TestInputSimulatorClickAndType(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	window := NewWindow(screen, "Settings")
+	window.SetLayout(NewGroupLayout())
+	textBox := NewTextBox(window, "hello")
+	textBox.SetEditable(true)
+	button := NewButton(window, "Save")
+	clicked := 0
+	button.SetCallback(func() { clicked++ })
+	screen.PerformLayout()
+	screen.DrawAll()
+
+	in := screen.Input()
+	bx, by := button.AbsolutePosition()
+	in.ClickAt(bx+5, by+5)
+	if clicked != 1 {
+		t.Fatalf("button callback is called %d times, want 1", clicked)
+	}
+
+	tx, ty := textBox.AbsolutePosition()
+	in.ClickAt(tx+5, ty+5)
+	screen.DrawAll()
+	if !textBox.Focused() {
+		t.Fatal("clicking the text box should focus it")
+	}
+	in.PressKey(glfw.KeyEnd, 0)
+	in.TypeText("abc")
+	in.PressKey(glfw.KeyEnter, 0)
+	if textBox.Value() != "helloabc" {
+		t.Errorf("Value() = %q, want %q", textBox.Value(), "helloabc")
+	}
+}
+
+func TestInputSimulatorRespectsModalWindows(t *testing.T) {
+	screen, window := newTestWindow(t)
+	button := NewButton(window, "Behind")
+	clicked := false
+	button.SetCallback(func() { clicked = true })
+	screen.PerformLayout()
+
+	modal := NewWindow(screen, "Modal")
+	modal.SetModal(true)
+	modal.SetPosition(400, 400)
+	modal.SetSize(100, 100)
+	modal.RequestFocus(modal)
+	screen.DrawAll()
+
+	bx, by := button.AbsolutePosition()
+	screen.Input().ClickAt(bx+5, by+5)
+	if clicked {
+		t.Error("a widget behind a modal window received the click")
+	}
+}
@@ -23,23 +23,54 @@ const (
 	EditActionCopy
 	EditActionCut
 	EditActionPaste
+	EditActionMoveUp
+	EditActionMoveDown
+	EditActionPageUp
+	EditActionPageDown
+	EditActionUndo
+	EditActionRedo
+	EditActionMoveLeftWord
+	EditActionMoveRightWord
+	EditActionDeleteRightWord
 )
 
 func DetectEditAction(key glfw.Key, modifier glfw.ModifierKey) EditAction {
 	isMac := runtime.GOOS == "darwin"
 	switch key {
 	case glfw.KeyLeft:
+		if isWordModifier(modifier) {
+			return EditActionMoveLeftWord
+		}
 		return EditActionMoveLeft
 	case glfw.KeyB:
 		if modifier == glfw.ModControl {
 			return EditActionMoveLeft
 		}
 	case glfw.KeyRight:
+		if isWordModifier(modifier) {
+			return EditActionMoveRightWord
+		}
 		return EditActionMoveRight
 	case glfw.KeyF:
 		if modifier == glfw.ModControl {
 			return EditActionMoveRight
 		}
+	case glfw.KeyUp:
+		return EditActionMoveUp
+	case glfw.KeyP:
+		if modifier == glfw.ModControl {
+			return EditActionMoveUp
+		}
+	case glfw.KeyDown:
+		return EditActionMoveDown
+	case glfw.KeyN:
+		if modifier == glfw.ModControl {
+			return EditActionMoveDown
+		}
+	case glfw.KeyPageUp:
+		return EditActionPageUp
+	case glfw.KeyPageDown:
+		return EditActionPageDown
 	case glfw.KeyHome:
 		return EditActionMoveLineTop
 	case glfw.KeyA:
@@ -59,6 +90,9 @@ func DetectEditAction(key glfw.Key, modifier glfw.ModifierKey) EditAction {
 			return EditActionMoveLineEnd
 		}
 	case glfw.KeyBackspace:
+		if isWordModifier(modifier) {
+			return EditActionDeleteLeftWord
+		}
 		return EditActionBackspace
 	case glfw.KeyH:
 		if modifier == glfw.ModControl {
@@ -67,6 +101,8 @@ func DetectEditAction(key glfw.Key, modifier glfw.ModifierKey) EditAction {
 	case glfw.KeyDelete:
 		if modifier == glfw.ModAlt {
 			return EditActionDeleteLeftWord
+		} else if isWordModifier(modifier) {
+			return EditActionDeleteRightWord
 		}
 		return EditActionDelete
 	case glfw.KeyD:
@@ -95,6 +131,18 @@ func DetectEditAction(key glfw.Key, modifier glfw.ModifierKey) EditAction {
 		if (!isMac && modifier == glfw.ModControl) || (isMac && modifier == glfw.ModSuper) {
 			return EditActionPaste
 		}
+	case glfw.KeyZ:
+		if (!isMac && modifier == glfw.ModControl) || (isMac && modifier == glfw.ModSuper) {
+			return EditActionUndo
+		} else if (!isMac && modifier == glfw.ModControl|glfw.ModShift) || (isMac && modifier == glfw.ModSuper|glfw.ModShift) {
+			return EditActionRedo
+		}
 	}
 	return EditActionNone
 }
+
+// isWordModifier() reports whether Ctrl or Alt (optionally with Shift) is held, which makes cursor actions word-wise
+func isWordModifier(modifier glfw.ModifierKey) bool {
+	modifier &^= glfw.ModShift
+	return modifier == glfw.ModControl || modifier == glfw.ModAlt
+}
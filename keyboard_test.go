@@ -0,0 +1,51 @@
+package nanogui
+
+import (
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+func TestKeyboardActivation(t *testing.T) {
+	screen, window := newTestWindow(t)
+	button := NewButton(window, "button")
+	clicked := 0
+	button.SetCallback(func() { clicked++ })
+	checkBox := NewCheckBox(window, "check")
+	slider := NewSlider(window)
+	slider.SetValue(0.5)
+	comboBox := NewComboBox(window, []string{"a", "b", "c"})
+	selected := -1
+	comboBox.SetCallback(func(index int) { selected = index })
+	screen.PerformLayout()
+
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.PressKey(glfw.KeySpace, 0)
+	if clicked != 1 || button.Pushed() {
+		t.Fatalf("Space should click the button once: called %d times, pushed %v", clicked, button.Pushed())
+	}
+	in.PressKey(glfw.KeyTab, 0)
+	in.PressKey(glfw.KeyEnter, 0)
+	if !checkBox.Checked() {
+		t.Fatal("Enter should toggle the check box")
+	}
+	in.PressKey(glfw.KeyTab, 0)
+	in.PressKey(glfw.KeyRight, 0)
+	if slider.Value() <= 0.5 {
+		t.Fatalf("Right should increase the slider value: got %v", slider.Value())
+	}
+	in.PressKey(glfw.KeyTab, 0)
+	in.PressKey(glfw.KeyDown, 0)
+	if selected != 1 || comboBox.SelectedIndex() != 1 {
+		t.Fatalf("Down should select the next item: callback %d, SelectedIndex() %d", selected, comboBox.SelectedIndex())
+	}
+	in.PressKey(glfw.KeySpace, 0)
+	if !comboBox.Pushed() {
+		t.Fatal("Space should open the combo box popup")
+	}
+	in.PressKey(glfw.KeyEscape, 0)
+	if comboBox.Pushed() {
+		t.Fatal("Escape should close the combo box popup")
+	}
+}
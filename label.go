@@ -15,6 +15,17 @@ type Label struct {
 	color       nanovgo.Color
 	columnWidth int
 	wrap        bool
+	direction   TextDirection
+	wrapped     labelLines
+}
+
+// labelLines caches the wrapped lines of a caption
+type labelLines struct {
+	caption string
+	font    string
+	size    int
+	width   int
+	lines   []string
 }
 
 func NewLabel(parent Widget, caption string) *Label {
@@ -34,7 +45,11 @@ func (l *Label) Caption() string {
 
 // SetCaption() sets the label's text caption
 func (l *Label) SetCaption(caption string) {
-	l.caption = caption
+	if l.caption != caption {
+		l.caption = caption
+		l.wrapped = labelLines{}
+		l.InvalidateLayout()
+	}
 }
 
 // Font() gets the currently active font
@@ -47,7 +62,11 @@ func (l *Label) Font() string {
 
 // SetFont() sets the currently active font (2 are available by default: 'sans' and 'sans-bold')
 func (l *Label) SetFont(fontFace string) {
-	l.fontFace = fontFace
+	if l.fontFace != fontFace {
+		l.fontFace = fontFace
+		l.wrapped = labelLines{}
+		l.InvalidateLayout()
+	}
 }
 
 // Color() gets the label color
@@ -65,7 +84,11 @@ func (l *Label) ColumnWidth() int {
 }
 
 func (l *Label) SetColumnWidth(width int) {
-	l.columnWidth = width
+	if l.columnWidth != width {
+		l.columnWidth = width
+		l.wrapped = labelLines{}
+		l.InvalidateLayout()
+	}
 }
 
 func (l *Label) Wrap() bool {
@@ -73,10 +96,25 @@ func (l *Label) Wrap() bool {
 }
 
 func (l *Label) SetWrap(wrap bool) {
-	l.wrap = wrap
+	if l.wrap != wrap {
+		l.wrap = wrap
+		l.wrapped = labelLines{}
+		l.InvalidateLayout()
+	}
+}
+
+// TextDirection() returns the paragraph direction of the caption
+func (l *Label) TextDirection() TextDirection {
+	return l.direction
 }
 
-func (l *Label) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+// SetTextDirection() sets the paragraph direction of the caption (default: TextDirectionAuto).
+// Right-to-left captions are aligned to the right edge
+func (l *Label) SetTextDirection(direction TextDirection) {
+	l.direction = direction
+}
+
+func (l *Label) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	if l.caption == "" {
 		return 0, 0
 	}
@@ -92,8 +130,9 @@ func (l *Label) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
 
 	if width > 0 {
 		ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignTop)
-		bounds := ctx.TextBoxBounds(0, 0, float32(width), l.caption)
-		return width, int(bounds[3] - bounds[1])
+		_, _, lineHeight := ctx.TextMetrics()
+		lines := l.wrappedLines(ctx, width)
+		return width, int(lineHeight * float32(len(lines)))
 	} else {
 		ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignTop)
 		w, _ := ctx.TextBounds(0, 0, l.caption)
@@ -101,7 +140,7 @@ func (l *Label) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
 	}
 }
 
-func (l *Label) Draw(self Widget, ctx *nanovgo.Context) {
+func (l *Label) Draw(self Widget, ctx DrawContext) {
 	l.WidgetImplement.Draw(self, ctx)
 	ctx.SetFontSize(float32(l.FontSize()))
 	ctx.SetFontFace(l.Font())
@@ -114,13 +153,33 @@ func (l *Label) Draw(self Widget, ctx *nanovgo.Context) {
 		width = l.columnWidth
 	}
 
+	rtl := resolveParagraphRTL([]rune(l.caption), l.direction)
+	x := float32(l.x)
+	align := nanovgo.AlignLeft
+	if rtl {
+		x += float32(l.w)
+		align = nanovgo.AlignRight
+	}
 	if width > 0 {
-		ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignTop)
-		ctx.TextBox(float32(l.x), float32(l.y), float32(width), l.caption)
+		ctx.SetTextAlign(align | nanovgo.AlignTop)
+		_, _, lineHeight := ctx.TextMetrics()
+		for i, line := range l.wrappedLines(ctx, width) {
+			ctx.Text(x, float32(l.y)+lineHeight*float32(i), string(newBidiLine([]rune(line), rtl).visual))
+		}
 	} else {
-		ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
-		ctx.Text(float32(l.x), float32(l.y)+float32(l.h)*0.5, l.caption)
+		ctx.SetTextAlign(align | nanovgo.AlignMiddle)
+		ctx.Text(x, float32(l.y)+float32(l.h)*0.5, string(newBidiLine([]rune(l.caption), rtl).visual))
+	}
+}
+
+// wrappedLines() returns the caption wrapped in width. The font of ctx should be set already
+func (l *Label) wrappedLines(ctx DrawContext, width int) []string {
+	font, size := l.Font(), l.FontSize()
+	cache := &l.wrapped
+	if cache.lines == nil || cache.caption != l.caption || cache.font != font || cache.size != size || cache.width != width {
+		*cache = labelLines{caption: l.caption, font: font, size: size, width: width, lines: wrapText(ctx, l.caption, float32(width))}
 	}
+	return cache.lines
 }
 
 func (l *Label) String() string {
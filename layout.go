@@ -2,7 +2,6 @@ package nanogui
 
 import (
 	"fmt"
-	"github.com/shibukawa/nanovgo"
 )
 
 type Alignment uint8
@@ -48,12 +47,165 @@ func (o Orientation) String() string {
 
 }
 
+// SizePolicy tells layouts how a widget may be resized along an axis
+//
+// The preferred size is the size hint which the policy refers to. The minimum and
+// the maximum size (see Widget.SetMinimumSize(), Widget.SetMaximumSize()) always
+// bound the result, and a fixed size overrides all of them.
+type SizePolicy int
+
+const (
+	// SizePreferred is the default: the preferred size is best, but the widget can grow and shrink
+	SizePreferred SizePolicy = iota
+	// SizeFixed uses the preferred size only
+	SizeFixed
+	// SizeMinimum uses the preferred size as minimum. The widget can grow
+	SizeMinimum
+	// SizeMaximum uses the preferred size as maximum. The widget can shrink
+	SizeMaximum
+	// SizeExpanding can grow and shrink like SizePreferred, and takes the extra space before the others
+	SizeExpanding
+)
+
+func (p SizePolicy) String() string {
+	switch p {
+	case SizePreferred:
+		return "Preferred"
+	case SizeFixed:
+		return "Fixed"
+	case SizeMinimum:
+		return "Minimum"
+	case SizeMaximum:
+		return "Maximum"
+	case SizeExpanding:
+		return "Expanding"
+	}
+	panic("you should not reach here")
+	return ""
+}
+
+// CanGrow() returns whether or not the widget can be larger than the preferred size
+func (p SizePolicy) CanGrow() bool {
+	return p == SizePreferred || p == SizeMinimum || p == SizeExpanding
+}
+
+// CanShrink() returns whether or not the widget can be smaller than the preferred size
+func (p SizePolicy) CanShrink() bool {
+	return p == SizePreferred || p == SizeMaximum || p == SizeExpanding
+}
+
+// LayoutSize() returns the size which layouts should reserve for the widget: the fixed size if
+// it is set, otherwise the preferred size bounded by the minimum and the maximum size
+func LayoutSize(widget Widget, ctx DrawContext) (int, int) {
+	pW, pH := widget.CachedPreferredSize(widget, ctx)
+	fW, fH := widget.FixedSize()
+	minW, minH := widget.MinimumSize()
+	maxW, maxH := widget.MaximumSize()
+	return boundSize(toI(fW > 0, fW, pW), minW, maxW, fW), boundSize(toI(fH > 0, fH, pH), minH, maxH, fH)
+}
+
+// FitSize() returns the size of the widget along the axis (0: horizontal, 1: vertical) when a layout
+// offers space to it. size is the result of LayoutSize() on that axis.
+//
+// The fixed size is kept. Otherwise the space is accepted as far as the size policy and the minimum/maximum
+// size allow it.
+func FitSize(widget Widget, axis, size, space int) int {
+	fW, fH := widget.FixedSize()
+	if fixed := toI(axis == 0, fW, fH); fixed > 0 {
+		return fixed
+	}
+	hPolicy, vPolicy := widget.SizePolicy()
+	policy := toPolicy(axis == 0, hPolicy, vPolicy)
+	minW, minH := widget.MinimumSize()
+	maxW, maxH := widget.MaximumSize()
+	lower := toI(axis == 0, minW, minH)
+	upper := toI(axis == 0, maxW, maxH)
+	if !policy.CanGrow() {
+		space = minI(space, size)
+	}
+	if !policy.CanShrink() {
+		space = maxI(space, size)
+	}
+	return boundSize(space, lower, upper, 0)
+}
+
+// IsExpanding() returns whether or not the widget asks for the extra space along the axis
+func IsExpanding(widget Widget, axis int) bool {
+	if toI(axis == 0, widget.FixedWidth(), widget.FixedHeight()) > 0 {
+		return false
+	}
+	hPolicy, vPolicy := widget.SizePolicy()
+	return toPolicy(axis == 0, hPolicy, vPolicy) == SizeExpanding
+}
+
+// boundSize() limits size to [lower, upper]. Zero bound means unbounded, and a fixed size isn't limited
+func boundSize(size, lower, upper, fixed int) int {
+	if fixed > 0 {
+		return fixed
+	}
+	if upper > 0 && size > upper {
+		size = upper
+	}
+	if lower > 0 && size < lower {
+		size = lower
+	}
+	return size
+}
+
+// shareSpace() adds extra to sizes evenly. The entries which reach their limits stop growing, and
+// the rest of their share goes to the others
+func shareSpace(sizes, limits []int, extra int) {
+	frozen := make([]bool, len(sizes))
+	for extra > 0 {
+		count := 0
+		for i := range sizes {
+			if !frozen[i] {
+				count++
+			}
+		}
+		if count == 0 {
+			return
+		}
+		share := extra / count
+		rest := extra - share*count
+		for i := range sizes {
+			if frozen[i] {
+				continue
+			}
+			add := share
+			if rest > 0 {
+				add++
+				rest--
+			}
+			if sizes[i]+add >= limits[i] {
+				add = maxI(limits[i]-sizes[i], 0)
+				frozen[i] = true
+			}
+			sizes[i] += add
+			extra -= add
+		}
+	}
+}
+
+func toPolicy(condition bool, a, b SizePolicy) SizePolicy {
+	if condition {
+		return a
+	}
+	return b
+}
+
 type Layout interface {
-	OnPerformLayout(widget Widget, ctx *nanovgo.Context)
-	PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int)
+	OnPerformLayout(widget Widget, ctx DrawContext)
+	PreferredSize(widget Widget, ctx DrawContext) (int, int)
 	String() string
 }
 
+// layoutCache is implemented by layouts which memoize intermediate results.
+// Widgets drop them when their layout is invalidated
+type layoutCache interface {
+	invalidate()
+}
+
 // Simple horizontal/vertical box layout
 //
 // This widget stacks up a bunch of widgets horizontally or vertically. It adds
@@ -119,7 +271,7 @@ func (b *BoxLayout) SetSpacing(s int) {
 	b.spacing = s
 }
 
-func (b *BoxLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
+func (b *BoxLayout) OnPerformLayout(widget Widget, ctx DrawContext) {
 	fX, fY := widget.FixedSize()
 	var containerSize [2]int
 	if fX > 0 {
@@ -145,23 +297,41 @@ func (b *BoxLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 			yOffset = widget.Theme().WindowHeaderHeight
 		}
 	}
-	first := true
+	var children []Widget
+	var sizes [][2]int
+	used := position + b.margin
 	for _, child := range widget.Children() {
 		if !child.Visible() {
 			continue
 		}
-		if first {
-			first = false
-		} else {
-			position += b.spacing
+		if len(children) > 0 {
+			used += b.spacing
+		}
+		w, h := LayoutSize(child, ctx)
+		children = append(children, child)
+		sizes = append(sizes, [2]int{w, h})
+		used += sizes[len(sizes)-1][axis1]
+	}
+	// the remained space goes to the expanding children
+	if extra := containerSize[axis1] - used; extra > 0 {
+		var indices, grown, limits []int
+		for i, child := range children {
+			if IsExpanding(child, axis1) {
+				indices = append(indices, i)
+				grown = append(grown, sizes[i][axis1])
+				limits = append(limits, FitSize(child, axis1, sizes[i][axis1], sizes[i][axis1]+extra))
+			}
+		}
+		shareSpace(grown, limits, extra)
+		for j, i := range indices {
+			sizes[i][axis1] = grown[j]
 		}
-		var fs [2]int
-		pW, pH := child.PreferredSize(child, ctx)
-		fs[0], fs[1] = child.FixedSize()
-		targetSize := [2]int{
-			toI(fs[0] > 0, fs[0], pW),
-			toI(fs[1] > 0, fs[1], pH),
+	}
+	for i, child := range children {
+		if i > 0 {
+			position += b.spacing
 		}
+		targetSize := sizes[i]
 		var pos [2]int
 		pos[1] = yOffset
 		pos[axis1] = position
@@ -175,20 +345,16 @@ func (b *BoxLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 			pos[axis2] += containerSize[axis2] - yOffset - targetSize[axis2] - b.margin
 		case Fill:
 			pos[axis2] += b.margin
-			if fs[axis2] > 0 {
-				targetSize[axis2] = fs[axis2]
-			} else {
-				targetSize[axis2] = containerSize[axis2] - yOffset - b.margin*2
-			}
+			targetSize[axis2] = FitSize(child, axis2, targetSize[axis2], containerSize[axis2]-yOffset-b.margin*2)
 		}
 		child.SetPosition(pos[0], pos[1])
 		child.SetSize(targetSize[0], targetSize[1])
-		child.OnPerformLayout(child, ctx)
+		child.LayoutIfNeeded(child, ctx)
 		position += targetSize[axis1]
 	}
 }
 
-func (b *BoxLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int) {
+func (b *BoxLayout) PreferredSize(widget Widget, ctx DrawContext) (int, int) {
 	size := []int{2 * b.margin, 2 * b.margin}
 
 	axis2Offset := 0
@@ -214,12 +380,8 @@ func (b *BoxLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int
 			size[axis1] += b.spacing
 		}
 
-		pW, pH := child.PreferredSize(child, ctx)
-		fW, fH := child.FixedSize()
-		targetSize := [2]int{
-			toI(fW > 0, fW, pW),
-			toI(fH > 0, fH, pH),
-		}
+		var targetSize [2]int
+		targetSize[0], targetSize[1] = LayoutSize(child, ctx)
 		size[axis1] += targetSize[axis1]
 		size[axis2] = maxI(size[axis2], targetSize[axis2]+2*b.margin+axis2Offset)
 	}
@@ -322,7 +484,7 @@ func (g *GroupLayout) SetGroupSpacing(s int) {
 	g.groupSpacing = s
 }
 
-func (g *GroupLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
+func (g *GroupLayout) OnPerformLayout(widget Widget, ctx DrawContext) {
 	height := g.margin
 	availableWidth := -g.margin * 2
 	availableWidth += toI(widget.FixedWidth() > 0, widget.FixedWidth(), widget.Width())
@@ -330,41 +492,63 @@ func (g *GroupLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 	if ok && window.Title() != "" {
 		height += widget.Theme().WindowHeaderHeight - g.margin/2
 	}
-	first := true
 	indent := false
 
+	var children []Widget
+	var indents, spacings, heights, widths []int
+	used := height + g.margin
 	for _, child := range widget.Children() {
 		if !child.Visible() {
 			continue
 		}
 		label, ok := child.(*Label)
-		if !first {
-			height += toI(ok, g.groupSpacing, g.spacing)
+		var spacing int
+		if len(children) > 0 {
+			spacing = toI(ok, g.groupSpacing, g.spacing)
 		}
-		first = false
 		var indentValue int
-
 		if indent && !ok {
 			indentValue = g.groupIndent
 		}
-
-		pW := availableWidth - indentValue
-		_, pH := child.PreferredSize(child, ctx)
-		fW, fH := child.FixedSize()
-		tW := toI(fW > 0, fW, pW)
-		tH := toI(fH > 0, fH, pH)
-		child.SetPosition(g.margin+indentValue, height)
-		child.SetSize(tW, tH)
-		child.OnPerformLayout(child, ctx)
-		height += tH
+		lW, lH := LayoutSize(child, ctx)
+		children = append(children, child)
+		indents = append(indents, indentValue)
+		spacings = append(spacings, spacing)
+		widths = append(widths, FitSize(child, 0, lW, availableWidth-indentValue))
+		heights = append(heights, lH)
+		used += spacing + lH
 
 		if ok {
 			indent = label.Caption() != ""
 		}
 	}
+	// the remained height goes to the expanding children
+	containerHeight := toI(widget.FixedHeight() > 0, widget.FixedHeight(), widget.Height())
+	if extra := containerHeight - used; extra > 0 {
+		var indices, grown, limits []int
+		for i, child := range children {
+			if IsExpanding(child, 1) {
+				indices = append(indices, i)
+				grown = append(grown, heights[i])
+				limits = append(limits, FitSize(child, 1, heights[i], heights[i]+extra))
+			}
+		}
+		shareSpace(grown, limits, extra)
+		for j, i := range indices {
+			heights[i] = grown[j]
+		}
+	}
+
+	for i, child := range children {
+		height += spacings[i]
+		child.SetPosition(g.margin+indents[i], height)
+		child.SetSize(widths[i], heights[i])
+		child.LayoutIfNeeded(child, ctx)
+		height += heights[i]
+	}
 }
 
-func (g *GroupLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int) {
+func (g *GroupLayout) PreferredSize(widget Widget, ctx DrawContext) (int, int) {
 	height := g.margin
 	width := g.margin * 2
 
@@ -384,10 +568,7 @@ func (g *GroupLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, i
 			height += toI(ok, g.groupSpacing, g.spacing)
 		}
 		first = false
-		pW, pH := child.PreferredSize(child, ctx)
-		fW, fH := child.FixedSize()
-		tW := toI(fW > 0, fW, pW)
-		tH := toI(fH > 0, fH, pH)
+		tW, tH := LayoutSize(child, ctx)
 		var indentValue int
 		if indent && !ok {
 			indentValue = g.groupIndent
@@ -407,6 +588,61 @@ func (g *GroupLayout) String() string {
 	return "GroupLayout"
 }
 
+// GridCell is the placement of a child widget of a GridLayout
+type GridCell struct {
+	pos  [2]int
+	span [2]int
+}
+
+// NewGridCell() creates a cell setting placed at the column col and the row row
+//
+// Negative col or row is chosen by the auto placement. The optional parameters are the
+// number of the columns and the rows the widget spans.
+func NewGridCell(col, row int, span ...int) GridCell {
+	c := GridCell{
+		pos:  [2]int{col, row},
+		span: [2]int{1, 1},
+	}
+	switch len(span) {
+	case 0:
+	case 1:
+		c.span[0] = span[0]
+	case 2:
+		c.span[0] = span[0]
+		c.span[1] = span[1]
+	default:
+		panic("NewGridCell can accept extra parameter upto 2 (colSpan, rowSpan).")
+	}
+	return c
+}
+
+// NewGridSpan() creates an automatically placed cell setting which spans colSpan columns and rowSpan rows
+func NewGridSpan(colSpan, rowSpan int) GridCell {
+	return NewGridCell(-1, -1, colSpan, rowSpan)
+}
+
+// Col() returns the column of the cell. It is negative when the column is placed automatically
+func (c GridCell) Col() int {
+	return c.pos[0]
+}
+
+// Row() returns the row of the cell. It is negative when the row is placed automatically
+func (c GridCell) Row() int {
+	return c.pos[1]
+}
+
+func (c GridCell) ColSpan() int {
+	return c.span[0]
+}
+
+func (c GridCell) RowSpan() int {
+	return c.span[1]
+}
+
+func (c *GridCell) String() string {
+	return fmt.Sprintf("GridCell[pos=(%d, %d), span=(%d, %d)]", c.pos[0], c.pos[1], c.span[0], c.span[1])
+}
+
 // Grid layout
 //
 // Widgets are arranged in a grid that has a fixed grid resolution \c resolution
@@ -414,6 +650,10 @@ func (g *GroupLayout) String() string {
 // widgets are also appended on this axis. The spacing between items can be
 // specified per axis. The horizontal/vertical alignment can be specified per
 // row and column.
+//
+// A widget can span several rows and columns, or be put on an explicit cell with SetCell().
+// The other widgets fill the first free cells in tree order (dense auto placement), and
+// lines are appended along the other axis as needed.
 
 type GridLayout struct {
 	orientation       Orientation
@@ -422,6 +662,18 @@ type GridLayout struct {
 	alignments        [2][]Alignment
 	margin            int
 	spacing           []int
+	cells             map[Widget]GridCell
+	cacheWidget       Widget
+	cacheGrid         [][]int
+	cacheExpanding    [][]bool
+	cacheItems        []gridItem
+}
+
+// gridItem is a visible child widget and the cells it occupies
+type gridItem struct {
+	widget Widget
+	pos    [2]int
+	span   [2]int
 }
 
 func NewGridLayout(orientation Orientation, resolution int, alignment Alignment, setting ...int) *GridLayout {
@@ -443,6 +695,7 @@ func NewGridLayout(orientation Orientation, resolution int, alignment Alignment,
 		alignments:        [2][]Alignment{{}, {}},
 		margin:            margin,
 		spacing:           []int{spacing, spacing},
+		cells:             make(map[Widget]GridCell),
 	}
 }
 
@@ -452,6 +705,7 @@ func (g *GridLayout) Orientation() Orientation {
 
 func (g *GridLayout) SetOrientation(o Orientation) {
 	g.orientation = o
+	g.invalidate()
 }
 
 func (g *GridLayout) Resolution() int {
@@ -460,6 +714,7 @@ func (g *GridLayout) Resolution() int {
 
 func (g *GridLayout) SetResolution(r int) {
 	g.resolution = r
+	g.invalidate()
 }
 
 func (g *GridLayout) ColDefaultAlignment() Alignment {
@@ -525,7 +780,22 @@ func (g *GridLayout) SetRowSpacing(s int) {
 	g.spacing[1] = s
 }
 
-func (g *GridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
+// SetCell() sets the placement of a child widget. The setting is dropped when the widget is removed from the container
+func (g *GridLayout) SetCell(widget Widget, cell GridCell) {
+	g.cells[widget] = cell
+	g.invalidate()
+	widget.InvalidateLayout()
+}
+
+// Cell() returns the placement of the widget. Widgets without setting are placed automatically on a single cell
+func (g *GridLayout) Cell(widget Widget) GridCell {
+	if cell, ok := g.cells[widget]; ok {
+		return cell
+	}
+	return NewGridSpan(1, 1)
+}
+
+func (g *GridLayout) OnPerformLayout(widget Widget, ctx DrawContext) {
 	fw, fh := widget.FixedSize()
 	containerSize := []int{
 		toI(fw > 0, fw, widget.Width()),
@@ -533,7 +803,8 @@ func (g *GridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 	}
 
 	/* Compute minimum row / column sizes */
-	grid := g.computeLayout(widget, ctx)
+	cached, expanding, items := g.grid(widget, ctx)
+	grid := [][]int{append([]int{}, cached[0]...), append([]int{}, cached[1]...)}
 	dim := []int{len(grid[0]), len(grid[1])}
 
 	extra := []int{0, 0}
@@ -546,81 +817,65 @@ func (g *GridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
 		gridSize := g.margin*2 + extra[i]
 		for _, s := range grid[i] {
 			gridSize += s
-			if i+1 < dim[i] {
-				gridSize += g.spacing[i]
-			}
 		}
+		gridSize += maxI(dim[i]-1, 0) * g.spacing[i]
 
 		if gridSize < containerSize[i] {
-			gap := containerSize[i] - gridSize
-			g := gap / dim[i]
-			rest := gap - g*dim[i]
+			/* Rows / columns with expanding widgets take the gap if any */
+			var targets []int
 			for j := 0; j < dim[i]; j++ {
-				grid[i][j] += g
+				if expanding[i][j] {
+					targets = append(targets, j)
+				}
 			}
-			for j := 0; rest > 0 && j < dim[i]; j++ {
-				grid[i][j]++
-				rest--
+			if len(targets) == 0 {
+				for j := 0; j < dim[i]; j++ {
+					targets = append(targets, j)
+				}
 			}
+			distribute(grid[i], targets, containerSize[i]-gridSize)
 		}
 	}
 
-	axis1 := int(g.orientation)
-	axis2 := (int(g.orientation) + 1) % 2
-	start := []int{g.margin, g.margin + extra[1]}
-	pos := []int{start[0], start[1]}
-	numChildren := widget.ChildCount()
-	child := 0
-	children := widget.Children()
-
-	for i2 := 0; i2 < dim[axis2]; i2++ {
-		pos[axis1] = start[axis1]
-		for i1 := 0; i1 < dim[axis1]; i1++ {
-			var w Widget
-			for {
-				if child >= numChildren {
-					return
-				}
-				w = children[child]
-				child++
-				if w.Visible() {
-					break
-				}
-			}
-			pw, ph := w.PreferredSize(w, ctx)
-			fw, fh := w.FixedSize()
-			fs := []int{fw, fh}
-			targetSize := []int{
-				toI(fw > 0, fw, pw),
-				toI(fh > 0, fh, ph),
-			}
-			itemPos := []int{pos[0], pos[1]}
-			for j := 0; j < 2; j++ {
-				axis := (axis1 + j) % 2
-				item := toI(j == 0, i1, i2)
-				align := g.Alignment(axis, item)
-
-				switch align {
-				case Minimum:
-				case Middle:
-					itemPos[axis] += (grid[axis][item] - targetSize[axis]) / 2
-				case Maximum:
-					itemPos[axis] += grid[axis][item] - targetSize[axis]
-				case Fill:
-					targetSize[axis] = toI(fs[axis] > 0, fs[axis], grid[axis][item])
-				}
+	/* Compute the start position of each row / column */
+	offsets := make([][]int, 2)
+	for i := 0; i < 2; i++ {
+		offsets[i] = make([]int, dim[i]+1)
+		offsets[i][0] = g.margin + extra[i]
+		for j, s := range grid[i] {
+			offsets[i][j+1] = offsets[i][j] + s + g.spacing[i]
+		}
+	}
+
+	for _, item := range items {
+		w := item.widget
+		lw, lh := LayoutSize(w, ctx)
+		targetSize := []int{lw, lh}
+		itemPos := []int{0, 0}
+		for axis := 0; axis < 2; axis++ {
+			first := item.pos[axis]
+			last := first + item.span[axis]
+			itemPos[axis] = offsets[axis][first]
+			cellSize := offsets[axis][last] - offsets[axis][first] - g.spacing[axis]
+
+			switch g.Alignment(axis, first) {
+			case Minimum:
+			case Middle:
+				itemPos[axis] += (cellSize - targetSize[axis]) / 2
+			case Maximum:
+				itemPos[axis] += cellSize - targetSize[axis]
+			case Fill:
+				targetSize[axis] = FitSize(w, axis, targetSize[axis], cellSize)
 			}
-			w.SetPosition(itemPos[0], itemPos[1])
-			w.SetSize(targetSize[0], targetSize[1])
-			w.OnPerformLayout(w, ctx)
-			pos[axis1] += grid[axis1][i1] + g.spacing[axis1]
 		}
-		pos[axis2] += grid[axis2][i2] + g.spacing[axis2]
+		w.SetPosition(itemPos[0], itemPos[1])
+		w.SetSize(targetSize[0], targetSize[1])
+		w.LayoutIfNeeded(w, ctx)
 	}
 }
 
-func (g *GridLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int) {
-	grid := g.computeLayout(widget, ctx)
+func (g *GridLayout) PreferredSize(widget Widget, ctx DrawContext) (int, int) {
+	grid, _, _ := g.grid(widget, ctx)
 
 	w := g.margin*2 + maxI(len(grid[0])-1, 0)*g.spacing[0]
 	for _, v := range grid[0] {
@@ -636,50 +891,198 @@ func (g *GridLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, in
 	return w, h
 }
 
-func (g *GridLayout) computeLayout(widget Widget, ctx *nanovgo.Context) [][]int {
+// grid() returns the result of computeLayout() memoized until the layout of widget is invalidated
+func (g *GridLayout) grid(widget Widget, ctx DrawContext) ([][]int, [][]bool, []gridItem) {
+	if g.cacheWidget != widget {
+		g.cacheItems = g.place(widget)
+		g.cacheGrid, g.cacheExpanding = g.computeLayout(g.cacheItems, ctx)
+		g.cacheWidget = widget
+	}
+	return g.cacheGrid, g.cacheExpanding, g.cacheItems
+}
+
+func (g *GridLayout) invalidate() {
+	g.cacheWidget = nil
+	g.cacheGrid = nil
+	g.cacheExpanding = nil
+	g.cacheItems = nil
+}
+
+// place() decides the cells of the visible children
+//
+// Widgets placed on both axes come first, then the widgets whose line is given, then the
+// widgets whose position on the fixed axis is given, and the rest fill the first free cells.
+func (g *GridLayout) place(widget Widget) []gridItem {
 	axis1 := int(g.orientation)
 	axis2 := (int(g.orientation) + 1) % 2
-	numChildren := widget.ChildCount()
-	visibleChildren := 0
+	resolution := maxI(g.resolution, 1)
+
+	/* Drop the settings of removed children */
+	children := make(map[Widget]bool, widget.ChildCount())
+	for _, child := range widget.Children() {
+		children[child] = true
+	}
+	for w := range g.cells {
+		if !children[w] {
+			delete(g.cells, w)
+		}
+	}
+
+	var items []gridItem
 	for _, child := range widget.Children() {
-		if child.Visible() {
-			visibleChildren++
+		if !child.Visible() {
+			continue
 		}
+		cell := g.Cell(child)
+		item := gridItem{widget: child, pos: cell.pos, span: cell.span}
+		item.span[axis1] = minI(maxI(item.span[axis1], 1), resolution)
+		item.span[axis2] = maxI(item.span[axis2], 1)
+		if item.pos[axis1] >= 0 {
+			/* Cells beyond the resolution are moved back into the grid */
+			item.pos[axis1] = minI(item.pos[axis1], resolution-item.span[axis1])
+		}
+		items = append(items, item)
 	}
-	dim := make([]int, 2)
-	dim[axis1] = g.resolution
-	dim[axis2] = int((visibleChildren + g.resolution - 1) / g.resolution)
-
-	grid := make([][]int, 2)
-	grid[axis1] = make([]int, dim[axis1])
-	grid[axis2] = make([]int, dim[axis2])
-
-	child := 0
-	children := widget.Children()
-	for i2 := 0; i2 < dim[axis2]; i2++ {
-		for i1 := 0; i1 < dim[axis1]; i1++ {
-			var w Widget
-			for {
-				if child >= numChildren {
-					return grid
+
+	/* occupied[line][slot] is true when a widget already covers the cell */
+	var occupied [][]bool
+	fits := func(item *gridItem, slot, line int) bool {
+		for l := line; l < line+item.span[axis2] && l < len(occupied); l++ {
+			for s := slot; s < slot+item.span[axis1]; s++ {
+				if occupied[l][s] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	mark := func(item *gridItem, slot, line int) {
+		item.pos[axis1] = slot
+		item.pos[axis2] = line
+		for len(occupied) < line+item.span[axis2] {
+			occupied = append(occupied, make([]bool, resolution))
+		}
+		for l := line; l < line+item.span[axis2]; l++ {
+			for s := slot; s < slot+item.span[axis1]; s++ {
+				occupied[l][s] = true
+			}
+		}
+	}
+
+	for phase := 0; phase < 4; phase++ {
+		for i := range items {
+			item := &items[i]
+			slot, line := item.pos[axis1], item.pos[axis2]
+			switch phase {
+			case 0:
+				if slot >= 0 && line >= 0 {
+					mark(item, slot, line)
 				}
-				w = children[child]
-				child++
-				if w.Visible() {
-					break
+			case 1:
+				if slot < 0 && line >= 0 {
+					found := 0
+					for s := 0; s+item.span[axis1] <= resolution; s++ {
+						if fits(item, s, line) {
+							found = s
+							break
+						}
+					}
+					mark(item, found, line)
+				}
+			case 2:
+				if slot >= 0 && line < 0 {
+					l := 0
+					for !fits(item, slot, l) {
+						l++
+					}
+					mark(item, slot, l)
+				}
+			case 3:
+				if slot < 0 && line < 0 {
+					s, l := 0, 0
+					for !fits(item, s, l) {
+						s++
+						if s+item.span[axis1] > resolution {
+							s = 0
+							l++
+						}
+					}
+					mark(item, s, l)
 				}
 			}
-			pw, ph := w.PreferredSize(w, ctx)
-			fw, fh := w.FixedSize()
-			targetSize := []int{
-				toI(fw > 0, fw, pw),
-				toI(fh > 0, fh, ph),
+		}
+	}
+	return items
+}
+
+// computeLayout() returns the sizes of the columns and the rows, and whether or not they contain expanding widgets
+func (g *GridLayout) computeLayout(items []gridItem, ctx DrawContext) ([][]int, [][]bool) {
+	axis1 := int(g.orientation)
+	axis2 := (int(g.orientation) + 1) % 2
+	dim := make([]int, 2)
+	dim[axis1] = maxI(g.resolution, 1)
+	for _, item := range items {
+		dim[axis2] = maxI(dim[axis2], item.pos[axis2]+item.span[axis2])
+	}
+
+	grid := [][]int{make([]int, dim[0]), make([]int, dim[1])}
+	expanding := [][]bool{make([]bool, dim[0]), make([]bool, dim[1])}
+
+	/* Widgets on a single cell decide the sizes first, then spanning widgets enlarge their cells if needed */
+	for phase := 0; phase < 2; phase++ {
+		for _, item := range items {
+			lw, lh := LayoutSize(item.widget, ctx)
+			targetSize := []int{lw, lh}
+			for axis := 0; axis < 2; axis++ {
+				first := item.pos[axis]
+				last := first + item.span[axis]
+				if (item.span[axis] == 1) != (phase == 0) {
+					continue
+				}
+				if phase == 0 {
+					grid[axis][first] = maxI(grid[axis][first], targetSize[axis])
+					expanding[axis][first] = expanding[axis][first] || IsExpanding(item.widget, axis)
+					continue
+				}
+				currentSize := (item.span[axis] - 1) * g.spacing[axis]
+				var targets []int
+				for i := first; i < last; i++ {
+					currentSize += grid[axis][i]
+					if expanding[axis][i] {
+						targets = append(targets, i)
+					}
+				}
+				if IsExpanding(item.widget, axis) {
+					for i := first; i < last; i++ {
+						expanding[axis][i] = true
+					}
+				}
+				if targetSize[axis] <= currentSize {
+					continue
+				}
+				if len(targets) == 0 {
+					for i := first; i < last; i++ {
+						targets = append(targets, i)
+					}
+				}
+				distribute(grid[axis], targets, targetSize[axis]-currentSize)
 			}
-			grid[axis1][i1] = maxI(grid[axis1][i1], targetSize[axis1])
-			grid[axis2][i2] = maxI(grid[axis2][i2], targetSize[axis2])
 		}
 	}
-	return grid
+	return grid, expanding
+}
+
+// distribute() shares the space evenly between the targets. The remainder goes to the first ones
+func distribute(sizes []int, targets []int, space int) {
+	share := space / len(targets)
+	rest := space - share*len(targets)
+	for _, i := range targets {
+		sizes[i] += share
+		if rest > 0 {
+			sizes[i]++
+			rest--
+		}
+	}
 }
 
 func (g *GridLayout) String() string {
@@ -716,7 +1119,7 @@ func NewAnchor(x, y int, aligns ...Alignment) Anchor {
 func NewAnchorWithSize(x, y, w, h int, aligns ...Alignment) Anchor {
 	a := Anchor{
 		pos:  [2]uint8{uint8(x), uint8(y)},
-		size: [2]uint8{uint8(x), uint8(y)},
+		size: [2]uint8{uint8(w), uint8(h)},
 	}
 	switch len(aligns) {
 	case 0:
@@ -735,8 +1138,8 @@ func NewAnchorWithSize(x, y, w, h int, aligns ...Alignment) Anchor {
 }
 
 func (a *Anchor) String() string {
-	return fmt.Sprintf("Format[pos=(%i, %i), size=(%i, %i), align=(%i, %i)]",
-		a.pos[0], a.pos[1], a.size[0], a.size[1], int(a.align[0]), int(a.align[1]))
+	return fmt.Sprintf("Format[pos=(%d, %d), size=(%d, %d), align=(%s, %s)]",
+		a.pos[0], a.pos[1], a.size[0], a.size[1], a.align[0], a.align[1])
 }
 
 type AdvancedGridLayout struct {
@@ -822,13 +1225,14 @@ func (a *AdvancedGridLayout) SetColStretch(index int, stretch float32) {
 
 func (a *AdvancedGridLayout) SetAnchor(widget Widget, anchor Anchor) {
 	a.anchors[widget] = anchor
+	widget.InvalidateLayout()
 }
 
 func (a *AdvancedGridLayout) Anchor(widget Widget) Anchor {
 	return a.anchors[widget]
 }
 
-func (a *AdvancedGridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context) {
+func (a *AdvancedGridLayout) OnPerformLayout(widget Widget, ctx DrawContext) {
 	grid := a.computeLayout(widget, ctx)
 	grid[0] = append([]int{a.margin}, grid[0]...)
 	if _, ok := widget.(*Window); ok {
@@ -847,14 +1251,8 @@ func (a *AdvancedGridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context
 			anchor := a.Anchor(w)
 			itemPos := grid[axis][anchor.pos[axis]]
 			cellSize := grid[axis][anchor.pos[axis]+anchor.size[axis]] - itemPos
-			pw, ph := w.PreferredSize(w, ctx)
-			fw, fh := w.FixedSize()
-			var targetSize int
-			if axis == 0 {
-				targetSize = toI(fw > 0, fw, pw)
-			} else {
-				targetSize = toI(fh > 0, fh, ph)
-			}
+			lw, lh := LayoutSize(w, ctx)
+			targetSize := toI(axis == 0, lw, lh)
 			switch anchor.align[axis] {
 			case Minimum:
 			case Middle:
@@ -862,11 +1260,7 @@ func (a *AdvancedGridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context
 			case Maximum:
 				itemPos += cellSize - targetSize
 			case Fill:
-				if axis == 0 {
-					targetSize = toI(fw > 0, fw, cellSize)
-				} else {
-					targetSize = toI(fh > 0, fh, cellSize)
-				}
+				targetSize = FitSize(w, axis, targetSize, cellSize)
 			}
 			posX, posY := w.Position()
 			sizeW, sizeH := w.Size()
@@ -879,12 +1273,16 @@ func (a *AdvancedGridLayout) OnPerformLayout(widget Widget, ctx *nanovgo.Context
 			}
 			w.SetPosition(posX, posY)
 			w.SetSize(sizeW, sizeH)
-			w.OnPerformLayout(w, ctx)
+		}
+	}
+	for _, w := range widget.Children() {
+		if w.Visible() {
+			w.LayoutIfNeeded(w, ctx)
 		}
 	}
 }
 
-func (a *AdvancedGridLayout) PreferredSize(widget Widget, ctx *nanovgo.Context) (int, int) {
+func (a *AdvancedGridLayout) PreferredSize(widget Widget, ctx DrawContext) (int, int) {
 	grid := a.computeLayout(widget, ctx)
 	sizeW := a.margin * 2
 	sizeH := a.margin * 2
@@ -900,7 +1298,7 @@ func (a *AdvancedGridLayout) PreferredSize(widget Widget, ctx *nanovgo.Context)
 	return sizeW, sizeH
 }
 
-func (a *AdvancedGridLayout) computeLayout(widget Widget, ctx *nanovgo.Context) [][]int {
+func (a *AdvancedGridLayout) computeLayout(widget Widget, ctx DrawContext) [][]int {
 	var grids [][]int = [][]int{[]int{}, []int{}}
 	fw, fh := widget.FixedSize()
 	containerW := toI(fw > 0, fw, widget.Width())
@@ -929,6 +1327,7 @@ func (a *AdvancedGridLayout) computeLayout(widget Widget, ctx *nanovgo.Context)
 		grid := make([]int, len(sizes))
 		copy(grid, sizes)
 		grids[axis] = grid
+		stretch = a.implicitStretch(axis, stretch)
 
 		for phase := 0; phase < 2; phase++ {
 			for widget, anchor := range a.anchors {
@@ -938,11 +1337,8 @@ func (a *AdvancedGridLayout) computeLayout(widget Widget, ctx *nanovgo.Context)
 				if (anchor.size[axis]) == 1 != (phase == 0) {
 					continue
 				}
-				pw, ph := widget.PreferredSize(widget, ctx)
-				ps := toI(axis == 0, pw, ph)
-				fw, fh := widget.FixedSize()
-				fs := toI(axis == 0, fw, fh)
-				targetSize := toI(fs > 0, fs, ps)
+				lw, lh := LayoutSize(widget, ctx)
+				targetSize := toI(axis == 0, lw, lh)
 				if int(anchor.pos[axis])+int(anchor.size[axis]) > len(grid) {
 					panic("Advanced grid layout: widget is out of bounds: " + anchor.String())
 				}
@@ -992,6 +1388,25 @@ func (a *AdvancedGridLayout) computeLayout(widget Widget, ctx *nanovgo.Context)
 	return grids
 }
 
+// implicitStretch() returns stretch as is if any factor is set. Otherwise the cells of the expanding widgets stretch
+func (a *AdvancedGridLayout) implicitStretch(axis int, stretch []float32) []float32 {
+	for _, value := range stretch {
+		if value != 0 {
+			return stretch
+		}
+	}
+	result := make([]float32, len(stretch))
+	for widget, anchor := range a.anchors {
+		if !widget.Visible() || !IsExpanding(widget, axis) {
+			continue
+		}
+		for i := int(anchor.pos[axis]); i < int(anchor.pos[axis]+anchor.size[axis]) && i < len(result); i++ {
+			result[i] = 1
+		}
+	}
+	return result
+}
+
 func (a *AdvancedGridLayout) String() string {
 	return fmt.Sprintf("AdvancedGridLayout[%d-%d]", len(a.cols), len(a.rows))
 }
@@ -0,0 +1,292 @@
+package nanogui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBoxLayoutSizePolicies(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	panel.SetLayout(NewBoxLayout(Vertical, Fill, 0, 0))
+	panel.SetSize(200, 300)
+	a := newSized(panel, 50, 20)
+	b := newSized(panel, 50, 20)
+	c := newSized(panel, 50, 20)
+	b.SetSizePolicy(SizeFixed, SizeExpanding)
+	c.SetSizePolicy(SizeMaximum, SizeExpanding)
+	c.SetMaximumSize(0, 100)
+	ctx := screen.Context()
+	panel.OnPerformLayout(panel, ctx)
+	// the expanding widgets share the rest, and c stops at its maximum height
+	if a.Height() != 20 || b.Height() != 180 || c.Height() != 100 {
+		t.Errorf("heights %d, %d, %d, want 20, 180, 100", a.Height(), b.Height(), c.Height())
+	}
+	// Fill stretches only the widgets whose policy can grow
+	if a.Width() != 200 || b.Width() != 50 || c.Width() != 50 {
+		t.Errorf("widths %d, %d, %d, want 200, 50, 50", a.Width(), b.Width(), c.Width())
+	}
+
+	a.SetMinimumSize(0, 40)
+	if _, h := panel.PreferredSize(panel, ctx); h != 80 {
+		t.Errorf("the minimum size should raise the preferred height: %d, want 80", h)
+	}
+	if clamp := b.Clamp(); !clamp[0] || clamp[1] {
+		t.Errorf("Clamp() = %v, want [true false] for a fixed width", clamp)
+	}
+	b.SetClampWidth(false)
+	if policy, _ := b.SizePolicy(); policy != SizePreferred {
+		t.Errorf("SetClampWidth(false): horizontal policy %v, want SizePreferred", policy)
+	}
+}
+
+func TestGroupLayoutSizePolicies(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	panel.SetLayout(NewGroupLayout(10, 0, 0, 0))
+	panel.SetSize(300, 200)
+	bounded := newSized(panel, 50, 20)
+	bounded.SetMaximumSize(150, 0)
+	expanding := newSized(panel, 50, 20)
+	expanding.SetSizePolicy(SizePreferred, SizeExpanding)
+	panel.OnPerformLayout(panel, screen.Context())
+	if bounded.Width() != 150 {
+		t.Errorf("bounded width %d, want 150", bounded.Width())
+	}
+	if expanding.Width() != 280 || expanding.Height() != 160 {
+		t.Errorf("expanding size %dx%d, want 280x160", expanding.Width(), expanding.Height())
+	}
+}
+
+func TestGridLayoutSizePolicies(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	panel.SetLayout(NewGridLayout(Horizontal, 2, Fill))
+	panel.SetSize(200, 40)
+	maximum := newSized(panel, 50, 20)
+	maximum.SetSizePolicy(SizeMaximum, SizePreferred)
+	expanding := newSized(panel, 50, 20)
+	expanding.SetSizePolicy(SizeExpanding, SizePreferred)
+	panel.OnPerformLayout(panel, screen.Context())
+	if maximum.Width() != 50 || expanding.Width() != 150 {
+		t.Errorf("widths %d, %d, want 50, 150", maximum.Width(), expanding.Width())
+	}
+	if x, _ := expanding.Position(); x != 50 {
+		t.Errorf("expanding column x = %d, want 50", x)
+	}
+}
+
+func TestAdvancedGridLayoutSizePolicies(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	layout := NewAdvancedGridLayout([]int{0, 0}, []int{0})
+	panel.SetLayout(layout)
+	panel.SetSize(300, 30)
+	minimum := newSized(panel, 50, 20)
+	minimum.SetMinimumSize(80, 0)
+	expanding := newSized(panel, 50, 20)
+	expanding.SetSizePolicy(SizeExpanding, SizePreferred)
+	layout.SetAnchor(minimum, NewAnchor(0, 0))
+	layout.SetAnchor(expanding, NewAnchor(1, 0))
+	panel.OnPerformLayout(panel, screen.Context())
+	// an expanding widget stretches its column without an explicit stretch factor
+	if minimum.Width() != 80 || expanding.Width() != 220 {
+		t.Errorf("widths %d, %d, want 80, 220", minimum.Width(), expanding.Width())
+	}
+}
+
+func TestFlexLayoutSizePolicies(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	layout := NewFlexLayout(Horizontal, JustifyStart, Fill)
+	panel.SetLayout(layout)
+	panel.SetSize(300, 30)
+	bounded := newSized(panel, 50, 20)
+	fixedHeight := newSized(panel, 50, 20)
+	layout.SetItem(bounded, NewFlexItem(1, 1))
+	layout.SetItem(fixedHeight, NewFlexItem(1, 1))
+	bounded.SetMaximumSize(100, 0)
+	fixedHeight.SetSizePolicy(SizePreferred, SizeFixed)
+	panel.OnPerformLayout(panel, screen.Context())
+	if bounded.Width() != 100 || fixedHeight.Width() != 200 {
+		t.Errorf("widths %d, %d, want 100, 200", bounded.Width(), fixedHeight.Width())
+	}
+	if bounded.Height() != 30 || fixedHeight.Height() != 20 {
+		t.Errorf("heights %d, %d, want 30, 20", bounded.Height(), fixedHeight.Height())
+	}
+}
+
+func TestConstraintLayoutSizePolicies(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	layout := NewConstraintLayout()
+	panel.SetLayout(layout)
+	panel.SetSize(300, 100)
+	w := newSized(panel, 50, 20)
+	w.SetMinimumSize(120, 0)
+	layout.AddConstraints(layout.Left(w).Equal(layout.Left(nil)), layout.Top(w).Equal(layout.Top(nil)))
+	ctx := screen.Context()
+	panel.OnPerformLayout(panel, ctx)
+	if layout.Err() != nil || w.Width() != 120 {
+		t.Fatalf("minimum width: %d (error %v), want 120", w.Width(), layout.Err())
+	}
+	layout.AddConstraint(layout.Width(w).Equal(Constant(60), StrengthStrong))
+	panel.OnPerformLayout(panel, ctx)
+	if w.Width() != 120 {
+		t.Fatalf("the minimum size is required: width %d, want 120", w.Width())
+	}
+
+	w.SetSizePolicy(SizeMaximum, SizePreferred)
+	w.SetMinimumSize(0, 0)
+	layout.AddConstraint(layout.Width(w).Equal(Constant(90), StrengthStrong))
+	panel.OnPerformLayout(panel, ctx)
+	if layout.Err() != nil || w.Width() != 50 {
+		t.Errorf("SizeMaximum caps the width at the preferred size: %d (error %v), want 50", w.Width(), layout.Err())
+	}
+}
+
+func TestSizePolicyDeclarative(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	ui, err := LoadUI(screen, strings.NewReader(`{"widgets": [{"type": "Widget", "id": "w",
+		"minimumSize": [10, 20], "maximumSize": [100, 0], "sizePolicy": ["expanding", "fixed"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := ui.Widget("w")
+	if mw, mh := w.MinimumSize(); mw != 10 || mh != 20 {
+		t.Errorf("MinimumSize() = %d, %d, want 10, 20", mw, mh)
+	}
+	if hp, vp := w.SizePolicy(); hp != SizeExpanding || vp != SizeFixed {
+		t.Errorf("SizePolicy() = %v, %v, want SizeExpanding, SizeFixed", hp, vp)
+	}
+	node, err := ExportWidget(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(node.SizePolicy) != 2 || node.SizePolicy[0] != "Expanding" || node.MaximumSize[0] != 100 {
+		t.Errorf("exported size policy %q and maximum size %v", node.SizePolicy, node.MaximumSize)
+	}
+	if _, err := LoadUI(screen, strings.NewReader(`{"widgets": [{"type": "Widget", "sizePolicy": ["huge", "fixed"]}]}`)); err == nil {
+		t.Error("LoadUI() should reject an unknown size policy")
+	}
+}
+
+func TestGridLayoutSpanAndAutoPlacement(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	layout := NewGridLayout(Horizontal, 3, Fill, 0, 10)
+	panel.SetLayout(layout)
+	a := newSized(panel, 50, 20)
+	b := newSized(panel, 30, 20)
+	c := newSized(panel, 30, 20)
+	d := newSized(panel, 30, 20)
+	e := newSized(panel, 30, 20)
+	layout.SetCell(a, NewGridSpan(2, 1))
+	layout.SetCell(b, NewGridSpan(3, 1))
+	layout.SetCell(c, NewGridCell(0, 1, 1, 2))
+	ctx := screen.Context()
+	_, _, items := layout.grid(panel, ctx)
+	positions := map[Widget][2]int{}
+	for _, item := range items {
+		positions[item.widget] = item.pos
+	}
+	// b doesn't fit next to a, so it moves to the first free row; d and e fill the holes
+	want := map[Widget][2]int{a: {0, 0}, b: {0, 3}, c: {0, 1}, d: {2, 0}, e: {1, 1}}
+	for widget, pos := range want {
+		if positions[widget] != pos {
+			t.Errorf("%v is placed at %v, want %v", widget, positions[widget], pos)
+		}
+	}
+
+	w, h := layout.PreferredSize(panel, ctx)
+	// the third row holds only the spanning c, so it has no height of its own
+	if h != 3*20+3*10 {
+		t.Errorf("preferred height %d, want %d", h, 3*20+3*10)
+	}
+	panel.SetSize(w, h)
+	layout.OnPerformLayout(panel, ctx)
+	if a.Width() != 70 || b.Width() != 110 || c.Height() != 30 {
+		t.Errorf("spanning sizes: a width %d, b width %d, c height %d, want 70, 110, 30", a.Width(), b.Width(), c.Height())
+	}
+}
+
+func TestGridLayoutSetCellInvalidatesOwner(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	layout := NewGridLayout(Horizontal, 2, Fill)
+	panel.SetLayout(layout)
+	child := newSized(panel, 10, 10)
+	screen.PerformLayout()
+	layout.SetCell(child, NewGridCell(1, 0))
+	if !panel.NeedsLayout() || !screen.NeedsLayout() {
+		t.Error("SetCell() should invalidate the layout of the container")
+	}
+}
+
+func TestGridLayoutWithoutCells(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	panel := NewWidget(screen)
+	layout := NewGridLayout(Vertical, 2, Minimum, 5, 3)
+	panel.SetLayout(layout)
+	var widgets []Widget
+	for i := 0; i < 5; i++ {
+		widgets = append(widgets, newSized(panel, 10+i, 20))
+	}
+	ctx := screen.Context()
+	w, h := layout.PreferredSize(panel, ctx)
+	// two rows and three columns filled column by column
+	if w != 5+11+3+13+3+14+5 || h != 5+20+3+20+5 {
+		t.Fatalf("PreferredSize() = %d, %d, want %d, %d", w, h, 5+11+3+13+3+14+5, 5+20+3+20+5)
+	}
+	panel.SetSize(w, h)
+	layout.OnPerformLayout(panel, ctx)
+	if x, y := widgets[3].Position(); x != 5+11+3 || y != 5+20+3 {
+		t.Errorf("fourth widget at (%d, %d), want (%d, %d)", x, y, 5+11+3, 5+20+3)
+	}
+}
+
+func TestGridLayoutInvalidCells(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	_, err := LoadUI(screen, strings.NewReader(`{"widgets": [{"type": "Widget", "layout": {"type": "GridLayout", "resolution": 2},
+		"children": [{"type": "Label", "caption": "x", "cell": {"pos": [1, 0], "span": [2, 1]}}]}]}`))
+	if err == nil {
+		t.Error("LoadUI() should reject a cell which overflows the resolution")
+	}
+
+	panel := NewWidget(screen)
+	layout := NewGridLayout(Horizontal, 2, Fill)
+	panel.SetLayout(layout)
+	a := newSized(panel, 10, 10)
+	b := newSized(panel, 10, 10)
+	layout.SetCell(a, NewGridCell(5, 0, 1, 1))
+	layout.SetCell(b, NewGridSpan(1, 1))
+	_, _, items := layout.grid(panel, screen.Context())
+	if items[0].pos != [2]int{1, 0} {
+		t.Errorf("an overflowing column should be clamped: %v, want [1 0]", items[0].pos)
+	}
+	panel.RemoveChild(b)
+	layout.grid(panel, screen.Context())
+	if _, ok := layout.cells[b]; ok || len(layout.cells) != 1 {
+		t.Error("the cell of a removed widget should be dropped")
+	}
+}
+
+func TestGridLayoutDeclarativeCell(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	ui, err := LoadUI(screen, strings.NewReader(`{"widgets": [{"type": "Widget", "id": "grid", "layout": {"type": "GridLayout", "resolution": 2},
+		"children": [{"type": "Label", "id": "label", "caption": "x", "cell": {"span": [2, 1]}}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout := ui.Widget("grid").Layout().(*GridLayout)
+	if cell := layout.Cell(ui.Widget("label")); cell.ColSpan() != 2 || cell.Col() != -1 {
+		t.Errorf("Cell() = %+v, want an auto placed cell spanning two columns", cell)
+	}
+	node, err := ExportWidget(ui.Widget("grid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cell := node.Children[0].Cell; cell == nil || cell.Span == nil || (*cell.Span)[0] != 2 {
+		t.Errorf("exported cell %+v, want a column span of 2", cell)
+	}
+}
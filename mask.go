@@ -0,0 +1,404 @@
+package nanogui
+
+import (
+	"errors"
+	"unicode"
+)
+
+// MaskValueMode specifies what Value() of a masked TextBox returns
+type MaskValueMode int
+
+const (
+	// MaskValueFormatted returns the value with the literal characters of the mask ("2016-04-01")
+	MaskValueFormatted MaskValueMode = iota
+	// MaskValueRaw returns only the characters entered in the slots ("20160401")
+	MaskValueRaw
+)
+
+// DefaultMaskBlank is the glyph drawn for the empty slots of an input mask
+const DefaultMaskBlank = '_'
+
+// ErrIncompleteValue is the validation error of a masked value whose required slots aren't filled
+var ErrIncompleteValue = errors.New("incomplete value")
+
+type maskClass int
+
+const (
+	maskLiteral maskClass = iota
+	maskDigit
+	maskLetter
+	maskAlphanumeric
+	maskAny
+)
+
+type maskElement struct {
+	class    maskClass
+	optional bool
+	literal  rune
+}
+
+// textMask is a parsed input mask
+//
+// The text being edited always has the length of the mask: literal characters are
+// kept at their positions and empty slots hold the blank glyph.
+type textMask struct {
+	pattern  string
+	elements []maskElement
+	blank    rune
+}
+
+func parseMask(pattern string, blank rune) (*textMask, error) {
+	mask := &textMask{
+		pattern: pattern,
+		blank:   blank,
+	}
+	hasSlot := false
+	escaped := false
+	for _, r := range pattern {
+		element := maskElement{class: maskLiteral, literal: r}
+		if escaped {
+			escaped = false
+		} else {
+			switch r {
+			case '\\':
+				escaped = true
+				continue
+			case '9':
+				element = maskElement{class: maskDigit}
+			case '#':
+				element = maskElement{class: maskDigit, optional: true}
+			case 'A':
+				element = maskElement{class: maskLetter}
+			case 'a':
+				element = maskElement{class: maskLetter, optional: true}
+			case '*':
+				element = maskElement{class: maskAlphanumeric}
+			case '?':
+				element = maskElement{class: maskAny}
+			}
+		}
+		hasSlot = hasSlot || element.class != maskLiteral
+		mask.elements = append(mask.elements, element)
+	}
+	if escaped {
+		return nil, errors.New("nanogui: input mask ends with an escape character")
+	}
+	if !hasSlot {
+		return nil, errors.New("nanogui: input mask has no input slots")
+	}
+	return mask, nil
+}
+
+func (m *textMask) isSlot(pos int) bool {
+	return pos >= 0 && pos < len(m.elements) && m.elements[pos].class != maskLiteral
+}
+
+// accept() returns whether or not the slot at pos accepts r
+func (m *textMask) accept(pos int, r rune) bool {
+	switch m.elements[pos].class {
+	case maskDigit:
+		return r >= '0' && r <= '9'
+	case maskLetter:
+		return unicode.IsLetter(r)
+	case maskAlphanumeric:
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	case maskAny:
+		return unicode.IsPrint(r) && r != m.blank
+	}
+	return false
+}
+
+// template() returns the text of the mask with all slots empty
+func (m *textMask) template() []rune {
+	text := make([]rune, len(m.elements))
+	for i, element := range m.elements {
+		if element.class == maskLiteral {
+			text[i] = element.literal
+		} else {
+			text[i] = m.blank
+		}
+	}
+	return text
+}
+
+func (m *textMask) firstSlot() int {
+	for i := range m.elements {
+		if m.isSlot(i) {
+			return i
+		}
+	}
+	return 0
+}
+
+// end() returns the cursor position after the last slot
+func (m *textMask) end() int {
+	for i := len(m.elements) - 1; i >= 0; i-- {
+		if m.isSlot(i) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// snapRight() returns the first cursor position at or after pos. The cursor stays in front of a slot or at the end
+func (m *textMask) snapRight(pos int) int {
+	end := m.end()
+	for pos < end && !m.isSlot(pos) {
+		pos++
+	}
+	return minI(maxI(pos, m.firstSlot()), end)
+}
+
+// snapLeft() returns the last cursor position at or before pos
+func (m *textMask) snapLeft(pos int) int {
+	end := m.end()
+	if pos >= end {
+		return end
+	}
+	for pos > 0 && !m.isSlot(pos) {
+		pos--
+	}
+	return maxI(pos, m.firstSlot())
+}
+
+// previousSlot() returns the last slot before pos, or -1
+func (m *textMask) previousSlot(pos int) int {
+	for i := pos - 1; i >= 0; i-- {
+		if m.isSlot(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// insert() enters runes from cursor and returns the new cursor position
+//
+// Each rune goes to the next slot. A rune which the slot doesn't accept is dropped,
+// unless it is one of the following literal characters and only optional slots are
+// in between: then the cursor jumps after that literal ("1." in "###.###").
+func (m *textMask) insert(text []rune, cursor int, runes []rune) int {
+	for _, r := range runes {
+		pos := m.snapRight(cursor)
+		if m.isSlot(pos) && m.accept(pos, r) {
+			text[pos] = r
+			if m.elements[pos].class == maskLetter && !m.elements[pos].optional {
+				text[pos] = unicode.ToUpper(r)
+			}
+			cursor = m.snapRight(pos + 1)
+			continue
+		}
+		if pos > 0 && m.elements[pos-1].class == maskLiteral && m.elements[pos-1].literal == r {
+			// the literal was already skipped
+			continue
+		}
+		if literal := m.nextLiteral(pos, r); literal >= 0 {
+			cursor = m.snapRight(literal + 1)
+		}
+	}
+	return cursor
+}
+
+func (m *textMask) nextLiteral(pos int, r rune) int {
+	for i := pos; i < len(m.elements); i++ {
+		element := m.elements[i]
+		if element.class == maskLiteral {
+			if element.literal == r {
+				return i
+			}
+		} else if !element.optional {
+			return -1
+		}
+	}
+	return -1
+}
+
+// clear() empties the slots in [begin, end)
+func (m *textMask) clear(text []rune, begin, end int) {
+	for i := maxI(begin, 0); i < end && i < len(m.elements); i++ {
+		if m.isSlot(i) {
+			text[i] = m.blank
+		}
+	}
+}
+
+// fill() returns the text to edit for a raw or formatted value
+func (m *textMask) fill(value string) []rune {
+	text := m.template()
+	m.insert(text, m.firstSlot(), []rune(value))
+	return text
+}
+
+// filled() returns the characters of the filled slots in [begin, end)
+func (m *textMask) filled(text []rune, begin, end int) []rune {
+	var result []rune
+	for i := maxI(begin, 0); i < end && i < len(text); i++ {
+		if m.isSlot(i) && text[i] != m.blank {
+			result = append(result, text[i])
+		}
+	}
+	return result
+}
+
+// format() returns the value with the literal characters. Empty optional slots are left out
+func (m *textMask) format(text []rune) string {
+	if m.isEmpty(text) {
+		return ""
+	}
+	result := make([]rune, 0, len(text))
+	for i, r := range text {
+		if !m.isSlot(i) || r != m.blank {
+			result = append(result, r)
+		}
+	}
+	return string(result)
+}
+
+// raw() returns the characters entered in the slots
+func (m *textMask) raw(text []rune) string {
+	return string(m.filled(text, 0, len(text)))
+}
+
+func (m *textMask) isEmpty(text []rune) bool {
+	return len(m.filled(text, 0, len(text))) == 0
+}
+
+// isComplete() returns whether or not all the required slots are filled
+func (m *textMask) isComplete(text []rune) bool {
+	for i, element := range m.elements {
+		if element.class != maskLiteral && !element.optional && text[i] == m.blank {
+			return false
+		}
+	}
+	return true
+}
+
+// Mask() returns the input mask, or an empty string
+func (t *TextBox) Mask() string {
+	if t.mask == nil {
+		return ""
+	}
+	return t.mask.pattern
+}
+
+// SetMask() sets an input mask which guides the user while typing. An empty mask removes it
+//
+// Slots of the mask accept one character of a class:
+//
+//	9  digit
+//	#  digit (optional)
+//	A  letter (converted to upper case)
+//	a  letter (optional)
+//	*  letter or digit
+//	?  any character
+//
+// Other characters are literals; '\' makes the next character a literal. The cursor skips
+// the literals, typed and pasted text is fitted into the slots and the value can't be
+// committed until all required slots are filled. The optional parameter sets the glyph of
+// empty slots (default: DefaultMaskBlank).
+func (t *TextBox) SetMask(pattern string, blank ...rune) error {
+	var blankParam rune
+	switch len(blank) {
+	case 0:
+		blankParam = DefaultMaskBlank
+	case 1:
+		blankParam = blank[0]
+	default:
+		panic("TextBox.SetMask can accept only one extra parameter (blank)")
+	}
+	if pattern == "" {
+		t.mask = nil
+		return nil
+	}
+	mask, err := parseMask(pattern, blankParam)
+	if err != nil {
+		return err
+	}
+	t.mask = mask
+	t.value = mask.format(mask.fill(t.value))
+	if t.committed {
+		t.valueTemp = []rune(t.value)
+	} else {
+		t.valueTemp = mask.fill(t.value)
+		t.cursorPos = mask.firstSlot()
+		t.selectionPos = -1
+		t.updateValidation()
+	}
+	return nil
+}
+
+// MaskValueMode() returns what Value() of a masked text box returns
+func (t *TextBox) MaskValueMode() MaskValueMode {
+	return t.maskValueMode
+}
+
+// SetMaskValueMode() selects whether Value() and the callback get the formatted or the raw value (default: MaskValueFormatted)
+func (t *TextBox) SetMaskValueMode(mode MaskValueMode) {
+	t.maskValueMode = mode
+}
+
+// maskedValue() converts a formatted value to the form selected by the mask value mode
+func (t *TextBox) maskedValue(formatted string) string {
+	if t.mask == nil || t.maskValueMode == MaskValueFormatted {
+		return formatted
+	}
+	return t.mask.raw(t.mask.fill(formatted))
+}
+
+// maskEditAction() performs the edit actions which change the length of the text in mask mode.
+// It returns EditActionNone for the handled actions
+func (t *TextBox) maskEditAction(editAction EditAction) EditAction {
+	m := t.mask
+	switch editAction {
+	case EditActionBackspace:
+		if !t.DeleteSelection() {
+			if pos := m.previousSlot(t.cursorPos); pos >= 0 {
+				t.valueTemp[pos] = m.blank
+				t.cursorPos = pos
+			}
+		}
+	case EditActionDelete:
+		if !t.DeleteSelection() {
+			m.clear(t.valueTemp, m.snapRight(t.cursorPos), m.snapRight(t.cursorPos)+1)
+		}
+	case EditActionDeleteLeftWord:
+		if !t.DeleteSelection() {
+			begin := t.cursorPos
+			for begin > 0 && !m.isSlot(begin-1) {
+				begin--
+			}
+			for begin > 0 && m.isSlot(begin-1) {
+				begin--
+			}
+			m.clear(t.valueTemp, begin, t.cursorPos)
+			t.cursorPos = m.snapRight(begin)
+		}
+	case EditActionDeleteRightWord:
+		if !t.DeleteSelection() {
+			end := m.snapRight(t.cursorPos)
+			for end < len(t.valueTemp) && m.isSlot(end) {
+				end++
+			}
+			m.clear(t.valueTemp, t.cursorPos, end)
+		}
+	case EditActionCutUntilLineEnd:
+		t.yankValue = m.filled(t.valueTemp, t.cursorPos, len(t.valueTemp))
+		m.clear(t.valueTemp, t.cursorPos, len(t.valueTemp))
+	case EditActionYank:
+		t.cursorPos = m.insert(t.valueTemp, t.cursorPos, t.yankValue)
+	default:
+		return editAction
+	}
+	return EditActionNone
+}
+
+// snapMaskCursor() keeps the cursor off the literals after it moved from the previous position
+func (t *TextBox) snapMaskCursor(previous int) {
+	if t.cursorPos < previous {
+		t.cursorPos = t.mask.snapLeft(t.cursorPos)
+	} else {
+		t.cursorPos = t.mask.snapRight(t.cursorPos)
+	}
+	if t.cursorPos == t.selectionPos {
+		t.selectionPos = -1
+	}
+}
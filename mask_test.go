@@ -0,0 +1,94 @@
+package nanogui
+
+import (
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+func TestTextBoxPhoneMask(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := NewTextBox(window, "")
+	textBox.SetEditable(true)
+	textBox.SetAlignment(TextLeft)
+	textBox.SetFixedSize(300, 0)
+	if err := textBox.SetMask("(999) 999-9999"); err != nil {
+		t.Fatal(err)
+	}
+	committed := ""
+	textBox.SetCallback(func(value string) bool {
+		committed = value
+		return true
+	})
+	screen.PerformLayout()
+	screen.DrawAll()
+
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	expect := func(step, text string, cursor int) {
+		t.Helper()
+		if string(textBox.valueTemp) != text || textBox.cursorPos != cursor {
+			t.Fatalf("%s: %q with cursor %d, want %q with cursor %d", step, string(textBox.valueTemp), textBox.cursorPos, text, cursor)
+		}
+	}
+	expect("focus", "(___) ___-____", 1)
+	in.TypeText("55x5123")
+	expect("typing skips literals and rejected runes", "(555) 123-____", 10)
+	in.PressKey(glfw.KeyEnter, 0)
+	if textBox.ValidationError() != ErrIncompleteValue || committed != "" {
+		t.Fatalf("an incomplete value shouldn't be committed: %v", textBox.ValidationError())
+	}
+	in.PressKey(glfw.KeyLeft, 0)
+	expect("Left skips the literal", "(555) 123-____", 8)
+	in.PressKey(glfw.KeyRight, 0)
+	in.PressKey(glfw.KeyBackspace, 0)
+	expect("Backspace clears the slot", "(555) 12_-____", 8)
+
+	in.PressKey(glfw.KeyA, glfw.ModControl)
+	SetClipboardText(screen.Clipboard(), "555.123.4567")
+	in.PressKey(glfw.KeyV, glfw.ModControl)
+	if got := string(textBox.valueTemp); got != "(555) 123-4567" {
+		t.Fatalf("paste should fill the slots: %q", got)
+	}
+	in.PressKey(glfw.KeyEnter, 0)
+	if committed != "(555) 123-4567" || textBox.Value() != committed {
+		t.Fatalf("committed %q, Value() = %q", committed, textBox.Value())
+	}
+	textBox.SetMaskValueMode(MaskValueRaw)
+	if textBox.Value() != "5551234567" {
+		t.Errorf("MaskValueRaw: Value() = %q, want %q", textBox.Value(), "5551234567")
+	}
+}
+
+func TestTextBoxOptionalSlotMask(t *testing.T) {
+	screen, window := newTestWindow(t)
+	ip := NewTextBox(window, "")
+	ip.SetEditable(true)
+	ip.SetMask("###.###.###.###")
+	ip.SetValue("10.0.0.1")
+	if ip.Value() != "10.0.0.1" {
+		t.Fatalf("Value() = %q, want %q", ip.Value(), "10.0.0.1")
+	}
+	ip.RequestFocus(ip)
+	if got := string(ip.valueTemp); got != "10_.0__.0__.1__" {
+		t.Fatalf("editing text %q, want %q", got, "10_.0__.0__.1__")
+	}
+	in := screen.Input()
+	in.PressKey(glfw.KeyA, glfw.ModControl)
+	in.TypeText("192.168.1.20")
+	if got := string(ip.valueTemp); got != "192.168.1__.20_" || ip.ValidationError() != nil {
+		t.Fatalf("typing a literal should jump to the next group: %q", got)
+	}
+	in.PressKey(glfw.KeyEnter, 0)
+	if ip.Value() != "192.168.1.20" {
+		t.Errorf("Value() = %q, want %q", ip.Value(), "192.168.1.20")
+	}
+
+	node, err := ExportWidget(ip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Mask != "###.###.###.###" {
+		t.Errorf("exported mask %q", node.Mask)
+	}
+}
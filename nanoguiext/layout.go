@@ -3,7 +3,6 @@ package nanoguiext
 import (
 	"fmt"
 	"github.com/shibukawa/nanogui.go"
-	"github.com/shibukawa/nanovgo"
 )
 
 type FlexibleWidget interface {
@@ -77,7 +76,7 @@ func (b *ExpandBoxLayout) SetSpacing(s int) {
 	b.spacing = s
 }
 
-func (b *ExpandBoxLayout) OnPerformLayout(widget nanogui.Widget, ctx *nanovgo.Context) {
+func (b *ExpandBoxLayout) OnPerformLayout(widget nanogui.Widget, ctx nanogui.DrawContext) {
 	fW, fH := widget.FixedSize()
 	var containerSize [2]int
 	if fW > 0 {
@@ -105,41 +104,63 @@ func (b *ExpandBoxLayout) OnPerformLayout(widget nanogui.Widget, ctx *nanovgo.Co
 	}
 	childCount := 0
 	fixedChildren := make([]bool, widget.ChildCount())
+	scrollChildren := make([]bool, widget.ChildCount())
 	preferredLength := make([][2]int, widget.ChildCount())
 	remainedLength := containerSize[axis1] - position - b.margin + b.spacing
+	hasExpanding := false
 	for i, child := range widget.Children() {
 		if child.Visible() && !child.IsPositionAbsolute() {
-			childCount++
-			if _, isScroll := child.(*nanogui.VScrollPanel); !isScroll {
-				fW, fH := child.FixedSize()
-				fs := [2]int{fW, fH}
-				preferredLength[i] = fs
-				if fs[axis1] > 0 {
-					remainedLength -= fs[axis1]
-					fixedChildren[i] = true
-					childCount--
-				} else if child.Clamp()[axis1] {
-					pW, pH := child.PreferredSize(child, ctx)
-					ps := [2]int{pW, pH}
-					remainedLength -= ps[axis1]
-					preferredLength[i] = ps
-					fixedChildren[i] = true
-					childCount--
-				} else {
-					pW, pH := child.PreferredSize(child, ctx)
-					ps := [2]int{pW, pH}
-					preferredLength[i] = ps
-				}
+			if _, isScroll := child.(*nanogui.VScrollPanel); isScroll {
+				scrollChildren[i] = true
+			} else {
+				w, h := nanogui.LayoutSize(child, ctx)
+				preferredLength[i] = [2]int{w, h}
+				hasExpanding = hasExpanding || nanogui.IsExpanding(child, axis1)
 			}
 			remainedLength -= b.spacing
 		} else {
 			fixedChildren[i] = true
 		}
 	}
+	// expanding children take the space if any, and the others keep their size
+	for i, child := range widget.Children() {
+		if fixedChildren[i] {
+			continue
+		}
+		if scrollChildren[i] {
+			childCount++
+			continue
+		}
+		fW, fH := child.FixedSize()
+		fs := [2]int{fW, fH}
+		if fs[axis1] > 0 || child.Clamp()[axis1] || (hasExpanding && !nanogui.IsExpanding(child, axis1)) {
+			remainedLength -= preferredLength[i][axis1]
+			fixedChildren[i] = true
+		} else {
+			childCount++
+		}
+	}
 
+	// the children which reach the bounds of their size keep it, and the others share the rest again
 	var averageSize int
-	if childCount > 0 {
+	for childCount > 0 {
 		averageSize = remainedLength / childCount
+		bounded := false
+		for i, child := range widget.Children() {
+			if fixedChildren[i] || scrollChildren[i] {
+				continue
+			}
+			if size := nanogui.FitSize(child, axis1, preferredLength[i][axis1], averageSize); size != averageSize {
+				preferredLength[i][axis1] = size
+				remainedLength -= size
+				fixedChildren[i] = true
+				childCount--
+				bounded = true
+			}
+		}
+		if !bounded {
+			break
+		}
 	}
 	for i, child := range widget.Children() {
 		if !child.Visible() || child.IsPositionAbsolute() {
@@ -150,7 +171,7 @@ func (b *ExpandBoxLayout) OnPerformLayout(widget nanogui.Widget, ctx *nanovgo.Co
 		pos[1] = yOffset
 		pos[axis1] = position
 		var targetSize [2]int
-		if fixedChildren[i] && preferredLength[i][axis1] > 0 {
+		if fixedChildren[i] {
 			targetSize[axis1] = preferredLength[i][axis1]
 		} else {
 			targetSize[axis1] = averageSize
@@ -167,15 +188,18 @@ func (b *ExpandBoxLayout) OnPerformLayout(widget nanogui.Widget, ctx *nanovgo.Co
 		case nanogui.Fill:
 			pos[axis2] += b.margin
 			targetSize[axis2] = containerSize[axis2] - yOffset - b.margin*2
+			if !scrollChildren[i] {
+				targetSize[axis2] = nanogui.FitSize(child, axis2, preferredLength[i][axis2], targetSize[axis2])
+			}
 		}
 		child.SetPosition(pos[0], pos[1])
 		child.SetSize(targetSize[0], targetSize[1])
-		child.OnPerformLayout(child, ctx)
+		child.LayoutIfNeeded(child, ctx)
 		position += targetSize[axis1] + b.spacing
 	}
 }
 
-func (b *ExpandBoxLayout) PreferredSize(widget nanogui.Widget, ctx *nanovgo.Context) (int, int) {
+func (b *ExpandBoxLayout) PreferredSize(widget nanogui.Widget, ctx nanogui.DrawContext) (int, int) {
 	fW, fH := widget.FixedSize()
 	var containerSize [2]int
 	if fW > 0 {
@@ -199,7 +223,7 @@ func (b *ExpandBoxLayout) PreferredSize(widget nanogui.Widget, ctx *nanovgo.Cont
 			continue
 		}
 		childCount++
-		w, h := child.PreferredSize(child, ctx)
+		w, h := nanogui.LayoutSize(child, ctx)
 		size := []int{w, h}
 		minimumContainerSize[axis1] += size[axis1]
 		if size[axis2] > minimumContainerSize[axis2] {
@@ -319,7 +343,7 @@ func (g *ExpandListLayout) SetExpandPolicy(axis int, policy ExpandPolicy) {
 	g.expandPolicy[axis] = policy
 }
 
-func (g *ExpandListLayout) OnPerformLayout(widget nanogui.Widget, ctx *nanovgo.Context) {
+func (g *ExpandListLayout) OnPerformLayout(widget nanogui.Widget, ctx nanogui.DrawContext) {
 	widths, heights, _, _ := g.computeSize(widget, ctx)
 
 	nCols := len(g.widths)
@@ -336,9 +360,9 @@ func (g *ExpandListLayout) OnPerformLayout(widget nanogui.Widget, ctx *nanovgo.C
 		column := i % nCols
 		width := widths[column]
 		height := heights[row]
-		pw, ph := child.PreferredSize(child, ctx)
-		childXOffset, childWidth := alignment(g.alignments[0], width, pw)
-		childYOffset, childHeight := alignment(g.alignments[1], height, ph)
+		pw, ph := nanogui.LayoutSize(child, ctx)
+		childXOffset, childWidth := alignment(child, 0, g.alignments[0], width, pw)
+		childYOffset, childHeight := alignment(child, 1, g.alignments[1], height, ph)
 		child.SetPosition(xOffset+childXOffset, yOffset+childYOffset)
 		child.SetSize(childWidth, childHeight)
 
@@ -349,16 +373,16 @@ func (g *ExpandListLayout) OnPerformLayout(widget nanogui.Widget, ctx *nanovgo.C
 		} else {
 			xOffset += g.spacing[0] + width
 		}
-		child.OnPerformLayout(child, ctx)
+		child.LayoutIfNeeded(child, ctx)
 	}
 }
 
-func (g *ExpandListLayout) PreferredSize(widget nanogui.Widget, ctx *nanovgo.Context) (int, int) {
+func (g *ExpandListLayout) PreferredSize(widget nanogui.Widget, ctx nanogui.DrawContext) (int, int) {
 	_, _, totalWidth, totalHeight := g.computeSize(widget, ctx)
 	return totalWidth, totalHeight
 }
 
-func (g *ExpandListLayout) computeSize(widget nanogui.Widget, ctx *nanovgo.Context) (widths, heights []int, totalWidth, totalHeight int) {
+func (g *ExpandListLayout) computeSize(widget nanogui.Widget, ctx nanogui.DrawContext) (widths, heights []int, totalWidth, totalHeight int) {
 	if widget.ChildCount() == 0 {
 		return nil, nil, 0, 0
 	}
@@ -415,7 +439,7 @@ func (g *ExpandListLayout) computeSize(widget nanogui.Widget, ctx *nanovgo.Conte
 		if fWidget, ok := child.(FlexibleWidget); ok {
 			fWidget.SetColumnWidth(widths[column])
 		}
-		_, h := child.PreferredSize(child, ctx)
+		_, h := nanogui.LayoutSize(child, ctx)
 		if h > maxRowHeight {
 			maxRowHeight = h
 		}
@@ -433,7 +457,7 @@ func (g *ExpandListLayout) String() string {
 	return fmt.Sprintf("ExpandListLayout[%d]", len(g.widths))
 }
 
-func alignment(align nanogui.Alignment, space, preferredSize int) (offset, size int) {
+func alignment(widget nanogui.Widget, axis int, align nanogui.Alignment, space, preferredSize int) (offset, size int) {
 	if space < preferredSize {
 		align = nanogui.Fill
 	}
@@ -449,7 +473,7 @@ func alignment(align nanogui.Alignment, space, preferredSize int) (offset, size
 		size = preferredSize
 	case nanogui.Fill:
 		offset = 0
-		size = space
+		size = nanogui.FitSize(widget, axis, preferredSize, space)
 	}
 	return
 }
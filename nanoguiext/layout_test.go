@@ -0,0 +1,39 @@
+package nanoguiext_test
+
+import (
+	"testing"
+
+	"github.com/shibukawa/nanogui.go"
+	"github.com/shibukawa/nanogui.go/nanoguiext"
+)
+
+func TestExpandBoxLayoutSizePolicies(t *testing.T) {
+	screen := nanogui.NewHeadlessScreen(800, 600)
+	panel := nanogui.NewWidget(screen)
+	panel.SetLayout(nanoguiext.NewExpandBoxLayout(nanogui.Horizontal, nanogui.Fill))
+	panel.SetSize(300, 40)
+	a := nanogui.NewWidget(panel)
+	b := nanogui.NewWidget(panel)
+	c := nanogui.NewWidget(panel)
+	a.SetMaximumSize(60, 0)
+	c.SetMaximumSize(0, 25)
+	panel.OnPerformLayout(panel, screen.Context())
+	// a stops at its maximum width and the others share the rest
+	if a.Width() != 60 || b.Width() != 120 || c.Width() != 120 {
+		t.Errorf("widths %d, %d, %d, want 60, 120, 120", a.Width(), b.Width(), c.Width())
+	}
+	if a.Height() != 40 || c.Height() != 25 {
+		t.Errorf("heights %d, %d, want 40, 25", a.Height(), c.Height())
+	}
+
+	// preferred sizes are cached, so changing the current size needs an invalidation
+	a.SetSize(30, 0)
+	b.SetSize(40, 0)
+	a.InvalidateLayout()
+	b.InvalidateLayout()
+	c.SetSizePolicy(nanogui.SizeExpanding, nanogui.SizePreferred)
+	panel.OnPerformLayout(panel, screen.Context())
+	if a.Width() != 30 || b.Width() != 40 || c.Width() != 230 {
+		t.Errorf("only the expanding widget should grow: widths %d, %d, %d, want 30, 40, 230", a.Width(), b.Width(), c.Width())
+	}
+}
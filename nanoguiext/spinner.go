@@ -107,15 +107,15 @@ func (s *Spinner) LineWidth() float32 {
 	return s.filter.lineWidth
 }
 
-func (s *Spinner) OnPerformLayout(self nanogui.Widget, ctx *nanovgo.Context) {
+func (s *Spinner) OnPerformLayout(self nanogui.Widget, ctx nanogui.DrawContext) {
 	s.filter.SetPosition(s.Parent().AbsolutePosition())
 }
 
-func (s *Spinner) PreferredSize(self nanogui.Widget, ctx *nanovgo.Context) (int, int) {
+func (s *Spinner) PreferredSize(self nanogui.Widget, ctx nanogui.DrawContext) (int, int) {
 	return 0, 0
 }
 
-func (s *Spinner) Draw(self nanogui.Widget, ctx *nanovgo.Context) {
+func (s *Spinner) Draw(self nanogui.Widget, ctx nanogui.DrawContext) {
 }
 
 func (s *Spinner) IsPositionAbsolute() bool {
@@ -145,7 +145,7 @@ func (sf *SpinnerFilter) IsPositionAbsolute() bool {
 	return true
 }
 
-func (sf *SpinnerFilter) PreferredSize(self nanogui.Widget, ctx *nanovgo.Context) (int, int) {
+func (sf *SpinnerFilter) PreferredSize(self nanogui.Widget, ctx nanogui.DrawContext) (int, int) {
 	if sf.isActive() {
 		fw, fh := sf.Parent().Size()
 		if window, ok := sf.Parent().(*nanogui.Window); ok {
@@ -158,7 +158,7 @@ func (sf *SpinnerFilter) PreferredSize(self nanogui.Widget, ctx *nanovgo.Context
 	}
 }
 
-func (sf *SpinnerFilter) Draw(self nanogui.Widget, ctx *nanovgo.Context) {
+func (sf *SpinnerFilter) Draw(self nanogui.Widget, ctx nanogui.DrawContext) {
 	if sf.isActive() {
 		var py int
 		fw, fh := sf.Parent().Size()
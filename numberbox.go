@@ -0,0 +1,523 @@
+package nanogui
+
+import (
+	"fmt"
+	"github.com/shibukawa/glfw"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// numberBox is implemented by IntBox and FloatBox to share spin, scroll, key and scrub handling
+type numberBox interface {
+	Widget
+	textBox() *TextBox
+	// stepValue() adds steps * scale * Step() to the value. scale is the multiplier of the modifier keys
+	stepValue(steps, scale float64)
+	// markScrubStart() remembers the value when a drag starts
+	markScrubStart()
+	// scrubValue() sets the value to the remembered value + steps * scale * Step()
+	scrubValue(steps, scale float64)
+}
+
+// scrubPixels is the drag distance which changes the value by one step
+const scrubPixels = 10
+
+type IntBox struct {
+	TextBox
+
+	number     int64
+	scrubStart int64
+	minValue   int64
+	maxValue   int64
+	hasRange   bool
+	signed     bool
+	step       int64
+	callback   func(int64)
+}
+
+func NewIntBox(parent Widget, signed bool, values ...int64) *IntBox {
+	var value int64
+	switch len(values) {
+	case 0:
+		value = 0
+	case 1:
+		value = values[0]
+	default:
+		panic("NewIntBox can accept only one extra parameter (value)")
+	}
+
+	intBox := &IntBox{
+		signed: signed,
+		step:   1,
+	}
+	InitWidget(intBox, parent)
+	intBox.init("")
+	if signed {
+		intBox.SetFormat(`^[-]?[0-9]*$`)
+	} else {
+		intBox.SetFormat(`^[0-9]*$`)
+	}
+	intBox.ClearRange()
+	intBox.SetValue(value)
+	return intBox
+}
+
+func (i *IntBox) Value() int64 {
+	return i.number
+}
+
+// SetValue() sets the value clamped to the range. The callback isn't called
+func (i *IntBox) SetValue(value int64) {
+	i.setNumber(value, false)
+}
+
+func (i *IntBox) DefaultValue() int64 {
+	v, _ := strconv.ParseInt(i.defaultValue, 10, 64)
+	return v
+}
+
+func (i *IntBox) SetDefaultValue(value int64) {
+	i.defaultValue = strconv.FormatInt(value, 10)
+}
+
+// Range() returns the minimum and maximum values
+func (i *IntBox) Range() (int64, int64) {
+	return i.minValue, i.maxValue
+}
+
+// SetRange() limits the value to [min, max]. Stepping and SetValue() clamp the value,
+// typed values outside of the range are rejected by a validator
+func (i *IntBox) SetRange(min, max int64) {
+	i.minValue, i.maxValue = min, max
+	i.hasRange = true
+	i.rangeValidator = IntRangeValidator(min, max)
+	i.setNumber(i.number, false)
+	i.updateValidation()
+}
+
+// ClearRange() removes the range set by SetRange(). Unsigned int boxes keep 0 as their minimum
+func (i *IntBox) ClearRange() {
+	i.minValue, i.maxValue = math.MinInt64, math.MaxInt64
+	if !i.signed {
+		i.minValue = 0
+	}
+	i.hasRange = false
+	i.rangeValidator = nil
+	i.updateValidation()
+}
+
+// Step() returns the amount added by spin arrows, arrow keys, the scroll wheel and dragging
+func (i *IntBox) Step() int64 {
+	return i.step
+}
+
+// SetStep() sets the amount added by one step (default: 1)
+func (i *IntBox) SetStep(step int64) {
+	i.step = step
+}
+
+// SetCallback() sets the callback called when the value is committed or changed by stepping
+func (i *IntBox) SetCallback(callback func(int64)) {
+	i.callback = callback
+}
+
+func (i *IntBox) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	return numberBoxMouseButtonEvent(i, self, x, y, button, down, modifier)
+}
+
+func (i *IntBox) MouseDragEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	return numberBoxMouseDragEvent(i, self, x, y, relX, relY, button, modifier)
+}
+
+func (i *IntBox) ScrollEvent(self Widget, x, y, relX, relY int) bool {
+	return numberBoxScrollEvent(i, self, x, y, relX, relY)
+}
+
+func (i *IntBox) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	return numberBoxKeyboardEvent(i, self, key, scanCode, action, modifier)
+}
+
+func (i *IntBox) FocusEvent(self Widget, focused bool) bool {
+	commit := i.editable && !focused && !i.committed && i.validationError == nil
+	i.TextBox.FocusEvent(self, focused)
+	if commit {
+		v, err := strconv.ParseInt(i.value, 10, 64)
+		if err != nil {
+			// e.g. only "-" was typed
+			v = i.number
+		}
+		i.setNumber(v, false)
+		if i.callback != nil {
+			i.callback(i.number)
+		}
+	}
+	return true
+}
+
+func (i *IntBox) String() string {
+	return i.StringHelper("IntBox", i.value)
+}
+
+func (i *IntBox) textBox() *TextBox {
+	return &i.TextBox
+}
+
+func (i *IntBox) stepValue(steps, scale float64) {
+	base := i.number
+	if !i.committed {
+		if v, err := strconv.ParseInt(string(i.valueTemp), 10, 64); err == nil {
+			base = v
+		}
+	}
+	delta := int64(math.Round(steps * scale * float64(i.step)))
+	if delta == 0 && steps != 0 {
+		delta = int64(math.Copysign(1, steps))
+	}
+	i.setNumber(addInt64(base, delta), true)
+	i.replaceEditingText(i.value)
+}
+
+func (i *IntBox) markScrubStart() {
+	i.scrubStart = i.number
+}
+
+func (i *IntBox) scrubValue(steps, scale float64) {
+	i.setNumber(addInt64(i.scrubStart, int64(steps*scale*float64(i.step))), true)
+}
+
+// setNumber() clamps and stores the value. If notify is true, the callback is called when the value changed
+func (i *IntBox) setNumber(value int64, notify bool) {
+	if value < i.minValue {
+		value = i.minValue
+	} else if value > i.maxValue {
+		value = i.maxValue
+	}
+	changed := value != i.number
+	i.number = value
+	i.value = strconv.FormatInt(value, 10)
+	if notify && changed && i.callback != nil {
+		i.callback(value)
+	}
+}
+
+type FloatBox struct {
+	TextBox
+
+	number     float64
+	scrubStart float64
+	minValue   float64
+	maxValue   float64
+	hasRange   bool
+	step       float64
+	precision  int
+	callback   func(float64)
+}
+
+func NewFloatBox(parent Widget, values ...float64) *FloatBox {
+	var value float64
+	switch len(values) {
+	case 0:
+		value = 0.0
+	case 1:
+		value = values[0]
+	default:
+		panic("NewFloatBox can accept only one extra parameter (value)")
+	}
+
+	floatBox := &FloatBox{
+		minValue:  math.Inf(-1),
+		maxValue:  math.Inf(1),
+		step:      0.1,
+		precision: -1,
+	}
+	InitWidget(floatBox, parent)
+	floatBox.init("")
+	floatBox.SetFormat(`^[-]?[0-9]*\.?[0-9]+$`)
+	floatBox.SetValue(value)
+
+	return floatBox
+}
+
+func (f *FloatBox) Value() float64 {
+	return f.number
+}
+
+// SetValue() sets the value clamped to the range. The callback isn't called
+func (f *FloatBox) SetValue(value float64) {
+	f.setNumber(value, false)
+}
+
+func (f *FloatBox) DefaultValue() float64 {
+	v, _ := strconv.ParseFloat(f.defaultValue, 64)
+	return v
+}
+
+func (f *FloatBox) SetDefaultValue(value float64) {
+	f.defaultValue = f.formatNumber(value)
+}
+
+// Range() returns the minimum and maximum values
+func (f *FloatBox) Range() (float64, float64) {
+	return f.minValue, f.maxValue
+}
+
+// SetRange() limits the value to [min, max]. Stepping and SetValue() clamp the value,
+// typed values outside of the range are rejected by a validator
+func (f *FloatBox) SetRange(min, max float64) {
+	f.minValue, f.maxValue = min, max
+	f.hasRange = true
+	f.rangeValidator = FloatRangeValidator(min, max)
+	f.setNumber(f.number, false)
+	f.updateValidation()
+}
+
+// ClearRange() removes the range set by SetRange()
+func (f *FloatBox) ClearRange() {
+	f.minValue, f.maxValue = math.Inf(-1), math.Inf(1)
+	f.hasRange = false
+	f.rangeValidator = nil
+	f.updateValidation()
+}
+
+// Step() returns the amount added by spin arrows, arrow keys, the scroll wheel and dragging
+func (f *FloatBox) Step() float64 {
+	return f.step
+}
+
+// SetStep() sets the amount added by one step (default: 0.1)
+func (f *FloatBox) SetStep(step float64) {
+	f.step = step
+}
+
+// Precision() returns the number of digits after the decimal point. -1 means as many as needed
+func (f *FloatBox) Precision() int {
+	return f.precision
+}
+
+// SetPrecision() sets the number of digits after the decimal point (default: -1, as many as needed)
+func (f *FloatBox) SetPrecision(precision int) {
+	f.precision = precision
+	f.value = f.formatNumber(f.number)
+}
+
+// SetCallback() sets the callback called when the value is committed or changed by stepping
+func (f *FloatBox) SetCallback(callback func(float64)) {
+	f.callback = callback
+}
+
+func (f *FloatBox) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	return numberBoxMouseButtonEvent(f, self, x, y, button, down, modifier)
+}
+
+func (f *FloatBox) MouseDragEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	return numberBoxMouseDragEvent(f, self, x, y, relX, relY, button, modifier)
+}
+
+func (f *FloatBox) ScrollEvent(self Widget, x, y, relX, relY int) bool {
+	return numberBoxScrollEvent(f, self, x, y, relX, relY)
+}
+
+func (f *FloatBox) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	return numberBoxKeyboardEvent(f, self, key, scanCode, action, modifier)
+}
+
+func (f *FloatBox) FocusEvent(self Widget, focused bool) bool {
+	commit := f.editable && !focused && !f.committed && f.validationError == nil
+	f.TextBox.FocusEvent(self, focused)
+	if commit {
+		v, err := strconv.ParseFloat(f.value, 64)
+		if err != nil {
+			v = f.number
+		}
+		f.setNumber(v, false)
+		if f.callback != nil {
+			f.callback(f.number)
+		}
+	}
+	return true
+}
+
+func (f *FloatBox) String() string {
+	return f.StringHelper("FloatBox", f.value)
+}
+
+func (f *FloatBox) textBox() *TextBox {
+	return &f.TextBox
+}
+
+func (f *FloatBox) stepValue(steps, scale float64) {
+	base := f.number
+	if !f.committed {
+		if v, err := strconv.ParseFloat(string(f.valueTemp), 64); err == nil {
+			base = v
+		}
+	}
+	f.setNumber(f.roundStep(base+steps*scale*f.step, scale), true)
+	f.replaceEditingText(f.value)
+}
+
+func (f *FloatBox) markScrubStart() {
+	f.scrubStart = f.number
+}
+
+func (f *FloatBox) scrubValue(steps, scale float64) {
+	f.setNumber(f.roundStep(f.scrubStart+steps*scale*f.step, scale), true)
+}
+
+// roundStep() removes the rounding error of stepping, e.g. 0.1 + 0.2 becomes 0.3
+//
+// Without precision, the value keeps the digits of the step multiplied by scale, so fine steps aren't rounded away.
+func (f *FloatBox) roundStep(value, scale float64) float64 {
+	digits := f.precision
+	if digits < 0 {
+		digits = decimalPlaces(f.step) + decimalPlaces(scale)
+	}
+	unit := math.Pow(10, float64(digits))
+	return math.Round(value*unit) / unit
+}
+
+// setNumber() clamps and stores the value. If notify is true, the callback is called when the value changed
+func (f *FloatBox) setNumber(value float64, notify bool) {
+	value = math.Max(f.minValue, math.Min(f.maxValue, value))
+	changed := value != f.number
+	f.number = value
+	f.value = f.formatNumber(value)
+	if notify && changed && f.callback != nil {
+		f.callback(value)
+	}
+}
+
+func (f *FloatBox) formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', f.precision, 64)
+}
+
+func numberBoxMouseButtonEvent(n numberBox, self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	t := n.textBox()
+	if !t.editable || t.Focused() || button != glfw.MouseButton1 {
+		return t.MouseButtonEvent(self, x, y, button, down, modifier)
+	}
+	// not focused: the spin arrows step the value, dragging scrubs it and a click starts editing
+	if down {
+		if area := t.spinArea(x, y); area != spinAreaNone {
+			n.stepValue(toF64(area == spinAreaTop, 1, -1), stepScale(modifier))
+			return true
+		}
+		t.scrubPending = true
+		t.scrubbing = false
+		t.scrubX = x
+		n.markScrubStart()
+		return true
+	}
+	if t.scrubPending {
+		t.scrubPending = false
+		if !t.scrubbing {
+			self.RequestFocus(self)
+			t.selectionPos = 0
+			t.cursorPos = len(t.valueTemp)
+		}
+		t.scrubbing = false
+	}
+	return true
+}
+
+func numberBoxMouseDragEvent(n numberBox, self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	t := n.textBox()
+	if !t.scrubPending {
+		return t.MouseDragEvent(self, x, y, relX, relY, button, modifier)
+	}
+	dx := x - t.scrubX
+	if !t.scrubbing && absI(dx) >= 3 {
+		t.scrubbing = true
+	}
+	if t.scrubbing {
+		n.scrubValue(float64(dx)/scrubPixels, stepScale(modifier))
+	}
+	return true
+}
+
+func numberBoxScrollEvent(n numberBox, self Widget, x, y, relX, relY int) bool {
+	t := n.textBox()
+	if !t.editable || !t.enabled || relY == 0 {
+		return t.ScrollEvent(self, x, y, relX, relY)
+	}
+	var modifier glfw.ModifierKey
+	if screen := findScreen(self); screen != nil {
+		modifier = screen.modifiers
+	}
+	n.stepValue(float64(signI(relY)), stepScale(modifier))
+	return true
+}
+
+func numberBoxKeyboardEvent(n numberBox, self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	t := n.textBox()
+	if t.editable && t.Focused() && (action == glfw.Press || action == glfw.Repeat) && len(t.preeditText) == 0 {
+		switch key {
+		case glfw.KeyUp:
+			n.stepValue(1, stepScale(modifier))
+			return true
+		case glfw.KeyDown:
+			n.stepValue(-1, stepScale(modifier))
+			return true
+		case glfw.KeyPageUp:
+			n.stepValue(10, stepScale(modifier))
+			return true
+		case glfw.KeyPageDown:
+			n.stepValue(-10, stepScale(modifier))
+			return true
+		}
+	}
+	return t.KeyboardEvent(self, key, scanCode, action, modifier)
+}
+
+// stepScale() returns the step multiplier of the modifier keys: Shift x10, Ctrl or Alt x0.1
+func stepScale(modifier glfw.ModifierKey) float64 {
+	switch modifier &^ glfw.ModSuper {
+	case glfw.ModShift:
+		return 10
+	case glfw.ModControl, glfw.ModAlt:
+		return 0.1
+	}
+	return 1
+}
+
+// IntRangeValidator() returns a TextBox validator which accepts integers in [min, max]
+func IntRangeValidator(min, max int64) func(string) error {
+	return func(text string) error {
+		v, err := strconv.ParseInt(text, 10, 64)
+		if err != nil || v < min || v > max {
+			return fmt.Errorf("enter a number between %d and %d", min, max)
+		}
+		return nil
+	}
+}
+
+// FloatRangeValidator() returns a TextBox validator which accepts numbers in [min, max]
+func FloatRangeValidator(min, max float64) func(string) error {
+	return func(text string) error {
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil || v < min || v > max {
+			return fmt.Errorf("enter a number between %s and %s", strconv.FormatFloat(min, 'g', -1, 64), strconv.FormatFloat(max, 'g', -1, 64))
+		}
+		return nil
+	}
+}
+
+// addInt64() adds without overflow
+func addInt64(a, b int64) int64 {
+	if b > 0 && a > math.MaxInt64-b {
+		return math.MaxInt64
+	} else if b < 0 && a < math.MinInt64-b {
+		return math.MinInt64
+	}
+	return a + b
+}
+
+// decimalPlaces() returns the number of digits after the decimal point
+func decimalPlaces(value float64) int {
+	text := strconv.FormatFloat(value, 'f', -1, 64)
+	if index := strings.IndexByte(text, '.'); index != -1 {
+		return len(text) - index - 1
+	}
+	return 0
+}
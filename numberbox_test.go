@@ -0,0 +1,126 @@
+package nanogui
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+func TestIntBoxSpinAndScrub(t *testing.T) {
+	screen, window := newTestWindow(t)
+	intBox := NewIntBox(window, true, 5)
+	intBox.SetEditable(true)
+	intBox.SetSpinnable(true)
+	intBox.SetRange(0, 100)
+	var committed []int64
+	intBox.SetCallback(func(value int64) { committed = append(committed, value) })
+	screen.PerformLayout()
+	screen.DrawAll()
+
+	in := screen.Input()
+	x, y := intBox.AbsolutePosition()
+	in.ClickAt(x+3, y+2)
+	if intBox.Value() != 6 || intBox.Focused() {
+		t.Fatalf("up arrow: Value() = %d, focused %v, want 6 without focus", intBox.Value(), intBox.Focused())
+	}
+	in.ClickAt(x+3, y+intBox.Height()-2, glfw.ModShift)
+	if intBox.Value() != 0 {
+		t.Fatalf("Shift+down arrow should step by 10 and clamp: Value() = %d", intBox.Value())
+	}
+	in.KeyUp(glfw.KeyLeftShift, 0)
+	in.MoveTo(x+40, y+5)
+	in.Scroll(0, 1)
+	in.Scroll(0, 1)
+	if intBox.Value() != 2 {
+		t.Fatalf("scrolling: Value() = %d, want 2", intBox.Value())
+	}
+	in.Drag(x+40, y+5, x+95, y+5)
+	if intBox.Value() != 7 || intBox.Focused() {
+		t.Fatalf("scrubbing: Value() = %d, want 7", intBox.Value())
+	}
+
+	in.ClickAt(x+40, y+5)
+	if !intBox.Focused() {
+		t.Fatal("a click without dragging should start editing")
+	}
+	in.PressKey(glfw.KeyUp, 0)
+	if intBox.Value() != 8 || string(intBox.valueTemp) != "8" {
+		t.Fatalf("Up key: Value() = %d, text %q, want 8", intBox.Value(), string(intBox.valueTemp))
+	}
+	in.TypeText("99")
+	in.PressKey(glfw.KeyEnter, 0)
+	if intBox.Value() != 8 || intBox.ValidationError() == nil || !intBox.Focused() {
+		t.Fatalf("899 is out of the range: Value() = %d", intBox.Value())
+	}
+	for i := 0; i < 3; i++ {
+		in.PressKey(glfw.KeyBackspace, 0)
+	}
+	in.TypeText("42")
+	in.PressKey(glfw.KeyEnter, 0)
+	if intBox.Value() != 42 || len(committed) == 0 || committed[len(committed)-1] != 42 {
+		t.Fatalf("Enter: Value() = %d, callbacks %v, want 42", intBox.Value(), committed)
+	}
+}
+
+func TestFloatBoxStepping(t *testing.T) {
+	screen, window := newTestWindow(t)
+	floatBox := NewFloatBox(window, 1.0)
+	floatBox.SetEditable(true)
+	floatBox.SetFixedSize(200, 0)
+	screen.PerformLayout()
+	screen.DrawAll()
+
+	in := screen.Input()
+	x, y := floatBox.AbsolutePosition()
+	in.MoveTo(x+40, y+5)
+	in.Scroll(0, 1)
+	in.Scroll(0, 1)
+	if floatBox.Value() != 1.2 || floatBox.value != "1.2" {
+		t.Fatalf("scrolling: Value() = %v, text %q, want 1.2", floatBox.Value(), floatBox.value)
+	}
+	floatBox.SetPrecision(2)
+	if floatBox.value != "1.20" {
+		t.Errorf("SetPrecision(2): text %q, want %q", floatBox.value, "1.20")
+	}
+}
+
+func TestFloatBoxFineStep(t *testing.T) {
+	floatBox := NewFloatBox(NewHeadlessScreen(800, 600), 1.0)
+	floatBox.SetStep(0.1)
+	floatBox.stepValue(1, stepScale(glfw.ModControl))
+	if floatBox.Value() != 1.01 {
+		t.Fatalf("Ctrl step: Value() = %v, want 1.01", floatBox.Value())
+	}
+	floatBox.markScrubStart()
+	floatBox.scrubValue(3, stepScale(glfw.ModControl))
+	if floatBox.Value() != 1.04 {
+		t.Errorf("fine scrub: Value() = %v, want 1.04", floatBox.Value())
+	}
+}
+
+func TestIntBoxLargeValue(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	intBox := NewIntBox(screen, true, 5)
+	intBox.SetID("large")
+	intBox.SetValue(1 << 60)
+	if found := screen.MustQueryAll("IntBox[value=1152921504606846976]"); len(found) != 1 {
+		t.Errorf("value selector found %v", found)
+	}
+	node, err := ExportWidget(intBox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(&UIDocument{Widgets: []*UINode{node}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui, err := LoadUI(NewWidget(screen), bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value := ui.Widget("large").(*IntBox).Value(); value != 1<<60 {
+		t.Errorf("round trip: Value() = %d, want %d", value, int64(1<<60))
+	}
+}
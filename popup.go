@@ -11,6 +11,7 @@ type Popup struct {
 	anchorX      int
 	anchorY      int
 	anchorHeight int
+	hideArrow    bool
 	vScroll      *VScrollPanel
 	panel        Widget
 }
@@ -47,6 +48,16 @@ func (p *Popup) AnchorHeight() int {
 	return p.anchorHeight
 }
 
+// ArrowVisible() returns whether or not the arrow pointing to the anchor is drawn
+func (p *Popup) ArrowVisible() bool {
+	return !p.hideArrow
+}
+
+// SetArrowVisible() shows or hides the arrow pointing to the anchor (default: visible)
+func (p *Popup) SetArrowVisible(visible bool) {
+	p.hideArrow = !visible
+}
+
 // SetParentWindow() sets the parent window of the popup
 func (p *Popup) SetParentWindow(w *Window) {
 	p.parentWindow = w
@@ -57,13 +68,13 @@ func (p *Popup) ParentWindow() IWindow {
 	return p.parentWindow
 }
 
-func (p *Popup) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+func (p *Popup) OnPerformLayout(self Widget, ctx DrawContext) {
 	if p.layout != nil || len(p.children) != 1 {
 		p.WidgetImplement.OnPerformLayout(self, ctx)
 	} else {
 		p.children[0].SetPosition(0, 0)
 		p.children[0].SetSize(p.w, p.h)
-		p.children[0].OnPerformLayout(p.children[0], ctx)
+		p.children[0].LayoutIfNeeded(p.children[0], ctx)
 	}
 }
 
@@ -71,7 +82,7 @@ func (p *Popup) IsPositionAbsolute() bool {
 	return true
 }
 
-func (p *Popup) Draw(self Widget, ctx *nanovgo.Context) {
+func (p *Popup) Draw(self Widget, ctx DrawContext) {
 	p.RefreshRelativePlacement()
 
 	if !p.visible {
@@ -99,9 +110,11 @@ func (p *Popup) Draw(self Widget, ctx *nanovgo.Context) {
 	ctx.BeginPath()
 	ctx.RoundedRect(px, py, pw, ph, cr)
 
-	ctx.MoveTo(px-15, py+ah)
-	ctx.LineTo(px+1, py+ah-15)
-	ctx.LineTo(px+1, py+ah+15)
+	if !p.hideArrow {
+		ctx.MoveTo(px-15, py+ah)
+		ctx.LineTo(px+1, py+ah-15)
+		ctx.LineTo(px+1, py+ah+15)
+	}
 
 	ctx.SetFillColor(p.theme.WindowPopup)
 
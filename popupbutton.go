@@ -1,6 +1,7 @@
 package nanogui
 
 import (
+	"github.com/shibukawa/glfw"
 	"github.com/shibukawa/nanovgo"
 	"runtime"
 )
@@ -13,8 +14,9 @@ type PopupButton struct {
 
 func finalizePopupButton(button *PopupButton) {
 	if button.popup != nil {
-		parent := button.popup.Parent()
-		parent.RemoveChild(button.popup)
+		if parent := button.popup.Parent(); parent != nil {
+			parent.RemoveChild(button.popup)
+		}
 		button.popup = nil
 	}
 }
@@ -42,6 +44,7 @@ func NewPopupButton(parent Widget, captions ...string) *PopupButton {
 	button.popup.SetSize(320, 250)
 
 	InitWidget(button, parent)
+	button.SetTabStop(true)
 
 	runtime.SetFinalizer(button, finalizePopupButton)
 
@@ -60,7 +63,7 @@ func (p *PopupButton) Popup() Widget {
 	return p.popup.panel
 }
 
-func (p *PopupButton) Draw(self Widget, ctx *nanovgo.Context) {
+func (p *PopupButton) Draw(self Widget, ctx DrawContext) {
 	if !p.enabled && p.pushed {
 		p.pushed = false
 	}
@@ -81,12 +84,26 @@ func (p *PopupButton) Draw(self Widget, ctx *nanovgo.Context) {
 	}
 }
 
-func (p *PopupButton) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+// KeyboardEvent() opens or closes the popup by Space or Enter and closes it by Escape
+func (p *PopupButton) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	if p.enabled && p.focused && p.pushed && key == glfw.KeyEscape {
+		if action == glfw.Press {
+			p.pushed = false
+			if p.changeCallback != nil {
+				p.changeCallback(false)
+			}
+		}
+		return true
+	}
+	return p.Button.KeyboardEvent(self, key, scanCode, action, modifier)
+}
+
+func (p *PopupButton) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	w, h := p.Button.PreferredSize(self, ctx)
 	return w + 15, h
 }
 
-func (p *PopupButton) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+func (p *PopupButton) OnPerformLayout(self Widget, ctx DrawContext) {
 	p.Button.WidgetImplement.OnPerformLayout(self, ctx)
 	parentWindow := self.FindWindow()
 	x := parentWindow.Width() + 15
@@ -25,11 +25,11 @@ func (p *ProgressBar) SetValue(value float32) {
 	p.value = value
 }
 
-func (p *ProgressBar) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+func (p *ProgressBar) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	return 70, 12
 }
 
-func (p *ProgressBar) Draw(self Widget, ctx *nanovgo.Context) {
+func (p *ProgressBar) Draw(self Widget, ctx DrawContext) {
 	px := float32(p.x)
 	py := float32(p.y)
 	pw := float32(p.w)
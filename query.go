@@ -0,0 +1,465 @@
+package nanogui
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FindWidgetByID() searches root and its descendants for the first widget with the given ID
+func FindWidgetByID(root Widget, id string) Widget {
+	if root.ID() == id {
+		return root
+	}
+	for _, child := range root.Children() {
+		if found := FindWidgetByID(child, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Selector is a compiled widget query
+//
+// The syntax is a small subset of CSS selectors:
+//
+//	Window[title=Settings] > TextBox#port
+//	Button:enabled
+//	PopupButton [caption^="Open"]
+//
+// A compound selector consists of an optional type name (or *), #id,
+// [attribute=value] filters and :state filters. Compound selectors are
+// combined with > (child) or whitespace (descendant).
+//
+// Supported attributes are id, type, caption, title, value and tooltip, with
+// the operators =, !=, ^=, $= and *=. Supported states are enabled, disabled,
+// visible, hidden, focused, checked, unchecked, pushed and editable.
+type Selector struct {
+	source    string
+	compounds []selectorCompound
+}
+
+type selectorCombinator int
+
+const (
+	combinatorDescendant selectorCombinator = iota
+	combinatorChild
+)
+
+type selectorAttribute struct {
+	name     string
+	operator string
+	value    string
+}
+
+type selectorCompound struct {
+	combinator selectorCombinator // relation to the previous compound
+	typeName   string
+	id         string
+	attributes []selectorAttribute
+	states     []string
+}
+
+// ParseSelector() compiles a widget query
+func ParseSelector(source string) (*Selector, error) {
+	p := &selectorParser{source: source}
+	compounds, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Selector{source: source, compounds: compounds}, nil
+}
+
+// MustParseSelector() is like ParseSelector() but panics if the query can't be parsed
+func MustParseSelector(source string) *Selector {
+	selector, err := ParseSelector(source)
+	if err != nil {
+		panic(err)
+	}
+	return selector
+}
+
+// Match() returns whether the widget matches the selector
+func (s *Selector) Match(w Widget) bool {
+	return s.matchAt(w, len(s.compounds)-1)
+}
+
+// Find() returns the first widget under root (including root) in tree order that matches the selector
+func (s *Selector) Find(root Widget) Widget {
+	if s.Match(root) {
+		return root
+	}
+	for _, child := range root.Children() {
+		if found := s.Find(child); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// FindAll() returns all widgets under root (including root) in tree order that match the selector
+func (s *Selector) FindAll(root Widget) []Widget {
+	var result []Widget
+	if s.Match(root) {
+		result = append(result, root)
+	}
+	for _, child := range root.Children() {
+		result = append(result, s.FindAll(child)...)
+	}
+	return result
+}
+
+func (s *Selector) String() string {
+	return s.source
+}
+
+func (s *Selector) matchAt(w Widget, index int) bool {
+	compound := &s.compounds[index]
+	if !compound.match(w) {
+		return false
+	}
+	if index == 0 {
+		return true
+	}
+	if compound.combinator == combinatorChild {
+		parent := w.Parent()
+		return parent != nil && s.matchAt(parent, index-1)
+	}
+	for parent := w.Parent(); parent != nil; parent = parent.Parent() {
+		if s.matchAt(parent, index-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// Query() returns the first widget in the screen that matches the selector
+func (s *Screen) Query(selector string) (Widget, error) {
+	compiled, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.Find(s), nil
+}
+
+// QueryAll() returns all widgets in the screen that match the selector
+func (s *Screen) QueryAll(selector string) ([]Widget, error) {
+	compiled, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.FindAll(s), nil
+}
+
+// MustQuery() is like Query() but panics if the selector can't be parsed
+func (s *Screen) MustQuery(selector string) Widget {
+	return MustParseSelector(selector).Find(s)
+}
+
+// MustQueryAll() is like QueryAll() but panics if the selector can't be parsed
+func (s *Screen) MustQueryAll(selector string) []Widget {
+	return MustParseSelector(selector).FindAll(s)
+}
+
+// FindWidgetByID() searches the screen for the first widget with the given ID
+func (s *Screen) FindWidgetByID(id string) Widget {
+	return FindWidgetByID(s, id)
+}
+
+// WidgetTypeName() returns the type name used by selectors (e.g. "TextBox" for *TextBox, "Widget" for NewWidget())
+func WidgetTypeName(w Widget) string {
+	t := reflect.TypeOf(w)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() == "WidgetImplement" {
+		return "Widget"
+	}
+	return t.Name()
+}
+
+func (c *selectorCompound) match(w Widget) bool {
+	if c.typeName != "" && c.typeName != "*" && c.typeName != WidgetTypeName(w) {
+		return false
+	}
+	if c.id != "" && c.id != w.ID() {
+		return false
+	}
+	for _, attribute := range c.attributes {
+		if !attribute.match(w) {
+			return false
+		}
+	}
+	for _, state := range c.states {
+		if !matchState(w, state) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *selectorAttribute) match(w Widget) bool {
+	value, ok := widgetAttribute(w, a.name)
+	if !ok {
+		return a.operator == "!="
+	}
+	switch a.operator {
+	case "=":
+		return value == a.value
+	case "!=":
+		return value != a.value
+	case "^=":
+		return strings.HasPrefix(value, a.value)
+	case "$=":
+		return strings.HasSuffix(value, a.value)
+	case "*=":
+		return strings.Contains(value, a.value)
+	}
+	return false
+}
+
+func widgetAttribute(w Widget, name string) (string, bool) {
+	switch name {
+	case "id":
+		return w.ID(), true
+	case "type":
+		return WidgetTypeName(w), true
+	case "tooltip":
+		return w.Tooltip(), true
+	case "caption":
+		if c, ok := w.(interface {
+			Caption() string
+		}); ok {
+			return c.Caption(), true
+		}
+	case "title":
+		if t, ok := w.(interface {
+			Title() string
+		}); ok {
+			return t.Title(), true
+		}
+	case "value":
+		switch v := w.(type) {
+		case interface {
+			Value() string
+		}:
+			return v.Value(), true
+		case interface {
+			Value() int
+		}:
+			return fmt.Sprint(v.Value()), true
+		case interface {
+			Value() int64
+		}:
+			return fmt.Sprint(v.Value()), true
+		case interface {
+			Value() float32
+		}:
+			return fmt.Sprint(v.Value()), true
+		case interface {
+			Value() float64
+		}:
+			return fmt.Sprint(v.Value()), true
+		}
+	}
+	return "", false
+}
+
+func matchState(w Widget, state string) bool {
+	switch state {
+	case "enabled":
+		return w.Enabled()
+	case "disabled":
+		return !w.Enabled()
+	case "visible":
+		return w.VisibleRecursive()
+	case "hidden":
+		return !w.VisibleRecursive()
+	case "focused":
+		return w.Focused()
+	case "checked", "unchecked":
+		if c, ok := w.(interface {
+			Checked() bool
+		}); ok {
+			return c.Checked() == (state == "checked")
+		}
+	case "pushed":
+		if p, ok := w.(interface {
+			Pushed() bool
+		}); ok {
+			return p.Pushed()
+		}
+	case "editable":
+		if e, ok := w.(interface {
+			Editable() bool
+		}); ok {
+			return e.Editable()
+		}
+	}
+	return false
+}
+
+var selectorStates = map[string]bool{
+	"enabled":   true,
+	"disabled":  true,
+	"visible":   true,
+	"hidden":    true,
+	"focused":   true,
+	"checked":   true,
+	"unchecked": true,
+	"pushed":    true,
+	"editable":  true,
+}
+
+type selectorParser struct {
+	source string
+	pos    int
+}
+
+func (p *selectorParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("selector %q: %s at offset %d", p.source, fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *selectorParser) skipSpaces() bool {
+	start := p.pos
+	for p.pos < len(p.source) && (p.source[p.pos] == ' ' || p.source[p.pos] == '\t') {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *selectorParser) parse() ([]selectorCompound, error) {
+	var compounds []selectorCompound
+	p.skipSpaces()
+	if p.pos == len(p.source) {
+		return nil, errors.New("selector: empty query")
+	}
+	combinator := combinatorDescendant
+	for {
+		compound, err := p.parseCompound()
+		if err != nil {
+			return nil, err
+		}
+		compound.combinator = combinator
+		compounds = append(compounds, compound)
+
+		hasSpace := p.skipSpaces()
+		if p.pos == len(p.source) {
+			return compounds, nil
+		}
+		if p.source[p.pos] == '>' {
+			p.pos++
+			p.skipSpaces()
+			combinator = combinatorChild
+		} else if hasSpace {
+			combinator = combinatorDescendant
+		} else {
+			return nil, p.errorf("unexpected %q", p.source[p.pos])
+		}
+	}
+}
+
+func (p *selectorParser) parseCompound() (selectorCompound, error) {
+	var compound selectorCompound
+	start := p.pos
+	if p.pos < len(p.source) && p.source[p.pos] == '*' {
+		compound.typeName = "*"
+		p.pos++
+	} else {
+		compound.typeName = p.parseName()
+	}
+	for p.pos < len(p.source) {
+		switch p.source[p.pos] {
+		case '#':
+			p.pos++
+			compound.id = p.parseName()
+			if compound.id == "" {
+				return compound, p.errorf("missing id")
+			}
+		case ':':
+			p.pos++
+			state := p.parseName()
+			if !selectorStates[state] {
+				return compound, p.errorf("unknown state %q", state)
+			}
+			compound.states = append(compound.states, state)
+		case '[':
+			p.pos++
+			attribute, err := p.parseAttribute()
+			if err != nil {
+				return compound, err
+			}
+			compound.attributes = append(compound.attributes, attribute)
+		default:
+			if p.pos == start {
+				return compound, p.errorf("unexpected %q", p.source[p.pos])
+			}
+			return compound, nil
+		}
+	}
+	if p.pos == start {
+		return compound, p.errorf("missing selector")
+	}
+	return compound, nil
+}
+
+func (p *selectorParser) parseName() string {
+	start := p.pos
+	for p.pos < len(p.source) {
+		c := p.source[p.pos]
+		if c == '_' || c == '-' || c == '.' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') {
+			p.pos++
+		} else {
+			break
+		}
+	}
+	return p.source[start:p.pos]
+}
+
+func (p *selectorParser) parseAttribute() (selectorAttribute, error) {
+	var attribute selectorAttribute
+	p.skipSpaces()
+	attribute.name = p.parseName()
+	switch attribute.name {
+	case "id", "type", "caption", "title", "value", "tooltip":
+	case "":
+		return attribute, p.errorf("missing attribute name")
+	default:
+		return attribute, p.errorf("unknown attribute %q", attribute.name)
+	}
+	p.skipSpaces()
+	for _, operator := range []string{"=", "!=", "^=", "$=", "*="} {
+		if strings.HasPrefix(p.source[p.pos:], operator) {
+			attribute.operator = operator
+			p.pos += len(operator)
+			break
+		}
+	}
+	if attribute.operator == "" {
+		return attribute, p.errorf("missing operator")
+	}
+	p.skipSpaces()
+	if p.pos < len(p.source) && (p.source[p.pos] == '"' || p.source[p.pos] == '\'') {
+		quote := p.source[p.pos]
+		end := strings.IndexByte(p.source[p.pos+1:], quote)
+		if end == -1 {
+			return attribute, p.errorf("unterminated string")
+		}
+		attribute.value = p.source[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		p.skipSpaces()
+	} else {
+		end := strings.IndexByte(p.source[p.pos:], ']')
+		if end == -1 {
+			return attribute, p.errorf("missing ]")
+		}
+		attribute.value = strings.TrimSpace(p.source[p.pos : p.pos+end])
+		p.pos += end
+	}
+	if p.pos == len(p.source) || p.source[p.pos] != ']' {
+		return attribute, p.errorf("missing ]")
+	}
+	p.pos++
+	return attribute, nil
+}
@@ -0,0 +1,53 @@
+package nanogui
+
+import "testing"
+
+func TestQuerySelectors(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	window := NewWindow(screen, "Settings")
+	window.SetLayout(NewGroupLayout())
+	panel := NewWidget(window)
+	port := NewTextBox(panel, "8080")
+	port.SetID("port")
+	save := NewButton(window, "Save")
+	cancel := NewButton(window, "Cancel")
+	cancel.SetEnabled(false)
+
+	if screen.MustQuery("Window[title=Settings] TextBox#port") != port {
+		t.Error("descendant combinator should match a nested widget")
+	}
+	if screen.MustQuery("Window[title=Settings] > TextBox#port") != nil {
+		t.Error("child combinator shouldn't match a grandchild")
+	}
+	if screen.MustQuery(`Window[title="Settings"] > Widget > TextBox#port`) != port {
+		t.Error("chained child combinators should match the grandchild")
+	}
+	if all := screen.MustQueryAll("Button:enabled"); len(all) != 1 || all[0] != save {
+		t.Errorf("QueryAll(\"Button:enabled\") = %v, want only the Save button", all)
+	}
+	if screen.MustQuery("*[value=8080]") != port {
+		t.Error("attribute selector should match the text box value")
+	}
+	if FindWidgetByID(screen, "port") != port {
+		t.Error("FindWidgetByID() should find the text box")
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	NewButton(screen, "Save")
+	for _, selector := range []string{"Button[foo=1]", "Button >"} {
+		if _, err := ParseSelector(selector); err == nil {
+			t.Errorf("ParseSelector(%q) should fail", selector)
+		}
+		if widget, err := screen.Query(selector); err == nil || widget != nil {
+			t.Errorf("Query(%q) = %v, %v, want an error", selector, widget, err)
+		}
+		if widgets, err := screen.QueryAll(selector); err == nil || widgets != nil {
+			t.Errorf("QueryAll(%q) = %v, %v, want an error", selector, widgets, err)
+		}
+	}
+	if widgets, err := screen.QueryAll("Button"); err != nil || len(widgets) != 1 {
+		t.Errorf("QueryAll(\"Button\") = %v, %v, want the button", widgets, err)
+	}
+}
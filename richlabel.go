@@ -0,0 +1,542 @@
+package nanogui
+
+import (
+	"fmt"
+	"github.com/shibukawa/glfw"
+	"github.com/shibukawa/nanovgo"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RichLabel is a text label with inline markup
+//
+// The markup uses square bracket tags:
+//
+//	[b]bold[/b]  [i]italic[/i]
+//	[color=#ff8000]colored[/color]  [size=24]large[/size]
+//	[icon=0x2713]  [icon=0xe84d font=materialicons]
+//	[link=help]clickable text[/link]
+//
+// Tags can be nested. "[[" is a literal '['. Bold and italic text use the fonts of the
+// theme, and italic text is slanted when the theme has no italic font; icons use the theme's icon font unless a font is given. Clicking a link calls
+// the link callback with the link target. Like Label, the text is wrapped when a fixed
+// width or a column width is set.
+type RichLabel struct {
+	WidgetImplement
+	markup       string
+	spans        []richSpan
+	links        []string
+	fontFace     string
+	color        nanovgo.Color
+	columnWidth  int
+	wrap         bool
+	linkCallback func(target string)
+
+	lines       []richLine
+	pressedLink int
+}
+
+type richStyle struct {
+	bold, italic bool
+	color        *nanovgo.Color
+	size         int
+	link         int // index in the links of the markup + 1, or 0
+}
+
+type richSpan struct {
+	text     []rune
+	style    richStyle
+	icon     bool
+	iconFont string // the theme's icon font if empty
+}
+
+// richSegment is a part of a line drawn with one style
+type richSegment struct {
+	span       int
+	text       []rune
+	x, width   float32
+	fontSize   float32
+	fontFace   string
+	slanted    bool
+	lineHeight float32
+	ascender   float32
+}
+
+type richLine struct {
+	segments []richSegment
+	y        float32
+	width    float32
+	height   float32
+	ascender float32
+}
+
+// NewRichLabel() creates a label from markup. Markup which can't be parsed is shown as plain text
+func NewRichLabel(parent Widget, markup string) *RichLabel {
+	label := &RichLabel{
+		color:       parent.Theme().TextColor,
+		wrap:        true,
+		pressedLink: -1,
+	}
+	InitWidget(label, parent)
+	label.SetMarkup(markup)
+	return label
+}
+
+// Markup() returns the markup of the label
+func (l *RichLabel) Markup() string {
+	return l.markup
+}
+
+// SetMarkup() sets the markup of the label. When it returns an error, the markup is shown as plain text
+func (l *RichLabel) SetMarkup(markup string) error {
+	l.markup = markup
+	l.pressedLink = -1
+	spans, links, err := parseMarkup(markup)
+	if err != nil {
+		l.spans = []richSpan{{text: []rune(markup)}}
+		l.links = nil
+		l.InvalidateLayout()
+		return err
+	}
+	l.spans = spans
+	l.links = links
+	l.InvalidateLayout()
+	return nil
+}
+
+// PlainText() returns the text of the label without the tags (icons are left out)
+func (l *RichLabel) PlainText() string {
+	var text []rune
+	for _, span := range l.spans {
+		if !span.icon {
+			text = append(text, span.text...)
+		}
+	}
+	return string(text)
+}
+
+// Links() returns the targets of the links in the order they appear
+func (l *RichLabel) Links() []string {
+	return l.links
+}
+
+// Font() gets the font of the plain text
+func (l *RichLabel) Font() string {
+	if l.fontFace == "" {
+		return l.theme.FontNormal
+	}
+	return l.fontFace
+}
+
+// SetFont() sets the font of the plain text. Bold and italic text use the fonts of the theme
+func (l *RichLabel) SetFont(fontFace string) {
+	if l.fontFace != fontFace {
+		l.fontFace = fontFace
+		l.InvalidateLayout()
+	}
+}
+
+// Color() gets the color of the text which has no color tag
+func (l *RichLabel) Color() nanovgo.Color {
+	return l.color
+}
+
+// SetColor() sets the color of the text which has no color tag
+func (l *RichLabel) SetColor(color nanovgo.Color) {
+	l.color = color
+}
+
+func (l *RichLabel) ColumnWidth() int {
+	return l.columnWidth
+}
+
+func (l *RichLabel) SetColumnWidth(width int) {
+	if l.columnWidth != width {
+		l.columnWidth = width
+		l.InvalidateLayout()
+	}
+}
+
+func (l *RichLabel) Wrap() bool {
+	return l.wrap
+}
+
+func (l *RichLabel) SetWrap(wrap bool) {
+	if l.wrap != wrap {
+		l.wrap = wrap
+		l.InvalidateLayout()
+	}
+}
+
+// LinkCallback() returns the function called when a link is clicked
+func (l *RichLabel) LinkCallback() func(target string) {
+	return l.linkCallback
+}
+
+// SetLinkCallback() sets the function called with the target of a clicked link
+func (l *RichLabel) SetLinkCallback(callback func(target string)) {
+	l.linkCallback = callback
+}
+
+func (l *RichLabel) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	if button != glfw.MouseButton1 {
+		return l.WidgetImplement.MouseButtonEvent(self, x, y, button, down, modifier)
+	}
+	link := l.linkAt(x-l.x, y-l.y)
+	if down {
+		l.pressedLink = link
+		if link < 0 {
+			return l.WidgetImplement.MouseButtonEvent(self, x, y, button, down, modifier)
+		}
+		return true
+	}
+	pressed := l.pressedLink
+	l.pressedLink = -1
+	if pressed < 0 || pressed != link {
+		return false
+	}
+	if l.linkCallback != nil {
+		l.linkCallback(l.links[link])
+	}
+	return true
+}
+
+func (l *RichLabel) MouseMotionEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	if l.linkAt(x-l.x, y-l.y) >= 0 {
+		l.cursor = Hand
+	} else {
+		l.cursor = Arrow
+	}
+	return false
+}
+
+func (l *RichLabel) MouseEnterEvent(self Widget, x, y int, enter bool) bool {
+	if !enter {
+		l.cursor = Arrow
+	}
+	return l.WidgetImplement.MouseEnterEvent(self, x, y, enter)
+}
+
+func (l *RichLabel) PreferredSize(self Widget, ctx DrawContext) (int, int) {
+	if len(l.spans) == 0 {
+		return 0, 0
+	}
+	width := l.wrapWidth()
+	lines := l.layoutText(ctx, width)
+	var w, h float32
+	for _, line := range lines {
+		w = maxF(w, line.width)
+		h += line.height
+	}
+	if width > 0 {
+		return width, int(h)
+	}
+	return int(w), int(h)
+}
+
+func (l *RichLabel) Draw(self Widget, ctx DrawContext) {
+	l.WidgetImplement.Draw(self, ctx)
+	if len(l.spans) == 0 {
+		l.lines = nil
+		return
+	}
+	l.lines = l.layoutText(ctx, l.wrapWidth())
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignBaseline)
+	for _, line := range l.lines {
+		baseline := float32(l.y) + line.y + line.ascender
+		for _, segment := range line.segments {
+			style := l.spans[segment.span].style
+			color := l.color
+			if style.color != nil {
+				color = *style.color
+			} else if style.link > 0 {
+				color = l.theme.LinkColor
+			}
+			ctx.SetFontSize(segment.fontSize)
+			ctx.SetFontFace(segment.fontFace)
+			ctx.SetFillColor(color)
+			x := float32(l.x) + segment.x
+			if segment.slanted {
+				ctx.Save()
+				ctx.Translate(x, baseline)
+				ctx.SkewX(-italicSkew)
+				ctx.Text(0, 0, string(segment.text))
+				ctx.Restore()
+			} else {
+				ctx.Text(x, baseline, string(segment.text))
+			}
+			if style.link > 0 && !l.spans[segment.span].icon {
+				ctx.BeginPath()
+				ctx.MoveTo(x, baseline+1.5)
+				ctx.LineTo(x+segment.width, baseline+1.5)
+				ctx.SetStrokeColor(color)
+				ctx.SetStrokeWidth(1)
+				ctx.Stroke()
+			}
+		}
+	}
+}
+
+func (l *RichLabel) String() string {
+	return l.StringHelper("RichLabel", l.PlainText())
+}
+
+func (l *RichLabel) wrapWidth() int {
+	if l.FixedWidth() > 0 {
+		return l.FixedWidth()
+	} else if l.columnWidth > 0 && l.wrap {
+		return l.columnWidth
+	}
+	return 0
+}
+
+// linkAt() returns the index of the link at the position relative to the label, or -1
+func (l *RichLabel) linkAt(x, y int) int {
+	px, py := float32(x), float32(y)
+	for _, line := range l.lines {
+		if py < line.y || py >= line.y+line.height {
+			continue
+		}
+		for _, segment := range line.segments {
+			if link := l.spans[segment.span].style.link; link > 0 && px >= segment.x && px < segment.x+segment.width {
+				return link - 1
+			}
+		}
+	}
+	return -1
+}
+
+// italicSkew is the slant in radians of the italic style synthesized for themes without italic fonts
+const italicSkew = 0.2
+
+// fontOf() returns the font face and the font size of a span, and whether or not the italic style is synthesized by a skew
+func (l *RichLabel) fontOf(span *richSpan) (string, float32, bool) {
+	size := l.FontSize()
+	if span.style.size > 0 {
+		size = span.style.size
+	}
+	if span.icon {
+		if span.iconFont == "" {
+			return l.theme.FontIcons, float32(size), false
+		}
+		return span.iconFont, float32(size), false
+	}
+	switch {
+	case span.style.bold && span.style.italic && l.theme.FontBoldItalic != "":
+		return l.theme.FontBoldItalic, float32(size), false
+	case span.style.bold:
+		return l.theme.FontBold, float32(size), span.style.italic
+	case span.style.italic && l.theme.FontItalic != "":
+		return l.theme.FontItalic, float32(size), false
+	}
+	return l.Font(), float32(size), span.style.italic
+}
+
+// layoutText() breaks the spans into lines. When width is 0, lines break only at newlines
+func (l *RichLabel) layoutText(ctx DrawContext, width int) []richLine {
+	// the text of all spans is laid out as one string; owners maps each rune to its span
+	var text []rune
+	var owners []int
+	for i, span := range l.spans {
+		text = append(text, span.text...)
+		for range span.text {
+			owners = append(owners, i)
+		}
+	}
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignBaseline)
+	segmentsOf := func(begin, end int) []richSegment {
+		var segments []richSegment
+		var x float32
+		for start := begin; start < end; {
+			stop := start + 1
+			for stop < end && owners[stop] == owners[start] {
+				stop++
+			}
+			span := &l.spans[owners[start]]
+			face, size, slanted := l.fontOf(span)
+			ctx.SetFontFace(face)
+			ctx.SetFontSize(size)
+			w, _ := ctx.TextBounds(0, 0, string(text[start:stop]))
+			ascender, _, lineHeight := ctx.TextMetrics()
+			segments = append(segments, richSegment{
+				span:       owners[start],
+				text:       text[start:stop],
+				x:          x,
+				width:      w,
+				fontSize:   size,
+				fontFace:   face,
+				slanted:    slanted,
+				lineHeight: lineHeight,
+				ascender:   ascender,
+			})
+			x += w
+			start = stop
+		}
+		return segments
+	}
+	measure := func(begin, end int) float32 {
+		var w float32
+		for _, segment := range segmentsOf(begin, end) {
+			w += segment.width
+		}
+		return w
+	}
+
+	var lines []richLine
+	var y float32
+	for begin := 0; begin <= len(text); {
+		end := begin
+		for end < len(text) && text[end] != '\n' {
+			end++
+		}
+		ranges := [][2]int{{begin, end}}
+		if width > 0 {
+			ranges = breakLines(text[begin:end], float32(width), func(b, e int) float32 {
+				return measure(begin+b, begin+e)
+			})
+			for i := range ranges {
+				ranges[i][0] += begin
+				ranges[i][1] += begin
+			}
+		}
+		for _, r := range ranges {
+			line := richLine{segments: segmentsOf(r[0], r[1]), y: y}
+			if len(line.segments) == 0 {
+				// an empty line has the height of the span it is in
+				span := len(l.spans) - 1
+				if r[0] < len(owners) {
+					span = owners[r[0]]
+				}
+				face, size, _ := l.fontOf(&l.spans[span])
+				ctx.SetFontFace(face)
+				ctx.SetFontSize(size)
+				line.ascender, _, line.height = ctx.TextMetrics()
+			}
+			for _, segment := range line.segments {
+				line.width = segment.x + segment.width
+				line.height = maxF(line.height, segment.lineHeight)
+				line.ascender = maxF(line.ascender, segment.ascender)
+			}
+			y += line.height
+			lines = append(lines, line)
+		}
+		begin = end + 1
+	}
+	return lines
+}
+
+// parseMarkup() splits markup into spans of the same style and returns them with the link targets
+func parseMarkup(markup string) ([]richSpan, []string, error) {
+	var spans []richSpan
+	var links []string
+	var text []rune
+	type openTag struct {
+		name  string
+		style richStyle
+	}
+	var stack []openTag
+	style := richStyle{}
+	flush := func() {
+		if len(text) > 0 {
+			spans = append(spans, richSpan{text: text, style: style})
+			text = nil
+		}
+	}
+	runes := []rune(markup)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r != '[' {
+			text = append(text, r)
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == '[' {
+			text = append(text, '[')
+			i++
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && runes[end] != ']' {
+			end++
+		}
+		if end == len(runes) {
+			return nil, nil, fmt.Errorf("nanogui: markup tag at %d isn't closed", i)
+		}
+		name, value, attributes := parseMarkupTag(string(runes[i+1 : end]))
+		i = end
+		if strings.HasPrefix(name, "/") {
+			name = name[1:]
+			if len(stack) == 0 || stack[len(stack)-1].name != name {
+				return nil, nil, fmt.Errorf("nanogui: unexpected markup tag [/%s]", name)
+			}
+			flush()
+			style = stack[len(stack)-1].style
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		next := style
+		switch name {
+		case "b":
+			next.bold = true
+		case "i":
+			next.italic = true
+		case "color":
+			color, err := parseColor(value)
+			if err != nil {
+				return nil, nil, fmt.Errorf("nanogui: invalid color %q in markup", value)
+			}
+			next.color = &color
+		case "size":
+			size, err := strconv.Atoi(value)
+			if err != nil || size <= 0 {
+				return nil, nil, fmt.Errorf("nanogui: invalid font size %q in markup", value)
+			}
+			next.size = size
+		case "link":
+			if style.link > 0 {
+				return nil, nil, fmt.Errorf("nanogui: links in markup can't be nested")
+			}
+			links = append(links, value)
+			next.link = len(links)
+		case "icon":
+			icon, err := strconv.ParseUint(value, 0, 32)
+			if err != nil || icon == 0 {
+				return nil, nil, fmt.Errorf("nanogui: invalid icon %q in markup", value)
+			}
+			flush()
+			spans = append(spans, richSpan{text: []rune{rune(icon)}, style: style, icon: true, iconFont: attributes["font"]})
+			continue
+		default:
+			return nil, nil, fmt.Errorf("nanogui: unknown markup tag [%s]", name)
+		}
+		flush()
+		stack = append(stack, openTag{name: name, style: style})
+		style = next
+	}
+	if len(stack) > 0 {
+		return nil, nil, fmt.Errorf("nanogui: markup tag [%s] isn't closed", stack[len(stack)-1].name)
+	}
+	flush()
+	return spans, links, nil
+}
+
+// parseMarkupTag() splits the content of a tag ("icon=0xe84d font=materialicons") into the name, its value and the other attributes
+func parseMarkupTag(tag string) (string, string, map[string]string) {
+	fields := strings.FieldsFunc(tag, unicode.IsSpace)
+	if len(fields) == 0 {
+		return "", "", nil
+	}
+	name, value := fields[0], ""
+	if index := strings.IndexByte(name, '='); index >= 0 {
+		name, value = name[:index], name[index+1:]
+	}
+	attributes := make(map[string]string)
+	for _, field := range fields[1:] {
+		if index := strings.IndexByte(field, '='); index >= 0 {
+			attributes[field[:index]] = field[index+1:]
+		} else {
+			attributes[field] = ""
+		}
+	}
+	return strings.ToLower(name), value, attributes
+}
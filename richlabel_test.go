@@ -0,0 +1,120 @@
+package nanogui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMarkup(t *testing.T) {
+	spans, links, err := parseMarkup("a [b]bold [i]both[/i][/b] [color=#f00]red[/color] [icon=0x2713] [[x] [link=help]help me[/link]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 1 || links[0] != "help" {
+		t.Errorf("links = %q, want [help]", links)
+	}
+	if string(spans[1].text) != "bold " || !spans[1].style.bold || spans[1].style.italic {
+		t.Errorf("second span = %+v, want bold %q", spans[1], "bold ")
+	}
+	if string(spans[2].text) != "both" || !spans[2].style.bold || !spans[2].style.italic {
+		t.Errorf("third span = %+v, want bold italic %q", spans[2], "both")
+	}
+	for _, markup := range []string{"[b]x", "[b]x[/i]", "[foo]", "[color=red]x[/color]", "[size=0]x[/size]", "[icon=zz]", "[b"} {
+		if _, _, err := parseMarkup(markup); err == nil {
+			t.Errorf("parseMarkup(%q) should fail", markup)
+		}
+	}
+}
+
+func TestRichLabelLayoutAndLinks(t *testing.T) {
+	screen, window := newTestWindow(t)
+	label := NewRichLabel(window, "hello [b]world[/b] [size=32]big[/size] [link=docs]read the docs[/link] tail")
+	ctx := screen.Context()
+	w, h := label.PreferredSize(label, ctx)
+	if h < 32 {
+		t.Errorf("the line should be as tall as the largest span: preferred height %d", h)
+	}
+	label.SetColumnWidth(100)
+	if cw, ch := label.PreferredSize(label, ctx); cw != 100 || ch <= h {
+		t.Errorf("column width 100: preferred size %dx%d, want 100 wide and taller than %d (single line width %d)", cw, ch, h, w)
+	}
+
+	clicked := ""
+	label.SetLinkCallback(func(target string) { clicked = target })
+	screen.PerformLayout()
+	screen.DrawAll()
+	var lx, ly int
+	for _, line := range label.lines {
+		for _, segment := range line.segments {
+			if label.spans[segment.span].style.link > 0 {
+				lx = int(segment.x + segment.width/2)
+				ly = int(line.y + line.height/2)
+			}
+		}
+	}
+	x, y := label.AbsolutePosition()
+	screen.Input().ClickAt(x+lx, y+ly)
+	if clicked != "docs" {
+		t.Fatalf("clicking the link: callback got %q, want %q", clicked, "docs")
+	}
+	clicked = ""
+	screen.Input().ClickAt(x+1, y+1)
+	if clicked != "" {
+		t.Errorf("clicking plain text calls the link callback with %q", clicked)
+	}
+
+	if err := label.SetMarkup("[b]oops"); err == nil || label.PlainText() != "[b]oops" {
+		t.Errorf("invalid markup: error %v, PlainText() = %q, want an error and the raw markup", err, label.PlainText())
+	}
+}
+
+func TestRichLabelDeclarative(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	ui, err := LoadUI(screen, strings.NewReader(`{"widgets": [{"type": "RichLabel", "id": "rich", "markup": "[i]x[/i] [link=a]y[/link]", "columnWidth": 50}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	label := ui.Widget("rich").(*RichLabel)
+	if err := ui.Bind("rich", func(string) {}); err != nil {
+		t.Error(err)
+	}
+	node, err := ExportWidget(label)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Markup != "[i]x[/i] [link=a]y[/link]" || node.ColumnWidth != 50 {
+		t.Errorf("exported markup %q and column width %d", node.Markup, node.ColumnWidth)
+	}
+	if name := DescribeWidget(label).Name; name != "x y" {
+		t.Errorf("accessible name %q, want the plain text %q", name, "x y")
+	}
+}
+
+func TestRichLabelSyntheticItalic(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	label := NewRichLabel(screen, "")
+	if err := label.SetMarkup("a [i]b[/i] [b][i]c[/i][/b]"); err != nil {
+		t.Fatal(err)
+	}
+	screen.PerformLayout()
+	ctx := screen.Context().(*RecordingContext)
+	countSkews := func() int {
+		ctx.Reset()
+		screen.DrawAll()
+		skews := 0
+		for _, call := range ctx.Calls() {
+			if call.Name == "SkewX" {
+				skews++
+			}
+		}
+		return skews
+	}
+	if skews := countSkews(); skews != 2 {
+		t.Errorf("without italic fonts, both italic spans should be skewed: %d skews", skews)
+	}
+	screen.Theme().FontItalic = "sans"
+	screen.Theme().FontBoldItalic = "sans-bold"
+	if skews := countSkews(); skews != 0 {
+		t.Errorf("with italic fonts, nothing should be skewed: %d skews", skews)
+	}
+}
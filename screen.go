@@ -13,7 +13,7 @@ var nanoguiScreens map[*glfw.Window]*Screen = map[*glfw.Window]*Screen{}
 type Screen struct {
 	WidgetImplement
 	window                 *glfw.Window
-	context                *nanovgo.Context
+	context                DrawContext
 	cursors                [3]int
 	cursor                 Cursor
 	focusPath              []Widget
@@ -28,6 +28,9 @@ type Screen struct {
 	backgroundColor        nanovgo.Color
 	caption                string
 	shutdownGLFWOnDestruct bool
+	preeditCursor          [3]int
+	accessibility          *AccessibilityTracker
+	clipboard              Clipboard
 
 	drawContentsCallback func()
 	dropEventCallback    func([]string) bool
@@ -145,8 +148,8 @@ func NewScreen(width, height int, caption string, resizable, fullScreen bool) *S
 
 func finalizeScreen(s *Screen) {
 	delete(nanoguiScreens, s.window)
-	if s.context != nil {
-		s.context.Delete()
+	if context, ok := s.context.(*nanovgo.Context); ok {
+		context.Delete()
 		s.context = nil
 	}
 	if s.window != nil && s.shutdownGLFWOnDestruct {
@@ -160,11 +163,11 @@ func (s *Screen) Initialize(window *glfw.Window, shutdownGLFWOnDestruct bool) {
 	s.shutdownGLFWOnDestruct = shutdownGLFWOnDestruct
 	s.w, s.h = window.GetSize()
 	s.fbW, s.fbH = window.GetFramebufferSize()
-	var err error
-	s.context, err = nanovgo.NewContext(nanovgo.StencilStrokes | nanovgo.AntiAlias)
+	context, err := nanovgo.NewContext(nanovgo.StencilStrokes | nanovgo.AntiAlias)
 	if err != nil {
 		panic(err)
 	}
+	s.context = context
 	s.visible = true //window.GetAttrib(glfw.Visible)
 	s.theme = NewStandardTheme(s.context)
 	s.mousePosX = 0
@@ -173,6 +176,9 @@ func (s *Screen) Initialize(window *glfw.Window, shutdownGLFWOnDestruct bool) {
 	s.modifiers = 0
 	s.dragActive = false
 	s.lastInteraction = GetTime()
+	if s.clipboard == nil {
+		s.clipboard = NewGLFWClipboard(window)
+	}
 	nanoguiScreens[window] = s
 	runtime.SetFinalizer(s, finalizeScreen)
 }
@@ -185,7 +191,9 @@ func (s *Screen) Caption() string {
 // SetCaption() sets the window title bar caption
 func (s *Screen) SetCaption(caption string) {
 	if s.caption != caption {
-		s.window.SetTitle(caption)
+		if s.window != nil {
+			s.window.SetTitle(caption)
+		}
 		s.caption = caption
 	}
 }
@@ -205,6 +213,9 @@ func (s *Screen) SetBackgroundColor(color nanovgo.Color) {
 func (s *Screen) SetVisible(flag bool) {
 	if s.visible != flag {
 		s.visible = flag
+		if s.window == nil {
+			return
+		}
 		if flag {
 			s.window.Show()
 		} else {
@@ -216,19 +227,30 @@ func (s *Screen) SetVisible(flag bool) {
 // SetSize() sets window size
 func (s *Screen) SetSize(w, h int) {
 	s.WidgetImplement.SetSize(w, h)
-	s.window.SetSize(w, h)
+	if s.window != nil {
+		s.window.SetSize(w, h)
+	} else {
+		s.fbW, s.fbH = w, h
+	}
 }
 
 // DrawAll() draws the Screen contents
 func (s *Screen) DrawAll() {
-	gl.ClearColor(s.backgroundColor.R, s.backgroundColor.G, s.backgroundColor.B, 1.0)
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
+	if s.window != nil {
+		gl.ClearColor(s.backgroundColor.R, s.backgroundColor.G, s.backgroundColor.B, 1.0)
+		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT | gl.STENCIL_BUFFER_BIT)
+	}
 
 	if s.drawContentsCallback != nil {
 		s.drawContentsCallback()
 	}
 	s.drawWidgets()
-	s.window.SwapBuffers()
+	if s.window != nil {
+		s.window.SwapBuffers()
+	}
+	if s.accessibility != nil && s.accessibility.bridge != nil {
+		s.accessibility.Update()
+	}
 }
 
 // SetResizeEventCallback() sets window resize event handler
@@ -308,8 +330,24 @@ func (s *Screen) GLFWWindow() *glfw.Window {
 	return s.window
 }
 
-// NVGContext() returns a pointer to the underlying nanoVGo draw context
+// Clipboard() returns the clipboard service used by the widgets on this screen
+func (s *Screen) Clipboard() Clipboard {
+	return s.clipboard
+}
+
+// SetClipboard() replaces the clipboard service (default: GLFWClipboard, MemoryClipboard for headless screens)
+func (s *Screen) SetClipboard(clipboard Clipboard) {
+	s.clipboard = clipboard
+}
+
+// NVGContext() returns a pointer to the underlying nanoVGo draw context (nil for headless screens)
 func (s *Screen) NVGContext() *nanovgo.Context {
+	context, _ := s.context.(*nanovgo.Context)
+	return context
+}
+
+// Context() returns the draw context used to measure and draw widgets
+func (s *Screen) Context() DrawContext {
 	return s.context
 }
 
@@ -367,8 +405,8 @@ func (s *Screen) DisposeWindow(window *Window) {
 func (s *Screen) CenterWindow(window *Window) {
 	w, h := window.Size()
 	if w == 0 && h == 0 {
-		window.SetSize(window.PreferredSize(window, s.context))
-		window.OnPerformLayout(window, s.context)
+		window.SetSize(LayoutSize(window, s.context))
+		window.LayoutIfNeeded(window, s.context)
 	}
 	ww, wh := window.Size()
 	pw, ph := window.Parent().Size()
@@ -407,10 +445,17 @@ func (s *Screen) MoveWindowToFront(window IWindow) {
 }
 
 func (s *Screen) PreeditCursorPos() (int, int, int) {
+	if s.window == nil {
+		return s.preeditCursor[0], s.preeditCursor[1], s.preeditCursor[2]
+	}
 	return s.window.GetPreeditCursorPos()
 }
 
 func (s *Screen) SetPreeditCursorPos(x, y, h int) {
+	if s.window == nil {
+		s.preeditCursor = [3]int{x, y, h}
+		return
+	}
 	s.window.SetPreeditCursorPos(x, y, h)
 }
 
@@ -418,51 +463,73 @@ func (s *Screen) drawWidgets() {
 	if !s.visible {
 		return
 	}
-	s.window.MakeContextCurrent()
-	s.fbW, s.fbH = s.window.GetFramebufferSize()
-	s.w, s.h = s.window.GetSize()
-	gl.Viewport(0, 0, s.fbW, s.fbH)
+	if s.window != nil {
+		s.window.MakeContextCurrent()
+		s.fbW, s.fbH = s.window.GetFramebufferSize()
+		s.w, s.h = s.window.GetSize()
+		gl.Viewport(0, 0, s.fbW, s.fbH)
+	}
 
 	s.pixelRatio = float32(s.fbW) / float32(s.w)
+	s.layoutIfNeeded()
 	s.context.BeginFrame(s.w, s.h, s.pixelRatio)
 	s.Draw(s, s.context)
 	elapsed := GetTime() - s.lastInteraction
 
+	// validation error of the focused text box
+	var errorWidget Widget
+	if len(s.focusPath) > 0 {
+		if textBox, ok := s.focusPath[0].(interface {
+			validationTooltip() string
+		}); ok {
+			if message := textBox.validationTooltip(); message != "" {
+				errorWidget = s.focusPath[0]
+				s.drawTooltip(errorWidget, message, s.theme.ErrorColor, 0.9)
+			}
+		}
+	}
+
 	if elapsed > 0.5 {
 		// Draw tooltips
 		widget := s.FindWidget(s, s.mousePosX, s.mousePosY)
-		if widget != nil && widget.Tooltip() != "" {
-			var tooltipWidth float32 = 150
-			ctx := s.context
-			ctx.SetFontFace(s.theme.FontNormal)
-			ctx.SetFontSize(15.0)
-			ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignTop)
-			ctx.SetTextLineHeight(1.1)
-			posX, posY := widget.AbsolutePosition()
-			posX += widget.Width() / 2
-			posY += widget.Height() + 10
-			bounds := ctx.TextBoxBounds(float32(posX), float32(posY), tooltipWidth, widget.Tooltip())
-			ctx.SetGlobalAlpha(minF(1.0, 2*(elapsed-0.5)) * 0.8)
-			ctx.BeginPath()
-			ctx.SetFillColor(nanovgo.MONO(0, 255))
-			h := (bounds[2] - bounds[0]) / 2
-			ctx.RoundedRect(bounds[0]-4-h, bounds[1]-4, bounds[2]-bounds[0]+8, bounds[3]-bounds[1]+8, 3)
-			px := (bounds[2]+bounds[0])/2 - h
-			ctx.MoveTo(px, bounds[1]-10)
-			ctx.LineTo(px+7, bounds[1]+1)
-			ctx.LineTo(px-7, bounds[1]+1)
-			ctx.Fill()
-
-			ctx.SetFillColor(nanovgo.MONO(255, 255))
-			ctx.SetFontBlur(0.0)
-			ctx.TextBox(float32(posX)-h, float32(posY), tooltipWidth, widget.Tooltip())
-
+		if widget != nil && widget != errorWidget && widget.Tooltip() != "" {
+			s.drawTooltip(widget, widget.Tooltip(), nanovgo.MONO(0, 255), minF(1.0, 2*(elapsed-0.5))*0.8)
 		}
 	}
 
 	s.context.EndFrame()
 }
 
+// drawTooltip() draws a bubble with text under the widget
+func (s *Screen) drawTooltip(widget Widget, text string, color nanovgo.Color, alpha float32) {
+	var tooltipWidth float32 = 150
+	ctx := s.context
+	ctx.Save()
+	ctx.SetFontFace(s.theme.FontNormal)
+	ctx.SetFontSize(15.0)
+	ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignTop)
+	ctx.SetTextLineHeight(1.1)
+	posX, posY := widget.AbsolutePosition()
+	posX += widget.Width() / 2
+	posY += widget.Height() + 10
+	bounds := ctx.TextBoxBounds(float32(posX), float32(posY), tooltipWidth, text)
+	ctx.SetGlobalAlpha(alpha)
+	ctx.BeginPath()
+	ctx.SetFillColor(color)
+	h := (bounds[2] - bounds[0]) / 2
+	ctx.RoundedRect(bounds[0]-4-h, bounds[1]-4, bounds[2]-bounds[0]+8, bounds[3]-bounds[1]+8, 3)
+	px := (bounds[2]+bounds[0])/2 - h
+	ctx.MoveTo(px, bounds[1]-10)
+	ctx.LineTo(px+7, bounds[1]+1)
+	ctx.LineTo(px-7, bounds[1]+1)
+	ctx.Fill()
+
+	ctx.SetFillColor(nanovgo.MONO(255, 255))
+	ctx.SetFontBlur(0.0)
+	ctx.TextBox(float32(posX)-h, float32(posY), tooltipWidth, text)
+	ctx.Restore()
+}
+
 func (s *Screen) cursorPositionCallbackEvent(x, y float64) bool {
 	ret := false
 	s.lastInteraction = GetTime()
@@ -535,6 +602,14 @@ func (s *Screen) mouseButtonCallbackEvent(button glfw.MouseButton, action glfw.A
 
 func (s *Screen) keyCallbackEvent(key glfw.Key, scanCode int, action glfw.Action, modifiers glfw.ModifierKey) bool {
 	s.lastInteraction = GetTime()
+	s.modifiers = modifiers
+	if key == glfw.KeyTab && action != glfw.Release && modifiers&^glfw.ModShift == 0 {
+		if modifiers&glfw.ModShift != 0 && s.FocusPrevious() {
+			return true
+		} else if modifiers&glfw.ModShift == 0 && s.FocusNext() {
+			return true
+		}
+	}
 	return s.KeyboardEvent(s, key, scanCode, action, modifiers)
 }
 
@@ -580,6 +655,9 @@ func (s *Screen) scrollCallbackEvent(x, y float32) bool {
 }
 
 func (s *Screen) resizeCallbackEvent(width, height int) bool {
+	if s.window == nil {
+		return false
+	}
 	fbW, fbH := s.window.GetFramebufferSize()
 	w, h := s.window.GetSize()
 
@@ -597,8 +675,30 @@ func (s *Screen) resizeCallbackEvent(width, height int) bool {
 	return false
 }
 
+// PerformLayout() lays out the whole widget tree again, and resizes the windows to their preferred size.
+// DrawAll() lays out only the invalidated subtrees (see Widget.InvalidateLayout())
 func (s *Screen) PerformLayout() {
-	s.OnPerformLayout(s, s.context)
+	invalidateTree(s)
+	s.LayoutIfNeeded(s, s.context)
+}
+
+// layoutIfNeeded() lays out the invalidated subtrees before drawing. The windows keep their size
+// unless they haven't got any size yet
+func (s *Screen) layoutIfNeeded() {
+	if !s.layoutDirty {
+		return
+	}
+	if s.layout != nil {
+		s.LayoutIfNeeded(s, s.context)
+		return
+	}
+	s.layoutDirty = false
+	for _, child := range s.children {
+		if w, h := child.Size(); w == 0 && h == 0 {
+			child.SetSize(LayoutSize(child, s.context))
+		}
+		child.LayoutIfNeeded(child, s.context)
+	}
 }
 
 func (s *Screen) String() string {
@@ -641,3 +741,12 @@ func (s *Screen) DebugPrint() {
 	}
 	println(buffer.String())
 }
+
+// findScreen() returns the screen which contains widget, or nil if it isn't attached to a screen
+func findScreen(widget Widget) *Screen {
+	for widget.Parent() != nil {
+		widget = widget.Parent()
+	}
+	screen, _ := widget.(*Screen)
+	return screen
+}
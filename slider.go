@@ -12,13 +12,17 @@ type Slider struct {
 	value            float32
 	highlightColor   nanovgo.Color
 	highlightedRange [2]float32
+	keyStep          float32
 	callback         func(float32)
 	finalCallback    func(float32)
 }
 
 func NewSlider(parent Widget) *Slider {
-	slider := &Slider{}
+	slider := &Slider{
+		keyStep: 0.05,
+	}
 	InitWidget(slider, parent)
+	slider.SetTabStop(true)
 	return slider
 }
 
@@ -47,6 +51,16 @@ func (s *Slider) SetHighlightedRange(l, h float32) {
 	s.highlightedRange[1] = h
 }
 
+// KeyStep() returns the amount the arrow keys change the value by
+func (s *Slider) KeyStep() float32 {
+	return s.keyStep
+}
+
+// SetKeyStep() sets the amount the arrow keys change the value by. PageUp/PageDown change it by 5 steps
+func (s *Slider) SetKeyStep(step float32) {
+	s.keyStep = step
+}
+
 func (s *Slider) SetCallback(callback func(float32)) {
 	s.callback = callback
 }
@@ -80,11 +94,46 @@ func (s *Slider) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton
 	return true
 }
 
-func (s *Slider) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+// KeyboardEvent() changes the value of the focused slider by arrow keys, PageUp/PageDown and Home/End
+func (s *Slider) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	if !s.enabled || !s.focused {
+		return false
+	}
+	value := s.value
+	switch key {
+	case glfw.KeyLeft, glfw.KeyDown:
+		value -= s.keyStep
+	case glfw.KeyRight, glfw.KeyUp:
+		value += s.keyStep
+	case glfw.KeyPageDown:
+		value -= s.keyStep * 5
+	case glfw.KeyPageUp:
+		value += s.keyStep * 5
+	case glfw.KeyHome:
+		value = 0.0
+	case glfw.KeyEnd:
+		value = 1.0
+	default:
+		return false
+	}
+	if action == glfw.Release {
+		if s.finalCallback != nil {
+			s.finalCallback(s.value)
+		}
+		return true
+	}
+	s.value = clampF(value, 0.0, 1.0)
+	if s.callback != nil {
+		s.callback(s.value)
+	}
+	return true
+}
+
+func (s *Slider) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	return 70, 12
 }
 
-func (s *Slider) Draw(self Widget, ctx *nanovgo.Context) {
+func (s *Slider) Draw(self Widget, ctx DrawContext) {
 	sx := float32(s.x)
 	sy := float32(s.y)
 	sw := float32(s.w)
@@ -142,6 +191,10 @@ func (s *Slider) Draw(self Widget, ctx *nanovgo.Context) {
 	ctx.SetFillColor(nanovgo.MONO(150, a3))
 	ctx.Stroke()
 	ctx.Fill()
+
+	if s.focused {
+		drawFocusRing(ctx, s.theme, kx-kr-2, ky-kr-2, kr*2+4, kr*2+4, kr+2)
+	}
 }
 
 func (s *Slider) String() string {
@@ -0,0 +1,998 @@
+package nanogui
+
+import (
+	"github.com/shibukawa/glfw"
+	"github.com/shibukawa/nanovgo"
+	"unicode"
+)
+
+// TextArea is a multi-line text editor
+//
+// Long lines are wrapped at the widget width (unless wrapping is disabled), the
+// content scrolls vertically and a scroll bar is shown when it doesn't fit.
+// Enter inserts a line break; the value is committed when the widget loses the focus.
+type TextArea struct {
+	WidgetImplement
+
+	fontFace            string
+	editable            bool
+	committed           bool
+	wrap                bool
+	rows                int
+	value               string
+	yankValue           []rune
+	callback            func(string) bool
+	valueTemp           []rune
+	cursorPos           int
+	selectionPos        int
+	preferredX          float32
+	scrollY             float32
+	scrollDrag          bool
+	mouseDownModifier   glfw.ModifierKey
+	lastClick           float32
+	clickCount          int
+	preeditText         []rune
+	preeditBlocks       []int
+	preeditFocusedBlock int
+	history             editHistory
+}
+
+// textAreaLine is a visual line of a TextArea
+type textAreaLine struct {
+	start, end int       // rune range in the text (end excludes the line break)
+	x          []float32 // caret positions relative to the line start (len(x) == end-start+1)
+	softBreak  bool      // the next line continues the same paragraph
+}
+
+const textAreaScrollBarWidth = 12
+
+func NewTextArea(parent Widget, values ...string) *TextArea {
+	var value string
+	switch len(values) {
+	case 0:
+	case 1:
+		value = values[0]
+	default:
+		panic("NewTextArea can accept only one extra parameter (value)")
+	}
+
+	textArea := &TextArea{
+		committed:    true,
+		wrap:         true,
+		rows:         5,
+		value:        value,
+		valueTemp:    []rune(value),
+		cursorPos:    -1,
+		selectionPos: -1,
+		preferredX:   -1,
+		history:      editHistory{limit: DefaultEditHistoryLimit},
+	}
+	InitWidget(textArea, parent)
+	textArea.fontSize = textArea.theme.TextBoxFontSize
+	textArea.tabStop = true
+	return textArea
+}
+
+func (t *TextArea) Editable() bool {
+	return t.editable
+}
+
+func (t *TextArea) SetEditable(e bool) {
+	t.editable = e
+}
+
+// TabStop() returns whether or not the text area can receive the focus by Tab / Shift+Tab. Read only text areas are skipped
+func (t *TextArea) TabStop() bool {
+	return t.editable && t.WidgetImplement.TabStop()
+}
+
+func (t *TextArea) Value() string {
+	return t.value
+}
+
+func (t *TextArea) SetValue(value string) {
+	t.value = value
+	if t.committed {
+		t.valueTemp = []rune(value)
+	}
+	t.InvalidateLayout()
+}
+
+func (t *TextArea) Font() string {
+	if t.fontFace == "" {
+		return t.theme.FontNormal
+	}
+	return t.fontFace
+}
+
+func (t *TextArea) SetFont(fontFace string) {
+	if t.fontFace != fontFace {
+		t.fontFace = fontFace
+		t.InvalidateLayout()
+	}
+}
+
+// Wrap() returns whether long lines are wrapped at the widget width
+func (t *TextArea) Wrap() bool {
+	return t.wrap
+}
+
+// SetWrap() sets whether long lines are wrapped at the widget width
+func (t *TextArea) SetWrap(wrap bool) {
+	if t.wrap != wrap {
+		t.wrap = wrap
+		t.InvalidateLayout()
+	}
+}
+
+// Rows() returns the number of lines used to compute the preferred height
+func (t *TextArea) Rows() int {
+	return t.rows
+}
+
+// SetRows() sets the number of lines used to compute the preferred height
+func (t *TextArea) SetRows(rows int) {
+	if t.rows != rows {
+		t.rows = rows
+		t.InvalidateLayout()
+	}
+}
+
+// ScrollOffset() returns the vertical scroll offset in pixels
+func (t *TextArea) ScrollOffset() float32 {
+	return t.scrollY
+}
+
+// SetCallback() sets the callback called when the value is committed. Returning false rejects the new value
+func (t *TextArea) SetCallback(callback func(string) bool) {
+	t.callback = callback
+}
+
+// CursorPosition() returns the cursor position as a rune index (-1 if the text area isn't being edited)
+func (t *TextArea) CursorPosition() int {
+	return t.cursorPos
+}
+
+// SetCursorPosition() moves the cursor to the given rune index while editing
+func (t *TextArea) SetCursorPosition(index int) {
+	if t.committed {
+		return
+	}
+	t.cursorPos = clampI(index, 0, len(t.valueTemp))
+	t.selectionPos = -1
+	t.preferredX = -1
+}
+
+// Selection() returns the selected rune range. begin == end if nothing is selected
+func (t *TextArea) Selection() (int, int) {
+	if t.selectionPos == -1 || t.cursorPos == -1 {
+		return t.cursorPos, t.cursorPos
+	}
+	if t.cursorPos < t.selectionPos {
+		return t.cursorPos, t.selectionPos
+	}
+	return t.selectionPos, t.cursorPos
+}
+
+// Undo() reverts the last edit. It returns false if there is nothing to undo
+func (t *TextArea) Undo() bool {
+	state, ok := t.history.undoStep(t.editState())
+	if ok {
+		t.restoreEditState(state)
+	}
+	return ok
+}
+
+// Redo() reapplies the last undone edit. It returns false if there is nothing to redo
+func (t *TextArea) Redo() bool {
+	state, ok := t.history.redoStep(t.editState())
+	if ok {
+		t.restoreEditState(state)
+	}
+	return ok
+}
+
+func (t *TextArea) CanUndo() bool {
+	return t.history.canUndo()
+}
+
+func (t *TextArea) CanRedo() bool {
+	return t.history.canRedo()
+}
+
+// ClearHistory() discards all undo and redo steps. The history is also cleared when the focus changes
+func (t *TextArea) ClearHistory() {
+	t.history.clear()
+}
+
+// HistoryLimit() returns the maximum number of undo steps. 0 means unlimited
+func (t *TextArea) HistoryLimit() int {
+	return t.history.limit
+}
+
+// SetHistoryLimit() sets the maximum number of undo steps (default: DefaultEditHistoryLimit). 0 means unlimited
+func (t *TextArea) SetHistoryLimit(limit int) {
+	t.history.setLimit(limit)
+}
+
+func (t *TextArea) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
+	t.WidgetImplement.MouseButtonEvent(self, x, y, button, down, modifier)
+
+	if button != glfw.MouseButton1 || len(t.preeditText) != 0 {
+		return false
+	}
+	if !down {
+		t.scrollDrag = false
+		return true
+	}
+	ctx := t.context()
+	if ctx == nil {
+		return false
+	}
+	lines := t.layoutLines(ctx)
+	if x >= t.x+t.w-textAreaScrollBarWidth && t.contentHeight(lines) > t.viewHeight() {
+		t.scrollDrag = true
+		t.scrollToBar(y, lines)
+		return true
+	}
+	if !t.editable || !t.Focused() {
+		return false
+	}
+	index := t.indexAt(ctx, lines, x, y)
+	if modifier&glfw.ModShift != 0 {
+		if t.selectionPos == -1 {
+			t.selectionPos = t.cursorPos
+		}
+	} else {
+		t.selectionPos = -1
+	}
+	t.cursorPos = index
+	t.preferredX = -1
+	t.mouseDownModifier = modifier
+
+	time := GetTime()
+	if time-t.lastClick < 0.25 {
+		t.clickCount++
+	} else {
+		t.clickCount = 1
+	}
+	if t.clickCount == 2 {
+		/* Double-click: select the word under the cursor */
+		t.selectionPos, t.cursorPos = wordRangeAt(t.valueTemp, index)
+	} else if t.clickCount >= 3 {
+		/* Triple-click: select all text */
+		t.selectionPos = 0
+		t.cursorPos = len(t.valueTemp)
+	}
+	t.lastClick = time
+	t.normalizeSelection()
+	return true
+}
+
+func (t *TextArea) MouseDragEvent(self Widget, x, y, relX, relY, button int, modifier glfw.ModifierKey) bool {
+	ctx := t.context()
+	if ctx == nil {
+		return false
+	}
+	lines := t.layoutLines(ctx)
+	if t.scrollDrag {
+		t.scrollToBar(y, lines)
+		return true
+	}
+	if t.editable && t.Focused() && len(t.preeditText) == 0 {
+		if t.selectionPos == -1 {
+			t.selectionPos = t.cursorPos
+		}
+		t.cursorPos = t.indexAt(ctx, lines, x, y)
+		t.preferredX = -1
+		t.normalizeSelection()
+		t.scrollToCursor(lines)
+		return true
+	}
+	return false
+}
+
+func (t *TextArea) ScrollEvent(self Widget, x, y, relX, relY int) bool {
+	ctx := t.context()
+	if ctx == nil {
+		return false
+	}
+	lines := t.layoutLines(ctx)
+	maxScroll := t.contentHeight(lines) - t.viewHeight()
+	if maxScroll <= 0 {
+		t.scrollY = 0
+		return false
+	}
+	t.scrollY = clampF(t.scrollY-float32(relY)*t.lineHeight(), 0, maxScroll)
+	return true
+}
+
+func (t *TextArea) FocusEvent(self Widget, focused bool) bool {
+	t.WidgetImplement.FocusEvent(self, focused)
+	backup := t.value
+
+	if t.editable {
+		t.history.clear()
+		if focused {
+			t.valueTemp = []rune(t.value)
+			t.committed = false
+			t.cursorPos = 0
+			t.selectionPos = -1
+			t.preferredX = -1
+		} else {
+			t.value = string(t.valueTemp)
+			if t.callback != nil && !t.callback(t.value) {
+				t.value = backup
+				t.valueTemp = []rune(backup)
+			}
+			t.committed = true
+			t.cursorPos = -1
+			t.selectionPos = -1
+			t.preeditText = nil
+			// the edited text updates the layout when it is committed, not on every key stroke
+			if t.value != backup {
+				t.InvalidateLayout()
+			}
+		}
+	}
+	return true
+}
+
+func (t *TextArea) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
+	if !t.editable || !t.Focused() {
+		return false
+	}
+	if (action == glfw.Press || action == glfw.Repeat) && len(t.preeditText) == 0 {
+		shift := modifier&glfw.ModShift != 0
+		editAction := DetectEditAction(key, modifier)
+		before := t.editState()
+		switch editAction {
+		case EditActionMoveLeft:
+			if t.collapseSelection(shift, false) {
+				break
+			}
+			t.moveCursor(previousGraphemeBoundary(t.valueTemp, t.cursorPos), shift)
+		case EditActionMoveRight:
+			if t.collapseSelection(shift, true) {
+				break
+			}
+			t.moveCursor(nextGraphemeBoundary(t.valueTemp, t.cursorPos), shift)
+		case EditActionMoveLeftWord:
+			t.moveCursor(previousWordBoundary(t.valueTemp, t.cursorPos), shift)
+		case EditActionMoveRightWord:
+			t.moveCursor(nextWordBoundary(t.valueTemp, t.cursorPos), shift)
+		case EditActionMoveUp, EditActionMoveDown, EditActionPageUp, EditActionPageDown,
+			EditActionMoveLineTop, EditActionMoveLineEnd:
+			t.moveVertically(editAction, shift)
+		case EditActionBackspace:
+			if !t.deleteSelection() && t.cursorPos > 0 {
+				begin := previousGraphemeBoundary(t.valueTemp, t.cursorPos)
+				t.valueTemp = append(t.valueTemp[:begin], t.valueTemp[t.cursorPos:]...)
+				t.cursorPos = begin
+			}
+		case EditActionDelete:
+			if !t.deleteSelection() && t.cursorPos < len(t.valueTemp) {
+				end := nextGraphemeBoundary(t.valueTemp, t.cursorPos)
+				t.valueTemp = append(t.valueTemp[:t.cursorPos], t.valueTemp[end:]...)
+			}
+		case EditActionDeleteLeftWord:
+			if !t.deleteSelection() {
+				begin := previousWordBoundary(t.valueTemp, t.cursorPos)
+				t.valueTemp = append(t.valueTemp[:begin], t.valueTemp[t.cursorPos:]...)
+				t.cursorPos = begin
+			}
+		case EditActionDeleteRightWord:
+			if !t.deleteSelection() {
+				end := nextWordBoundary(t.valueTemp, t.cursorPos)
+				t.valueTemp = append(t.valueTemp[:t.cursorPos], t.valueTemp[end:]...)
+			}
+		case EditActionCutUntilLineEnd:
+			end := t.cursorPos
+			for end < len(t.valueTemp) && t.valueTemp[end] != '\n' {
+				end++
+			}
+			if end == t.cursorPos && end < len(t.valueTemp) {
+				end++
+			}
+			t.yankValue = append([]rune{}, t.valueTemp[t.cursorPos:end]...)
+			t.valueTemp = append(t.valueTemp[:t.cursorPos], t.valueTemp[end:]...)
+		case EditActionYank:
+			t.insert(t.yankValue)
+		case EditActionEnter:
+			t.deleteSelection()
+			t.insert([]rune{'\n'})
+		case EditActionSelectAll:
+			t.cursorPos = len(t.valueTemp)
+			t.selectionPos = 0
+		case EditActionCopy:
+			t.CopySelection()
+		case EditActionCut:
+			t.CopySelection()
+			t.deleteSelection()
+		case EditActionPaste:
+			t.deleteSelection()
+			t.PasteFromClipboard()
+		case EditActionUndo:
+			t.Undo()
+		case EditActionRedo:
+			t.Redo()
+		default:
+			return true
+		}
+		if editAction != EditActionUndo && editAction != EditActionRedo {
+			t.history.record(before, t.valueTemp, t.cursorPos, editKindOther)
+		}
+		if editAction != EditActionMoveUp && editAction != EditActionMoveDown &&
+			editAction != EditActionPageUp && editAction != EditActionPageDown {
+			t.preferredX = -1
+		}
+		t.normalizeSelection()
+		if ctx := t.context(); ctx != nil {
+			t.scrollToCursor(t.layoutLines(ctx))
+		}
+	}
+	return true
+}
+
+func (t *TextArea) KeyboardCharacterEvent(self Widget, codePoint rune) bool {
+	if t.editable && t.Focused() {
+		before := t.editState()
+		t.deleteSelection()
+		t.insert([]rune{codePoint})
+		t.history.record(before, t.valueTemp, t.cursorPos, editKindTyping)
+		t.preeditText = nil
+		t.preferredX = -1
+		if ctx := t.context(); ctx != nil {
+			t.scrollToCursor(t.layoutLines(ctx))
+		}
+		return true
+	}
+	return false
+}
+
+func (t *TextArea) IMEPreeditEvent(self Widget, text []rune, blocks []int, focusedBlock int) bool {
+	if !t.editable || !t.Focused() {
+		return false
+	}
+	before := t.editState()
+	t.deleteSelection()
+	t.history.record(before, t.valueTemp, t.cursorPos, editKindOther)
+	t.preeditText = text
+	t.preeditBlocks = blocks
+	t.preeditFocusedBlock = focusedBlock
+	return true
+}
+
+func (t *TextArea) IMEStatusEvent(self Widget) bool {
+	if len(t.preeditText) != 0 {
+		before := t.editState()
+		t.insert(t.preeditText)
+		t.preeditText = nil
+		t.history.record(before, t.valueTemp, t.cursorPos, editKindOther)
+	}
+	return true
+}
+
+// CopySelection() copies the selected text to the clipboard
+func (t *TextArea) CopySelection() bool {
+	begin, end := t.Selection()
+	if begin == end {
+		return false
+	}
+	if clipboard := findClipboard(t); clipboard != nil {
+		SetClipboardText(clipboard, string(t.valueTemp[begin:end]))
+		return true
+	}
+	return false
+}
+
+// PasteFromClipboard() inserts the clipboard text at the cursor
+func (t *TextArea) PasteFromClipboard() {
+	if clipboard := findClipboard(t); clipboard != nil {
+		str, _ := GetClipboardText(clipboard)
+		t.insert([]rune(str))
+	}
+}
+
+// PreferredSize() returns the size of rows lines. The width is the longest paragraph, or the wrap width when
+// long lines are wrapped: the fixed width, or the current width once the widget has one
+func (t *TextArea) PreferredSize(self Widget, ctx DrawContext) (int, int) {
+	ctx.SetFontSize(float32(t.FontSize()))
+	ctx.SetFontFace(t.Font())
+	padding := t.padding()
+	height := int(t.lineHeight()*float32(t.rows) + padding*2)
+	if t.wrap {
+		if t.FixedWidth() > 0 {
+			return t.FixedWidth(), height
+		} else if t.w > 0 {
+			return t.w, height
+		}
+	}
+	var width float32
+	start := 0
+	text := t.editingText()
+	for i := 0; i <= len(text); i++ {
+		if i == len(text) || text[i] == '\n' {
+			w, _ := ctx.TextBounds(0, 0, string(text[start:i]))
+			width = maxF(width, w)
+			start = i + 1
+		}
+	}
+	return int(width+padding*2) + textAreaScrollBarWidth, height
+}
+
+func (t *TextArea) Draw(self Widget, ctx DrawContext) {
+	t.WidgetImplement.Draw(self, ctx)
+
+	x := float32(t.x)
+	y := float32(t.y)
+	w := float32(t.w)
+	h := float32(t.h)
+
+	bg := nanovgo.BoxGradient(x+1, y+2, w-2, h-2, 3, 4, nanovgo.MONO(255, 32), nanovgo.MONO(32, 32))
+	fg := nanovgo.BoxGradient(x+1, y+2, w-2, h-2, 3, 4, nanovgo.MONO(150, 32), nanovgo.MONO(32, 32))
+
+	ctx.BeginPath()
+	ctx.RoundedRect(x+1, y+2, w-2, h-2, 3)
+	if t.editable && t.Focused() {
+		ctx.SetFillPaint(fg)
+	} else {
+		ctx.SetFillPaint(bg)
+	}
+	ctx.Fill()
+
+	ctx.BeginPath()
+	ctx.RoundedRect(x+0.5, y+0.5, w-1, h-1, 2.5)
+	ctx.SetStrokeColor(nanovgo.MONO(0, 48))
+	ctx.Stroke()
+
+	lines := t.layoutLines(ctx)
+	text := t.editingText()
+	padding := t.padding()
+	lineH := t.lineHeight()
+	viewH := t.viewHeight()
+	contentH := t.contentHeight(lines)
+	t.scrollY = clampF(t.scrollY, 0, maxF(0, contentH-viewH))
+
+	originX := x + padding
+	originY := y + padding - t.scrollY
+
+	ctx.Save()
+	ctx.Scissor(x+padding-1, y+1, w-padding*2-textAreaScrollBarWidth+2, h-2)
+	if t.enabled {
+		ctx.SetFillColor(t.theme.TextColor)
+	} else {
+		ctx.SetFillColor(t.theme.DisabledTextColor)
+	}
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignTop)
+
+	first := clampI(int(t.scrollY/lineH), 0, len(lines)-1)
+	last := clampI(int((t.scrollY+viewH)/lineH), 0, len(lines)-1)
+
+	editing := !t.committed && t.cursorPos > -1
+	begin, end := t.Selection()
+	if editing && begin != end && len(t.preeditText) == 0 {
+		// draw selection
+		ctx.SetFillColor(nanovgo.MONO(255, 80))
+		for i := first; i <= last; i++ {
+			line := &lines[i]
+			if end < line.start || begin > line.end {
+				continue
+			}
+			selBegin := line.caretX(maxI(begin, line.start))
+			selEnd := line.caretX(minI(end, line.end))
+			if end > line.end && !line.softBreak {
+				// the line break is selected
+				selEnd += float32(t.FontSize()) * 0.3
+			}
+			ly := originY + float32(i)*lineH
+			ctx.BeginPath()
+			ctx.Rect(originX+selBegin, ly, selEnd-selBegin, lineH)
+			ctx.Fill()
+		}
+		if t.enabled {
+			ctx.SetFillColor(t.theme.TextColor)
+		} else {
+			ctx.SetFillColor(t.theme.DisabledTextColor)
+		}
+	}
+
+	for i := first; i <= last; i++ {
+		line := &lines[i]
+		if line.end > line.start {
+			ctx.TextRune(originX, originY+float32(i)*lineH, text[line.start:line.end])
+		}
+	}
+
+	if editing {
+		caretIndex := t.cursorPos
+		if len(t.preeditText) != 0 {
+			// draw preedit text
+			ctx.SetStrokeColor(nanovgo.MONO(255, 160))
+			ctx.SetFillColor(nanovgo.MONO(255, 80))
+			ctx.SetStrokeWidth(2.0)
+			offset := t.cursorPos
+			for i, blockLength := range t.preeditBlocks {
+				for index := offset; index < offset+blockLength; {
+					lineIndex := lineOfIndex(lines, index)
+					line := &lines[lineIndex]
+					blockEnd := minI(offset+blockLength, line.end)
+					if blockEnd <= index {
+						blockEnd = index + 1
+					}
+					bx0 := originX + line.caretX(index)
+					bx1 := originX + line.caretX(minI(blockEnd, line.end))
+					ly := originY + float32(lineIndex)*lineH
+					ctx.BeginPath()
+					if i != t.preeditFocusedBlock {
+						ctx.MoveTo(bx0+2, ly+lineH-1)
+						ctx.LineTo(bx1-2, ly+lineH-1)
+						ctx.Stroke()
+					} else {
+						ctx.Rect(bx0, ly, bx1-bx0, lineH)
+						ctx.Fill()
+					}
+					index = blockEnd
+				}
+				offset += blockLength
+			}
+			caretIndex += len(t.preeditText)
+		}
+		lineIndex := lineOfIndex(lines, caretIndex)
+		caretX := originX + lines[lineIndex].caretX(caretIndex)
+		caretY := originY + float32(lineIndex)*lineH
+
+		// draw cursor
+		ctx.BeginPath()
+		ctx.MoveTo(caretX, caretY)
+		ctx.LineTo(caretX, caretY+lineH)
+		ctx.SetStrokeColor(nanovgo.RGBA(255, 192, 0, 255))
+		ctx.SetStrokeWidth(1.0)
+		ctx.Stroke()
+
+		if len(t.preeditText) != 0 {
+			if screen := t.screen(); screen != nil {
+				oldCurX, oldCurY, oldCurH := screen.PreeditCursorPos()
+				absX, absY := t.Parent().AbsolutePosition()
+				newCurX := int(caretX) + absX
+				newCurY := int(caretY+lineH) + absY
+				newCurH := int(lineH)
+				if oldCurX != newCurX || oldCurY != newCurY || oldCurH != newCurH {
+					screen.SetPreeditCursorPos(newCurX, newCurY, newCurH)
+				}
+			}
+		}
+	}
+	ctx.Restore()
+
+	if contentH > viewH {
+		scrollH := h * minF(1.0, viewH/contentH)
+		scrollH = minF(maxF(20.0, scrollH), h-8)
+		scroll := t.scrollY / (contentH - viewH)
+		paint := nanovgo.BoxGradient(x+w-12+1, y+4+1, 8, h-8, 3, 4, nanovgo.MONO(0, 32), nanovgo.MONO(0, 92))
+		ctx.BeginPath()
+		ctx.RoundedRect(x+w-12, y+4, 8, h-8, 3)
+		ctx.SetFillPaint(paint)
+		ctx.Fill()
+
+		barPaint := nanovgo.BoxGradient(x+w-12-1, y+4+1+(h-8-scrollH)*scroll-1, 8, scrollH, 3, 4, nanovgo.MONO(220, 100), nanovgo.MONO(128, 100))
+		ctx.BeginPath()
+		ctx.RoundedRect(x+w-12+1, y+4+1+(h-8-scrollH)*scroll, 8-2, scrollH-2, 2)
+		ctx.SetFillPaint(barPaint)
+		ctx.Fill()
+	}
+}
+
+func (t *TextArea) String() string {
+	return t.StringHelper("TextArea", t.value)
+}
+
+func (t *TextArea) padding() float32 {
+	return float32(t.FontSize()) * 0.3
+}
+
+func (t *TextArea) lineHeight() float32 {
+	return float32(t.FontSize()) * 1.2
+}
+
+func (t *TextArea) viewHeight() float32 {
+	return float32(t.h) - t.padding()*2
+}
+
+func (t *TextArea) contentHeight(lines []textAreaLine) float32 {
+	return float32(len(lines)) * t.lineHeight()
+}
+
+func (t *TextArea) screen() *Screen {
+	return findScreen(t)
+}
+
+// context() returns the draw context of the screen to measure text outside of Draw()
+func (t *TextArea) context() DrawContext {
+	if screen := t.screen(); screen != nil {
+		return screen.Context()
+	}
+	return nil
+}
+
+func (t *TextArea) editingText() []rune {
+	if t.committed {
+		return []rune(t.value)
+	}
+	if len(t.preeditText) == 0 {
+		return t.valueTemp
+	}
+	result := make([]rune, 0, len(t.valueTemp)+len(t.preeditText))
+	result = append(append(append(result, t.valueTemp[:t.cursorPos]...), t.preeditText...), t.valueTemp[t.cursorPos:]...)
+	return result
+}
+
+func (t *TextArea) editState() editState {
+	return newEditState(t.valueTemp, t.cursorPos, t.selectionPos)
+}
+
+func (t *TextArea) restoreEditState(state editState) {
+	t.valueTemp = append([]rune{}, state.text...)
+	t.cursorPos = clampI(state.cursor, 0, len(t.valueTemp))
+	t.selectionPos = toI(state.selection > len(t.valueTemp), -1, state.selection)
+	t.preeditText = nil
+	t.preferredX = -1
+}
+
+func (t *TextArea) insert(runes []rune) {
+	if len(runes) == 0 {
+		return
+	}
+	inserted := make([]rune, 0, len(t.valueTemp)+len(runes))
+	inserted = append(append(append(inserted, t.valueTemp[:t.cursorPos]...), runes...), t.valueTemp[t.cursorPos:]...)
+	t.valueTemp = inserted
+	t.cursorPos += len(runes)
+}
+
+func (t *TextArea) deleteSelection() bool {
+	begin, end := t.Selection()
+	if begin == end {
+		t.selectionPos = -1
+		return false
+	}
+	t.valueTemp = append(t.valueTemp[:begin], t.valueTemp[end:]...)
+	t.cursorPos = begin
+	t.selectionPos = -1
+	return true
+}
+
+// collapseSelection() moves the cursor to an edge of the selection (instead of moving it) when Left/Right is pressed without Shift
+func (t *TextArea) collapseSelection(shift, toEnd bool) bool {
+	if shift || t.selectionPos == -1 {
+		return false
+	}
+	begin, end := t.Selection()
+	if toEnd {
+		t.cursorPos = end
+	} else {
+		t.cursorPos = begin
+	}
+	t.selectionPos = -1
+	return true
+}
+
+func (t *TextArea) moveCursor(index int, extend bool) {
+	if extend {
+		if t.selectionPos == -1 {
+			t.selectionPos = t.cursorPos
+		}
+	} else {
+		t.selectionPos = -1
+	}
+	t.cursorPos = clampI(index, 0, len(t.valueTemp))
+}
+
+func (t *TextArea) normalizeSelection() {
+	if t.cursorPos == t.selectionPos {
+		t.selectionPos = -1
+	}
+}
+
+func (t *TextArea) moveVertically(action EditAction, extend bool) {
+	ctx := t.context()
+	if ctx == nil {
+		return
+	}
+	lines := t.layoutLines(ctx)
+	lineIndex := lineOfIndex(lines, t.cursorPos)
+	line := &lines[lineIndex]
+	if t.preferredX < 0 {
+		t.preferredX = line.caretX(t.cursorPos)
+	}
+	pageRows := maxI(1, int(t.viewHeight()/t.lineHeight())-1)
+	var index int
+	switch action {
+	case EditActionMoveLineTop:
+		index = line.start
+	case EditActionMoveLineEnd:
+		index = line.lastCaretIndex()
+	case EditActionMoveUp, EditActionPageUp:
+		rows := 1
+		if action == EditActionPageUp {
+			rows = pageRows
+			t.scrollY = maxF(0, t.scrollY-float32(rows)*t.lineHeight())
+		}
+		if lineIndex == 0 {
+			index = 0
+		} else {
+			target := &lines[maxI(0, lineIndex-rows)]
+			index = target.indexAtX(t.preferredX)
+		}
+	case EditActionMoveDown, EditActionPageDown:
+		rows := 1
+		if action == EditActionPageDown {
+			rows = pageRows
+			t.scrollY += float32(rows) * t.lineHeight()
+		}
+		if lineIndex == len(lines)-1 {
+			index = len(t.valueTemp)
+		} else {
+			target := &lines[minI(len(lines)-1, lineIndex+rows)]
+			index = target.indexAtX(t.preferredX)
+		}
+	}
+	t.moveCursor(index, extend)
+}
+
+// indexAt() returns the rune index nearest to the given position (in parent coordinates)
+func (t *TextArea) indexAt(ctx DrawContext, lines []textAreaLine, x, y int) int {
+	padding := t.padding()
+	row := int((float32(y-t.y) - padding + t.scrollY) / t.lineHeight())
+	if float32(y-t.y)-padding+t.scrollY < 0 {
+		return 0
+	}
+	if row >= len(lines) {
+		return len(t.valueTemp)
+	}
+	return lines[row].indexAtX(float32(x-t.x) - padding)
+}
+
+func (t *TextArea) scrollToCursor(lines []textAreaLine) {
+	if t.cursorPos < 0 {
+		return
+	}
+	lineH := t.lineHeight()
+	top := float32(lineOfIndex(lines, t.cursorPos)) * lineH
+	if top < t.scrollY {
+		t.scrollY = top
+	} else if top+lineH > t.scrollY+t.viewHeight() {
+		t.scrollY = top + lineH - t.viewHeight()
+	}
+	t.scrollY = clampF(t.scrollY, 0, maxF(0, t.contentHeight(lines)-t.viewHeight()))
+}
+
+func (t *TextArea) scrollToBar(y int, lines []textAreaLine) {
+	h := float32(t.h)
+	viewH := t.viewHeight()
+	contentH := t.contentHeight(lines)
+	scrollH := minF(maxF(20.0, h*minF(1.0, viewH/contentH)), h-8)
+	ratio := clampF((float32(y-t.y)-4-scrollH*0.5)/(h-8-scrollH), 0, 1)
+	t.scrollY = ratio * (contentH - viewH)
+}
+
+// layoutLines() splits the editing text into visual lines
+func (t *TextArea) layoutLines(ctx DrawContext) []textAreaLine {
+	ctx.SetFontSize(float32(t.FontSize()))
+	ctx.SetFontFace(t.Font())
+	ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignTop)
+
+	text := t.editingText()
+	width := float32(t.w) - t.padding()*2 - textAreaScrollBarWidth
+	var lines []textAreaLine
+	start := 0
+	for {
+		end := start
+		for end < len(text) && text[end] != '\n' {
+			end++
+		}
+		lines = t.wrapParagraph(ctx, lines, text, start, end, width)
+		if end == len(text) {
+			break
+		}
+		start = end + 1
+	}
+	return lines
+}
+
+func (t *TextArea) wrapParagraph(ctx DrawContext, lines []textAreaLine, text []rune, start, end int, width float32) []textAreaLine {
+	paragraph := text[start:end]
+	glyphs := ctx.TextGlyphPositionsRune(0, 0, paragraph)
+	advance, _ := ctx.TextBounds(0, 0, string(paragraph))
+	position := func(i int) float32 {
+		if i >= len(glyphs) {
+			return advance
+		}
+		return glyphs[i].X
+	}
+	// lines are broken only between grapheme clusters
+	atBoundary := make([]bool, len(paragraph)+1)
+	for _, boundary := range graphemeBoundaries(paragraph) {
+		atBoundary[boundary] = true
+	}
+	lineStart := 0
+	for {
+		lineEnd := len(paragraph)
+		if t.wrap && width > 0 {
+			lastBreak := -1
+			for i := lineStart; i < len(paragraph); i++ {
+				if i > lineStart && position(i+1)-position(lineStart) > width && !unicode.IsSpace(paragraph[i]) {
+					if lastBreak > lineStart {
+						lineEnd = lastBreak
+					} else {
+						lineEnd = i
+						for lineEnd > lineStart && !atBoundary[lineEnd] {
+							lineEnd--
+						}
+						if lineEnd == lineStart {
+							lineEnd = nextGraphemeBoundary(paragraph, lineStart)
+						}
+					}
+					break
+				}
+				if unicode.IsSpace(paragraph[i]) && atBoundary[i+1] {
+					lastBreak = i + 1
+				} else if isWideRune(paragraph[i]) && i > lineStart && atBoundary[i] {
+					lastBreak = i
+				}
+			}
+		}
+		line := textAreaLine{
+			start:     start + lineStart,
+			end:       start + lineEnd,
+			softBreak: lineEnd < len(paragraph),
+		}
+		offset := position(lineStart)
+		for i := lineStart; i <= lineEnd; i++ {
+			line.x = append(line.x, position(i)-offset)
+		}
+		lines = append(lines, line)
+		if lineEnd >= len(paragraph) {
+			break
+		}
+		lineStart = lineEnd
+	}
+	return lines
+}
+
+// caretX() returns the caret position of the rune index relative to the line start
+func (l *textAreaLine) caretX(index int) float32 {
+	return l.x[clampI(index-l.start, 0, len(l.x)-1)]
+}
+
+// lastCaretIndex() returns the last cursor position in the line (before the wrapped next line starts)
+func (l *textAreaLine) lastCaretIndex() int {
+	if l.softBreak && l.end > l.start {
+		return l.end - 1
+	}
+	return l.end
+}
+
+// indexAtX() returns the rune index in the line nearest to the x position
+func (l *textAreaLine) indexAtX(x float32) int {
+	last := l.lastCaretIndex()
+	index := l.start
+	for i := l.start + 1; i <= last; i++ {
+		if absF(l.caretX(i)-x) < absF(l.caretX(index)-x) {
+			index = i
+		}
+	}
+	return index
+}
+
+// lineOfIndex() returns the visual line which displays the cursor at the rune index
+func lineOfIndex(lines []textAreaLine, index int) int {
+	result := 0
+	for i := range lines {
+		if lines[i].start <= index {
+			result = i
+		} else {
+			break
+		}
+	}
+	return result
+}
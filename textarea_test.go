@@ -0,0 +1,89 @@
+package nanogui
+
+import (
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+func TestTextAreaWrapAndEdit(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textArea := NewTextArea(window, "hello world foo bar\nsecond line")
+	textArea.SetEditable(true)
+	textArea.SetFixedSize(100, 80)
+	screen.PerformLayout()
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	if !textArea.Focused() {
+		t.Fatal("Tab should focus the text area")
+	}
+	lines := textArea.layoutLines(screen.Context())
+	if len(lines) <= 2 || !lines[0].softBreak {
+		t.Fatalf("a long paragraph should be soft wrapped: %d lines", len(lines))
+	}
+	in.PressKey(glfw.KeyDown, 0)
+	if textArea.cursorPos != lines[1].start {
+		t.Fatalf("Down should move to the next visual line: cursor %d, want %d", textArea.cursorPos, lines[1].start)
+	}
+
+	for i := 0; i < 5; i++ {
+		in.PressKey(glfw.KeyPageDown, 0)
+	}
+	if textArea.cursorPos != len(textArea.valueTemp) {
+		t.Fatalf("PageDown should reach the end: cursor %d, want %d", textArea.cursorPos, len(textArea.valueTemp))
+	}
+	in.PressKey(glfw.KeyEnter, 0)
+	in.TypeText("X")
+	in.PressKey(glfw.KeyUp, glfw.ModShift)
+	if begin, end := textArea.Selection(); begin == end {
+		t.Error("Shift+Up should select text")
+	}
+	in.PressKey(glfw.KeyTab, 0)
+	if want := "hello world foo bar\nsecond line\nX"; textArea.Value() != want {
+		t.Errorf("Value() = %q, want %q", textArea.Value(), want)
+	}
+}
+
+func TestTextAreaGraphemeEditing(t *testing.T) {
+	// "a", a regional indicator pair and "e" with a combining acute accent
+	screen, window := newTestWindow(t)
+	textArea := NewTextArea(window, "a\U0001F1EF\U0001F1F5e\u0301")
+	textArea.SetEditable(true)
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	textArea.cursorPos = len(textArea.valueTemp)
+	textArea.selectionPos = -1
+
+	in.PressKey(glfw.KeyLeft, 0)
+	if textArea.cursorPos != 3 {
+		t.Fatalf("Left should skip the combining mark: cursor %d, want 3", textArea.cursorPos)
+	}
+	in.PressKey(glfw.KeyBackspace, 0)
+	if string(textArea.valueTemp) != "ae\u0301" || textArea.cursorPos != 1 {
+		t.Fatalf("Backspace should delete the whole flag: %q, cursor %d", string(textArea.valueTemp), textArea.cursorPos)
+	}
+	in.PressKey(glfw.KeyDelete, 0)
+	if string(textArea.valueTemp) != "a" {
+		t.Fatalf("Delete should delete the whole cluster: %q", string(textArea.valueTemp))
+	}
+}
+
+func TestTextAreaPreferredWidth(t *testing.T) {
+	screen, window := newTestWindow(t)
+	fixed := NewTextArea(window, "short")
+	fixed.SetFixedSize(200, 80)
+	ctx := screen.Context()
+	if w, _ := fixed.PreferredSize(fixed, ctx); w != 200 {
+		t.Errorf("wrapped text area with a fixed width: preferred width %d, want 200", w)
+	}
+	sized := NewTextArea(window, "a very long paragraph which is wrapped")
+	sized.SetSize(120, 50)
+	if w, _ := sized.PreferredSize(sized, ctx); w != 120 {
+		t.Errorf("wrapped text area with a size: preferred width %d, want 120", w)
+	}
+	unwrapped := NewTextArea(window, "a very long paragraph which is not wrapped at all")
+	unwrapped.SetWrap(false)
+	if w, _ := unwrapped.PreferredSize(unwrapped, ctx); w <= 200 {
+		t.Errorf("unwrapped text area should fit the longest line: preferred width %d", w)
+	}
+}
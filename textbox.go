@@ -1,10 +1,10 @@
 package nanogui
 
 import (
+	"errors"
 	"github.com/shibukawa/glfw"
 	"github.com/shibukawa/nanovgo"
 	"regexp"
-	"strconv"
 )
 
 type TextAlignment int
@@ -15,6 +15,36 @@ const (
 	TextRight
 )
 
+// ValidationErrorDisplay specifies how a TextBox shows its validation error
+type ValidationErrorDisplay int
+
+const (
+	// ValidationErrorTooltip shows the message in a bubble under the focused text box
+	ValidationErrorTooltip ValidationErrorDisplay = iota
+	// ValidationErrorInline shows the message at the right end of the text box instead of the units
+	ValidationErrorInline
+)
+
+type spinArea int
+
+const (
+	spinAreaNone spinArea = iota
+	spinAreaTop
+	spinAreaBottom
+)
+
+// spinArrowsWidth is the width of the up/down arrows at the left end of a spinnable text box
+const spinArrowsWidth = 14
+
+// ErrInvalidFormat is the validation error of text which doesn't match the format of a TextBox
+var ErrInvalidFormat = errors.New("invalid format")
+
+// DefaultPasswordMask is the glyph drawn for each character of a password text box
+const DefaultPasswordMask = '•'
+
+// passwordRevealIcon is the "eye" glyph of the entypo font
+const passwordRevealIcon Icon = 0xe70a
+
 type TextBox struct {
 	WidgetImplement
 
@@ -39,9 +69,32 @@ type TextBox struct {
 	mouseDownModifier   glfw.ModifierKey
 	textOffset          float32
 	lastClick           float32
+	clickCount          int
 	preeditText         []rune
 	preeditBlocks       []int
 	preeditFocusedBlock int
+	history             editHistory
+	password            bool
+	passwordMask        rune
+	revealButton        bool
+	revealed            bool
+	placeholder         string
+	validators          []func(string) error
+	rangeValidator      func(string) error
+	validationError     error
+	errorDisplay        ValidationErrorDisplay
+	spinnable           bool
+	scrubPending        bool
+	scrubbing           bool
+	scrubX              int
+	completer           func(string) []Suggestion
+	asyncCompleter      func(string) <-chan []Suggestion
+	pendingSuggestions  <-chan []Suggestion
+	suggestionPopup     *Popup
+	suggestionList      *suggestionList
+	mask                *textMask
+	maskValueMode       MaskValueMode
+	direction           TextDirection
 }
 
 func NewTextBox(parent Widget, values ...string) *TextBox {
@@ -73,6 +126,9 @@ func (t *TextBox) init(value string) {
 	t.mouseDownPos = [2]int{-1, -1}
 	t.mouseDragPos = [2]int{-1, -1}
 	t.fontSize = t.theme.TextBoxFontSize
+	t.tabStop = true
+	t.history.limit = DefaultEditHistoryLimit
+	t.passwordMask = DefaultPasswordMask
 }
 
 func (t *TextBox) Editable() bool {
@@ -83,12 +139,25 @@ func (t *TextBox) SetEditable(e bool) {
 	t.editable = e
 }
 
+// TabStop() returns whether or not the text box can receive the focus by Tab / Shift+Tab. Read only text boxes are skipped
+func (t *TextBox) TabStop() bool {
+	return t.editable && t.WidgetImplement.TabStop()
+}
+
+// Value() returns the committed value. A masked text box returns the formatted or the raw value by MaskValueMode()
 func (t *TextBox) Value() string {
-	return t.value
+	return t.maskedValue(t.value)
 }
 
+// SetValue() sets the value. A masked text box accepts both the formatted and the raw value
 func (t *TextBox) SetValue(value string) {
-	t.value = value
+	if t.mask != nil {
+		value = t.mask.format(t.mask.fill(value))
+	}
+	if t.value != value {
+		t.value = value
+		t.InvalidateLayout()
+	}
 }
 
 func (t *TextBox) DefaultValue() string {
@@ -112,7 +181,10 @@ func (t *TextBox) Units() string {
 }
 
 func (t *TextBox) SetUnits(units string) {
-	t.units = units
+	if t.units != units {
+		t.units = units
+		t.InvalidateLayout()
+	}
 }
 
 func (t *TextBox) UnitImage() int {
@@ -120,7 +192,10 @@ func (t *TextBox) UnitImage() int {
 }
 
 func (t *TextBox) SetUnitImage(img int) {
-	t.unitImage = img
+	if t.unitImage != img {
+		t.unitImage = img
+		t.InvalidateLayout()
+	}
 }
 
 func (t *TextBox) Font() string {
@@ -131,10 +206,91 @@ func (t *TextBox) Font() string {
 }
 
 func (t *TextBox) SetFont(fontFace string) {
-	t.fontFace = fontFace
+	if t.fontFace != fontFace {
+		t.fontFace = fontFace
+		t.InvalidateLayout()
+	}
+}
+
+// TextDirection() returns the paragraph direction of the text
+func (t *TextBox) TextDirection() TextDirection {
+	return t.direction
+}
+
+// SetTextDirection() sets the paragraph direction (default: TextDirectionAuto). Mixed-direction text is
+// displayed in visual order, and Left/Right move the cursor visually
+func (t *TextBox) SetTextDirection(direction TextDirection) {
+	t.direction = direction
+}
+
+// Password() returns whether or not the text box hides its value
+func (t *TextBox) Password() bool {
+	return t.password
+}
+
+// SetPassword() switches the secret entry mode. The value is drawn as mask glyphs,
+// copy and cut are disabled and IME composition isn't displayed
+func (t *TextBox) SetPassword(password bool) {
+	t.password = password
+	t.revealed = false
+	t.InvalidateLayout()
+}
+
+// PasswordMask() returns the glyph drawn for each character in password mode
+func (t *TextBox) PasswordMask() rune {
+	return t.passwordMask
+}
+
+// SetPasswordMask() sets the glyph drawn for each character in password mode (default: DefaultPasswordMask)
+func (t *TextBox) SetPasswordMask(mask rune) {
+	if t.passwordMask != mask {
+		t.passwordMask = mask
+		t.InvalidateLayout()
+	}
+}
+
+// PasswordRevealButton() returns whether or not an eye icon to reveal the password is shown
+func (t *TextBox) PasswordRevealButton() bool {
+	return t.revealButton
+}
+
+// SetPasswordRevealButton() shows an eye icon at the right end in password mode. Clicking it toggles Revealed()
+func (t *TextBox) SetPasswordRevealButton(show bool) {
+	if t.revealButton != show {
+		t.revealButton = show
+		t.InvalidateLayout()
+	}
+}
+
+// Revealed() returns whether or not the password is temporarily shown as plain text
+func (t *TextBox) Revealed() bool {
+	return t.revealed
+}
+
+// SetRevealed() shows or hides the password. It is hidden again when the text box loses the focus
+func (t *TextBox) SetRevealed(revealed bool) {
+	if t.revealed != revealed {
+		t.revealed = revealed
+		t.InvalidateLayout()
+	}
+}
+
+// Spinnable() returns whether or not up/down arrows are shown. IntBox and FloatBox step their value by them
+func (t *TextBox) Spinnable() bool {
+	return t.spinnable
+}
+
+func (t *TextBox) SetSpinnable(spinnable bool) {
+	if t.spinnable != spinnable {
+		t.spinnable = spinnable
+		t.InvalidateLayout()
+	}
 }
 
 func (t *TextBox) Format() string {
+	if t.format == nil {
+		return ""
+	}
 	return t.format.String()
 }
 
@@ -144,27 +300,122 @@ func (t *TextBox) SetFormat(format string) error {
 	return err
 }
 
+// SetCallback() sets the callback called when the value is committed. Returning false rejects the new value.
+// It isn't called while the value is invalid
 func (t *TextBox) SetCallback(callback func(string) bool) {
 	t.callback = callback
 }
 
+// Placeholder() returns the hint text drawn when the value is empty
+func (t *TextBox) Placeholder() string {
+	return t.placeholder
+}
+
+// SetPlaceholder() sets the hint text drawn when the value is empty
+func (t *TextBox) SetPlaceholder(placeholder string) {
+	t.placeholder = placeholder
+}
+
+// AddValidator() adds a validator which runs on each edit and on commit
+//
+// A validator receives the value which would be committed (the default value if
+// the text is empty) and returns an error with a message for the user if it is invalid.
+// The value can't be committed while any validator fails.
+func (t *TextBox) AddValidator(validator func(string) error) {
+	t.validators = append(t.validators, validator)
+	t.updateValidation()
+}
+
+// ClearValidators() removes all validators added by AddValidator()
+func (t *TextBox) ClearValidators() {
+	t.validators = nil
+	t.updateValidation()
+}
+
+// ValidationError() returns the error of the text being edited, or nil if it is valid
+func (t *TextBox) ValidationError() error {
+	return t.validationError
+}
+
+// ValidationErrorDisplay() returns how the validation error is shown
+func (t *TextBox) ValidationErrorDisplay() ValidationErrorDisplay {
+	return t.errorDisplay
+}
+
+// SetValidationErrorDisplay() sets how the validation error is shown (default: ValidationErrorTooltip)
+func (t *TextBox) SetValidationErrorDisplay(display ValidationErrorDisplay) {
+	t.errorDisplay = display
+}
+
+// Undo() reverts the last edit. It returns false if there is nothing to undo
+func (t *TextBox) Undo() bool {
+	state, ok := t.history.undoStep(t.editState())
+	if ok {
+		t.restoreEditState(state)
+	}
+	return ok
+}
+
+// Redo() reapplies the last undone edit. It returns false if there is nothing to redo
+func (t *TextBox) Redo() bool {
+	state, ok := t.history.redoStep(t.editState())
+	if ok {
+		t.restoreEditState(state)
+	}
+	return ok
+}
+
+func (t *TextBox) CanUndo() bool {
+	return t.history.canUndo()
+}
+
+func (t *TextBox) CanRedo() bool {
+	return t.history.canRedo()
+}
+
+// ClearHistory() discards all undo and redo steps. The history is also cleared when the focus changes
+func (t *TextBox) ClearHistory() {
+	t.history.clear()
+}
+
+// HistoryLimit() returns the maximum number of undo steps. 0 means unlimited
+func (t *TextBox) HistoryLimit() int {
+	return t.history.limit
+}
+
+// SetHistoryLimit() sets the maximum number of undo steps (default: DefaultEditHistoryLimit). 0 means unlimited
+func (t *TextBox) SetHistoryLimit(limit int) {
+	t.history.setLimit(limit)
+}
+
 func (t *TextBox) MouseButtonEvent(self Widget, x, y int, button glfw.MouseButton, down bool, modifier glfw.ModifierKey) bool {
 	t.WidgetImplement.MouseButtonEvent(self, x, y, button, down, modifier)
 
+	if t.hasRevealButton() && button == glfw.MouseButton1 && float32(x) >= t.revealButtonX() {
+		if down {
+			t.revealed = !t.revealed
+		}
+		return true
+	}
 	if t.editable && t.Focused() && button == glfw.MouseButton1 && len(t.preeditText) == 0 {
 		if down {
 			t.mouseDownPos = [2]int{x, y}
 			t.mouseDownModifier = modifier
 			time := GetTime()
 			if time-t.lastClick < 0.25 {
-				/* Double-click: select all text */
+				t.clickCount++
+			} else {
+				t.clickCount = 1
+			}
+			if t.clickCount >= 3 {
+				/* Triple-click: select all text */
 				t.selectionPos = 0
 				t.cursorPos = len(t.valueTemp)
-				t.mouseDownPos = [2]int{-1, 1}
+				t.mouseDownPos = [2]int{-1, -1}
 			}
 			t.lastClick = time
 		} else {
-			t.mouseDownPos = [2]int{-1, -1}
+			// a pending click is resolved by the next Draw()
 			t.mouseDragPos = [2]int{-1, -1}
 		}
 		return true
@@ -198,30 +449,40 @@ func (t *TextBox) FocusEvent(self Widget, focused bool) bool {
 	backup := t.value
 
 	if t.editable {
+		t.history.clear()
 		if focused {
 			t.valueTemp = []rune(t.value)
 			t.committed = false
 			t.cursorPos = 0
+			if t.mask != nil {
+				t.valueTemp = t.mask.fill(t.value)
+				t.cursorPos = t.mask.firstSlot()
+			}
 		} else {
-			if t.validFormat {
-				if len(t.valueTemp) == 0 {
-					t.value = t.defaultValue
-				} else {
-					t.value = string(t.valueTemp)
+			t.HideSuggestions()
+			t.updateValidation()
+			if t.validationError == nil {
+				t.value = t.editedValue()
+				if t.callback != nil && !t.callback(t.Value()) {
+					t.value = backup
 				}
 			}
 
-			if t.callback != nil && !t.callback(t.value) {
-				t.value = backup
-			}
-
 			t.validFormat = true
+			t.validationError = nil
 			t.committed = true
 			t.cursorPos = -1
 			t.selectionPos = -1
 			t.textOffset = 0
+			t.revealed = false
+			// the edited text updates the layout when it is committed, not on every key stroke
+			if t.value != backup {
+				t.InvalidateLayout()
+			}
+		}
+		if focused {
+			t.updateValidation()
 		}
-		t.validFormat = len(t.valueTemp) == 0 || t.checkFormat(string(t.valueTemp))
 	}
 	return true
 }
@@ -229,25 +490,29 @@ func (t *TextBox) FocusEvent(self Widget, focused bool) bool {
 func (t *TextBox) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action glfw.Action, modifier glfw.ModifierKey) bool {
 	if t.editable && t.Focused() {
 		if (action == glfw.Press || action == glfw.Repeat) && len(t.preeditText) == 0 {
-			switch DetectEditAction(key, modifier) {
+			editAction := DetectEditAction(key, modifier)
+			if t.suggestionKeyboardEvent(key, editAction) {
+				return true
+			}
+			before := t.editState()
+			if t.mask != nil {
+				editAction = t.maskEditAction(editAction)
+			}
+			switch editAction {
 			case EditActionMoveLeft:
 				if modifier == glfw.ModShift {
 					t.selectionPos = toI(t.selectionPos == -1, t.cursorPos, t.selectionPos)
 				} else {
 					t.selectionPos = -1
 				}
-				if t.cursorPos > 0 {
-					t.cursorPos--
-				}
+				t.cursorPos = t.moveCursorVisually(-1)
 			case EditActionMoveRight:
 				if modifier == glfw.ModShift {
 					t.selectionPos = toI(t.selectionPos == -1, t.cursorPos, t.selectionPos)
 				} else {
 					t.selectionPos = -1
 				}
-				if t.cursorPos < len(t.valueTemp) {
-					t.cursorPos++
-				}
+				t.cursorPos = t.moveCursorVisually(1)
 			case EditActionMoveLineTop:
 				if modifier == glfw.ModShift {
 					t.selectionPos = toI(t.selectionPos == -1, t.cursorPos, t.selectionPos)
@@ -262,17 +527,33 @@ func (t *TextBox) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action
 					t.selectionPos = -1
 				}
 				t.cursorPos = len(t.valueTemp)
+			case EditActionMoveLeftWord:
+				if modifier&glfw.ModShift != 0 {
+					t.selectionPos = toI(t.selectionPos == -1, t.cursorPos, t.selectionPos)
+				} else {
+					t.selectionPos = -1
+				}
+				t.cursorPos = t.previousWordBoundary(t.cursorPos)
+			case EditActionMoveRightWord:
+				if modifier&glfw.ModShift != 0 {
+					t.selectionPos = toI(t.selectionPos == -1, t.cursorPos, t.selectionPos)
+				} else {
+					t.selectionPos = -1
+				}
+				t.cursorPos = t.nextWordBoundary(t.cursorPos)
 			case EditActionBackspace:
 				if !t.DeleteSelection() {
 					if t.cursorPos > 0 {
-						t.valueTemp = append(t.valueTemp[:t.cursorPos-1], t.valueTemp[t.cursorPos:]...)
-						t.cursorPos--
+						begin := t.previousCharBoundary(t.cursorPos)
+						t.valueTemp = append(t.valueTemp[:begin], t.valueTemp[t.cursorPos:]...)
+						t.cursorPos = begin
 					}
 				}
 			case EditActionDelete:
 				if !t.DeleteSelection() {
 					if t.cursorPos < len(t.valueTemp) {
-						t.valueTemp = append(t.valueTemp[:t.cursorPos], t.valueTemp[t.cursorPos+1:]...)
+						end := t.nextCharBoundary(t.cursorPos)
+						t.valueTemp = append(t.valueTemp[:t.cursorPos], t.valueTemp[end:]...)
 					}
 				}
 			case EditActionCutUntilLineEnd:
@@ -281,10 +562,19 @@ func (t *TextBox) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action
 			case EditActionYank:
 				t.valueTemp = append(t.valueTemp[:t.cursorPos], append(t.yankValue, t.valueTemp[t.cursorPos:]...)...)
 			case EditActionDeleteLeftWord:
-				panic("not implemented")
+				if !t.DeleteSelection() {
+					begin := t.previousWordBoundary(t.cursorPos)
+					t.valueTemp = append(t.valueTemp[:begin], t.valueTemp[t.cursorPos:]...)
+					t.cursorPos = begin
+				}
+			case EditActionDeleteRightWord:
+				if !t.DeleteSelection() {
+					end := t.nextWordBoundary(t.cursorPos)
+					t.valueTemp = append(t.valueTemp[:t.cursorPos], t.valueTemp[end:]...)
+				}
 			case EditActionEnter:
-				if !t.committed {
-					t.FocusEvent(t, false)
+				if !t.committed && t.validationError == nil {
+					self.FocusEvent(self, false)
 				}
 			case EditActionSelectAll:
 				t.cursorPos = len(t.valueTemp)
@@ -292,13 +582,27 @@ func (t *TextBox) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action
 			case EditActionCopy:
 				t.CopySelection()
 			case EditActionCut:
-				t.CopySelection()
-				t.DeleteSelection()
+				if t.CopySelection() {
+					t.DeleteSelection()
+				}
 			case EditActionPaste:
 				t.DeleteSelection()
 				t.PasteFromClipboard()
+			case EditActionUndo:
+				t.Undo()
+			case EditActionRedo:
+				t.Redo()
+			}
+			if t.mask != nil && !t.committed && t.cursorPos != before.cursor {
+				t.snapMaskCursor(before.cursor)
+			}
+			if editAction != EditActionUndo && editAction != EditActionRedo && !t.committed {
+				t.history.record(before, t.valueTemp, t.cursorPos, editKindOther)
+			}
+			if !t.committed {
+				t.updateValidation()
+				t.updateSuggestions(before)
 			}
-			t.validFormat = len(t.valueTemp) == 0 || t.checkFormat(string(t.valueTemp))
 		}
 		return true
 	}
@@ -307,11 +611,18 @@ func (t *TextBox) KeyboardEvent(self Widget, key glfw.Key, scanCode int, action
 
 func (t *TextBox) KeyboardCharacterEvent(self Widget, codePoint rune) bool {
 	if t.editable && t.Focused() {
+		before := t.editState()
 		t.DeleteSelection()
-		t.valueTemp = append(t.valueTemp[:t.cursorPos], append([]rune{codePoint}, t.valueTemp[t.cursorPos:]...)...)
-		t.cursorPos++
-		t.validFormat = len(t.valueTemp) == 0 || t.checkFormat(string(t.valueTemp))
+		if t.mask != nil {
+			t.cursorPos = t.mask.insert(t.valueTemp, t.cursorPos, []rune{codePoint})
+		} else {
+			t.valueTemp = append(t.valueTemp[:t.cursorPos], append([]rune{codePoint}, t.valueTemp[t.cursorPos:]...)...)
+			t.cursorPos++
+		}
+		t.history.record(before, t.valueTemp, t.cursorPos, editKindTyping)
+		t.updateValidation()
 		t.preeditText = nil
+		t.requestSuggestions(false)
 		return true
 	}
 	return false
@@ -321,19 +632,31 @@ func (t *TextBox) IMEPreeditEvent(self Widget, text []rune, blocks []int, focuse
 	t.preeditText = text
 	t.preeditBlocks = blocks
 	t.preeditFocusedBlock = focusedBlock
+	if len(text) != 0 {
+		// the list comes back with the composed text
+		t.HideSuggestions()
+	}
 	return true
 }
 
 func (t *TextBox) IMEStatusEvent(self Widget) bool {
 	if len(t.preeditText) != 0 {
-		t.valueTemp = append(append(t.valueTemp[:t.cursorPos], t.preeditText...), t.valueTemp[t.cursorPos:]...)
-		t.cursorPos += len(t.preeditText)
+		before := t.editState()
+		if t.mask != nil {
+			t.cursorPos = t.mask.insert(t.valueTemp, t.cursorPos, t.preeditText)
+		} else {
+			t.valueTemp = append(append(t.valueTemp[:t.cursorPos], t.preeditText...), t.valueTemp[t.cursorPos:]...)
+			t.cursorPos += len(t.preeditText)
+		}
 		t.preeditText = nil
+		t.history.record(before, t.valueTemp, t.cursorPos, editKindOther)
+		t.updateValidation()
+		t.requestSuggestions(false)
 	}
 	return true
 }
 
-func (t *TextBox) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+func (t *TextBox) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	sizeH := float32(t.FontSize()) * 1.4
 
 	var unitWidth, textWidth float32
@@ -345,15 +668,26 @@ func (t *TextBox) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
 	} else if t.units != "" {
 		unitWidth, _ = ctx.TextBounds(0, 0, t.units)
 	}
+	if t.hasRevealButton() {
+		unitWidth += sizeH * 0.7
+	}
+	if t.spinnable {
+		unitWidth += spinArrowsWidth
+	}
 
-	textWidth, _ = ctx.TextBounds(0, 0, string(t.editingText()))
+	textWidth, _ = ctx.TextBounds(0, 0, string(t.displayText(t.editingText())))
 	sizeW := sizeH + textWidth + unitWidth
 	return int(sizeW), int(sizeH)
 }
 
-func (t *TextBox) Draw(self Widget, ctx *nanovgo.Context) {
+func (t *TextBox) Draw(self Widget, ctx DrawContext) {
 	t.WidgetImplement.Draw(self, ctx)
 
+	t.pollSuggestions()
+	if t.SuggestionsVisible() {
+		t.placeSuggestions()
+	}
+
 	x := float32(t.x)
 	y := float32(t.y)
 	w := float32(t.w)
@@ -366,7 +700,7 @@ func (t *TextBox) Draw(self Widget, ctx *nanovgo.Context) {
 	ctx.BeginPath()
 	ctx.RoundedRect(x+1, y+2, w-2, h-2, 3)
 	if t.editable && t.Focused() {
-		if t.validFormat {
+		if t.validationError == nil {
 			ctx.SetFillPaint(fg1)
 		} else {
 			ctx.SetFillPaint(fg2)
@@ -388,16 +722,32 @@ func (t *TextBox) Draw(self Widget, ctx *nanovgo.Context) {
 	drawPosY := y + h*0.5 + 1
 
 	xSpacing := h * 0.3
-	var unitWidth float32
+	var unitWidth, revealWidth float32
 
-	if t.unitImage > 0 {
+	if t.hasRevealButton() {
+		revealWidth = h * 0.7
+		ctx.SetFontSize(revealWidth)
+		ctx.SetFontFace(t.theme.FontIcons)
+		ctx.SetFillColor(nanovgo.MONO(255, toB(t.revealed, 160, 64)))
+		ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignMiddle)
+		ctx.Text(x+w-xSpacing-revealWidth*0.5, drawPosY, string([]rune{rune(passwordRevealIcon)}))
+		ctx.SetFontSize(float32(t.FontSize()))
+		ctx.SetFontFace(t.Font())
+	}
+
+	if message := t.inlineValidationMessage(); message != "" {
+		unitWidth, _ = ctx.TextBounds(0, 0, message)
+		ctx.SetFillColor(t.theme.ErrorColor)
+		ctx.SetTextAlign(nanovgo.AlignRight | nanovgo.AlignMiddle)
+		ctx.Text(x+w-xSpacing-revealWidth, drawPosY, message)
+	} else if t.unitImage > 0 {
 		iw, ih, _ := ctx.ImageSize(t.unitImage)
 		unitHeight := float32(ih) * 0.4
 		unitWidth = float32(iw) * unitHeight / float32(h)
-		imgPaint := nanovgo.ImagePattern(x+w-xSpacing-unitWidth, drawPosY-unitHeight*0.5,
+		imgPaint := nanovgo.ImagePattern(x+w-xSpacing-revealWidth-unitWidth, drawPosY-unitHeight*0.5,
 			unitWidth, unitHeight, 0, t.unitImage, toF(t.enabled, 0.7, 0.35))
 		ctx.BeginPath()
-		ctx.Rect(x+w-xSpacing-unitWidth, drawPosY-unitHeight*0.5, unitWidth, unitHeight)
+		ctx.Rect(x+w-xSpacing-revealWidth-unitWidth, drawPosY-unitHeight*0.5, unitWidth, unitHeight)
 		ctx.SetFillPaint(imgPaint)
 		ctx.Fill()
 		unitWidth += 2
@@ -405,19 +755,37 @@ func (t *TextBox) Draw(self Widget, ctx *nanovgo.Context) {
 		unitWidth, _ = ctx.TextBounds(0, 0, t.units)
 		ctx.SetFillColor(nanovgo.MONO(255, toB(t.enabled, 64, 32)))
 		ctx.SetTextAlign(nanovgo.AlignRight | nanovgo.AlignMiddle)
-		ctx.Text(x+w-xSpacing, drawPosY, t.units)
+		ctx.Text(x+w-xSpacing-revealWidth, drawPosY, t.units)
+	}
+	unitWidth += revealWidth
+
+	var spinWidth float32
+	if t.spinnable {
+		spinWidth = spinArrowsWidth
+		if !t.Focused() {
+			ctx.SetFontFace(t.theme.FontIcons)
+			if t.enabled {
+				ctx.SetFillColor(t.theme.TextColor)
+			} else {
+				ctx.SetFillColor(t.theme.DisabledTextColor)
+			}
+			ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
+			ctx.Text(x+4, y+h*0.5-xSpacing*0.5, string([]rune{rune(IconUpOpen)}))
+			ctx.Text(x+4, y+h*0.5+xSpacing*0.5+1.5, string([]rune{rune(IconDownOpen)}))
+			ctx.SetFontFace(t.Font())
+		}
 	}
 
 	switch t.alignment {
 	case TextLeft:
 		ctx.SetTextAlign(nanovgo.AlignLeft | nanovgo.AlignMiddle)
-		drawPosX += xSpacing
+		drawPosX += xSpacing + spinWidth
 	case TextRight:
 		ctx.SetTextAlign(nanovgo.AlignRight | nanovgo.AlignMiddle)
 		drawPosX += w - unitWidth - xSpacing
 	case TextCenter:
 		ctx.SetTextAlign(nanovgo.AlignCenter | nanovgo.AlignMiddle)
-		drawPosX += w * 0.5
+		drawPosX += (w + spinWidth) * 0.5
 	}
 	if t.enabled {
 		ctx.SetFillColor(t.theme.TextColor)
@@ -425,30 +793,35 @@ func (t *TextBox) Draw(self Widget, ctx *nanovgo.Context) {
 		ctx.SetFillColor(t.theme.DisabledTextColor)
 	}
 	// clip visible text area
-	clipX := x + xSpacing - 1
+	clipX := x + xSpacing + spinWidth - 1
 	clipY := y + 1.0
-	clipWidth := w - unitWidth - 2.0*xSpacing + 2.0
+	clipWidth := w - unitWidth - spinWidth - 2.0*xSpacing + 2.0
 	clipHeight := h - 3.0
 	ctx.Scissor(clipX, clipY, clipWidth, clipHeight)
 	oldDrawPosX := drawPosX
 	drawPosX += t.textOffset
 
 	if t.committed {
-		ctx.Text(drawPosX, drawPosY, t.value)
+		if t.value == "" && t.placeholder != "" {
+			ctx.SetFillColor(t.theme.DisabledTextColor)
+			ctx.Text(drawPosX, drawPosY, string(newBidiText([]rune(t.placeholder), t.direction).visual))
+		} else {
+			ctx.Text(drawPosX, drawPosY, string(newBidiText(t.displayText([]rune(t.value)), t.direction).visual))
+		}
 	} else {
-		text := t.editingText()
-		textString := string(text)
+		text := t.displayText(t.editingText())
+		layout := newBidiText(text, t.direction)
+		textString := string(layout.visual)
 		_, bounds := ctx.TextBounds(drawPosX, drawPosY, textString)
 		lineH := bounds[3] - bounds[1]
 		// find cursor positions
-		glyphs := ctx.TextGlyphPositionsRune(drawPosX, drawPosY, text)
-		t.updateCursor(ctx, bounds[2], glyphs)
+		glyphs := ctx.TextGlyphPositionsRune(drawPosX, drawPosY, layout.visual)
+		t.updateCursor(ctx, bounds[2], glyphs, layout)
 
 		// compute text offset
-		prevCPos := toI(t.cursorPos > 0, t.cursorPos-1, 0)
-		nextCPos := toI(t.cursorPos < len(glyphs), t.cursorPos+1, len(glyphs))
-		prevCX := t.textIndex2Position(prevCPos, bounds[2], glyphs)
-		nextCX := t.textIndex2Position(nextCPos, bounds[2], glyphs)
+		slot := layout.caretSlot(t.cursorPos)
+		prevCX := t.textIndex2Position(layout.slotRuneIndex(maxI(slot-1, 0)), bounds[2], glyphs)
+		nextCX := t.textIndex2Position(layout.slotRuneIndex(minI(slot+1, len(layout.clusters))), bounds[2], glyphs)
 
 		if nextCX > clipX+clipWidth {
 			t.textOffset -= nextCX - (clipX + clipWidth) + 1.0
@@ -459,25 +832,29 @@ func (t *TextBox) Draw(self Widget, ctx *nanovgo.Context) {
 		drawPosX = oldDrawPosX + t.textOffset
 
 		// draw text with offset
-		ctx.TextRune(drawPosX, drawPosY, text)
+		if len(text) == 0 && t.placeholder != "" {
+			ctx.SetFillColor(t.theme.DisabledTextColor)
+			ctx.Text(drawPosX, drawPosY, string(newBidiText([]rune(t.placeholder), t.direction).visual))
+		}
+		ctx.TextRune(drawPosX, drawPosY, layout.visual)
 		_, bounds = ctx.TextBounds(drawPosX, drawPosY, textString)
 
 		// recompute cursor position
-		glyphs = ctx.TextGlyphPositionsRune(drawPosX, drawPosY, text)
+		glyphs = ctx.TextGlyphPositionsRune(drawPosX, drawPosY, layout.visual)
 
 		var caretX float32 = -1
-		if len(t.preeditText) != 0 {
+		if len(t.preeditText) != 0 && !t.password {
 			// draw preedit text
-			caretX = t.textIndex2Position(t.cursorPos+len(t.preeditText), bounds[2], glyphs)
+			caretX = t.caretPosition(layout, t.cursorPos+len(t.preeditText), bounds[2], glyphs)
 
 			offsetIndex := t.cursorPos
-			offsetX := t.textIndex2Position(t.cursorPos, bounds[2], glyphs)
+			offsetX := t.caretPosition(layout, t.cursorPos, bounds[2], glyphs)
 			ctx.SetStrokeColor(nanovgo.MONO(255, 160))
 			ctx.SetFillColor(nanovgo.MONO(255, 80))
 			ctx.SetStrokeWidth(2.0)
 			for i, blockLength := range t.preeditBlocks {
 				nextOffsetIndex := offsetIndex + blockLength
-				nextOffsetX := t.textIndex2Position(nextOffsetIndex, bounds[2], glyphs)
+				nextOffsetX := t.caretPosition(layout, nextOffsetIndex, bounds[2], glyphs)
 				if i != t.preeditFocusedBlock {
 					ctx.BeginPath()
 					ctx.MoveTo(offsetX+2, drawPosY+lineH*0.5-1)
@@ -491,31 +868,29 @@ func (t *TextBox) Draw(self Widget, ctx *nanovgo.Context) {
 				offsetIndex = nextOffsetIndex
 				offsetX = nextOffsetX
 			}
-			screen := t.FindWindow().Parent().(*Screen)
-			oldCurX, oldCurY, oldCurH := screen.PreeditCursorPos()
-			absX, absY := t.Parent().AbsolutePosition()
-			newCurX := int(caretX) + absX
-			newCurY := int(drawPosY+lineH*0.5) + absY
-			newCurH := int(lineH)
-			if oldCurX != newCurX || oldCurY != newCurY || oldCurH != newCurH {
-				screen.SetPreeditCursorPos(newCurX, newCurY, newCurH)
+			if screen := findScreen(t); screen != nil {
+				oldCurX, oldCurY, oldCurH := screen.PreeditCursorPos()
+				absX, absY := t.Parent().AbsolutePosition()
+				newCurX := int(caretX) + absX
+				newCurY := int(drawPosY+lineH*0.5) + absY
+				newCurH := int(lineH)
+				if oldCurX != newCurX || oldCurY != newCurY || oldCurH != newCurH {
+					screen.SetPreeditCursorPos(newCurX, newCurY, newCurH)
+				}
 			}
 		} else if t.cursorPos > -1 {
 			// regular cursor and selection area
-			caretX = t.textIndex2Position(t.cursorPos, bounds[2], glyphs)
+			caretX = t.caretPosition(layout, t.cursorPos, bounds[2], glyphs)
 
 			if t.selectionPos > -1 {
-				caretX2 := caretX
-				selX := t.textIndex2Position(t.selectionPos, bounds[2], glyphs)
-
-				if caretX2 > selX {
-					selX, caretX2 = caretX2, selX
-				}
-
-				// draw selection
+				// draw selection; it is split into several areas in mixed-direction text
 				ctx.BeginPath()
 				ctx.SetFillColor(nanovgo.MONO(255, 80))
-				ctx.Rect(caretX2, drawPosY-lineH*0.5, selX-caretX2, lineH)
+				for _, area := range layout.visualRanges(minI(t.cursorPos, t.selectionPos), maxI(t.cursorPos, t.selectionPos)) {
+					selX1 := t.textIndex2Position(area[0], bounds[2], glyphs)
+					selX2 := t.textIndex2Position(area[1], bounds[2], glyphs)
+					ctx.Rect(selX1, drawPosY-lineH*0.5, selX2-selX1, lineH)
+				}
 				ctx.Fill()
 			}
 		}
@@ -532,6 +907,63 @@ func (t *TextBox) Draw(self Widget, ctx *nanovgo.Context) {
 	ctx.ResetScissor()
 }
 
+// updateValidation() checks the text being edited against the format and the validators
+func (t *TextBox) updateValidation() {
+	text := string(t.valueTemp)
+	if t.mask != nil {
+		text = t.mask.format(t.valueTemp)
+	}
+	t.validFormat = text == "" || t.checkFormat(text)
+	t.validationError = nil
+	if !t.validFormat {
+		t.validationError = ErrInvalidFormat
+		return
+	}
+	if t.mask != nil && text != "" && !t.mask.isComplete(t.valueTemp) {
+		t.validationError = ErrIncompleteValue
+		return
+	}
+	text = t.maskedValue(t.editedValue())
+	if t.rangeValidator != nil {
+		if t.validationError = t.rangeValidator(text); t.validationError != nil {
+			return
+		}
+	}
+	for _, validator := range t.validators {
+		if t.validationError = validator(text); t.validationError != nil {
+			return
+		}
+	}
+}
+
+// editedValue() returns the value which the text being edited is committed as
+func (t *TextBox) editedValue() string {
+	text := string(t.valueTemp)
+	if t.mask != nil {
+		text = t.mask.format(t.valueTemp)
+	}
+	if text == "" {
+		return t.defaultValue
+	}
+	return text
+}
+
+// inlineValidationMessage() returns the message drawn at the right end of the text box
+func (t *TextBox) inlineValidationMessage() string {
+	if t.errorDisplay != ValidationErrorInline || t.committed || t.validationError == nil {
+		return ""
+	}
+	return t.validationError.Error()
+}
+
+// validationTooltip() returns the message which the screen shows in a bubble under the focused text box
+func (t *TextBox) validationTooltip() string {
+	if t.errorDisplay != ValidationErrorTooltip || t.committed || !t.Focused() || t.validationError == nil {
+		return ""
+	}
+	return t.validationError.Error()
+}
+
 func (t *TextBox) checkFormat(input string) bool {
 	if t.format == nil {
 		return true
@@ -539,24 +971,38 @@ func (t *TextBox) checkFormat(input string) bool {
 	return t.format.MatchString(input)
 }
 
+// CopySelection() copies the selected text to the screen's clipboard
 func (t *TextBox) CopySelection() bool {
-	sc := t.FindWindow().Parent().(*Screen)
-	if t.selectionPos > -1 {
+	clipboard := findClipboard(t)
+	if clipboard != nil && t.selectionPos > -1 && !t.password {
 		begin := t.cursorPos
 		end := t.selectionPos
 
 		if begin > end {
 			begin, end = end, begin
 		}
-		sc.GLFWWindow().SetClipboardString(string(t.valueTemp[begin:end]))
+		if t.mask != nil {
+			SetClipboardText(clipboard, string(t.mask.filled(t.valueTemp, begin, end)))
+			return true
+		}
+		SetClipboardText(clipboard, string(t.valueTemp[begin:end]))
+		return true
 	}
 	return false
 }
 
+// PasteFromClipboard() inserts the text on the screen's clipboard at the cursor
 func (t *TextBox) PasteFromClipboard() {
-	sc := t.FindWindow().Parent().(*Screen)
-	str, _ := sc.GLFWWindow().GetClipboardString()
+	clipboard := findClipboard(t)
+	if clipboard == nil {
+		return
+	}
+	str, _ := GetClipboardText(clipboard)
 	runes := []rune(str)
+	if t.mask != nil {
+		t.cursorPos = t.mask.insert(t.valueTemp, t.cursorPos, runes)
+		return
+	}
 	t.valueTemp = append(t.valueTemp[:t.cursorPos], append(runes, t.valueTemp[t.cursorPos:]...)...)
 	t.cursorPos += len(runes)
 }
@@ -569,15 +1015,20 @@ func (t *TextBox) DeleteSelection() bool {
 		if begin > end {
 			begin, end = end, begin
 		}
-		t.valueTemp = append(t.valueTemp[:begin], t.valueTemp[end:]...)
-		t.cursorPos = begin
+		if t.mask != nil {
+			t.mask.clear(t.valueTemp, begin, end)
+			t.cursorPos = t.mask.snapRight(begin)
+		} else {
+			t.valueTemp = append(t.valueTemp[:begin], t.valueTemp[end:]...)
+			t.cursorPos = begin
+		}
 		t.selectionPos = -1
 		return true
 	}
 	return false
 }
 
-func (t *TextBox) updateCursor(ctx *nanovgo.Context, lastX float32, glyphs []nanovgo.GlyphPosition) {
+func (t *TextBox) updateCursor(ctx DrawContext, lastX float32, glyphs []nanovgo.GlyphPosition, layout *bidiText) {
 	if t.mouseDownPos[0] != -1 {
 		if t.mouseDownModifier == glfw.ModShift {
 			if t.selectionPos == -1 {
@@ -586,13 +1037,27 @@ func (t *TextBox) updateCursor(ctx *nanovgo.Context, lastX float32, glyphs []nan
 		} else {
 			t.selectionPos = -1
 		}
-		t.cursorPos = t.position2CursorIndex(float32(t.mouseDownPos[0]), lastX, glyphs)
+		t.cursorPos = t.position2CursorIndex(float32(t.mouseDownPos[0]), lastX, glyphs, layout)
+		if t.mask != nil {
+			t.cursorPos = t.mask.snapRight(t.cursorPos)
+		}
+		if t.clickCount == 2 {
+			/* Double-click: select the word under the cursor, or everything in password mode */
+			if t.password {
+				t.selectionPos, t.cursorPos = 0, len(t.valueTemp)
+			} else {
+				t.selectionPos, t.cursorPos = wordRangeAt(t.valueTemp, t.cursorPos)
+			}
+		}
 		t.mouseDownPos = [2]int{-1, -1}
 	} else if t.mouseDragPos[0] != -1 {
 		if t.selectionPos == -1 {
 			t.selectionPos = t.cursorPos
 		}
-		t.cursorPos = t.position2CursorIndex(float32(t.mouseDragPos[0]), lastX, glyphs)
+		t.cursorPos = t.position2CursorIndex(float32(t.mouseDragPos[0]), lastX, glyphs, layout)
+		if t.mask != nil {
+			t.cursorPos = t.mask.snapRight(t.cursorPos)
+		}
 	} else {
 		// set cursor to last character
 		if t.cursorPos == -2 {
@@ -612,143 +1077,135 @@ func (t *TextBox) textIndex2Position(index int, lastX float32, glyphs []nanovgo.
 	return glyphs[index].X
 }
 
-func (t *TextBox) position2CursorIndex(posX, lastX float32, glyphs []nanovgo.GlyphPosition) int {
-	cursorIndex := 0
+// caretPosition() returns the x coordinate of the caret at a logical index
+func (t *TextBox) caretPosition(layout *bidiText, index int, lastX float32, glyphs []nanovgo.GlyphPosition) float32 {
+	return t.textIndex2Position(layout.slotRuneIndex(layout.caretSlot(index)), lastX, glyphs)
+}
+
+// position2CursorIndex() returns the logical index of the caret nearest to posX
+func (t *TextBox) position2CursorIndex(posX, lastX float32, glyphs []nanovgo.GlyphPosition, layout *bidiText) int {
 	if len(glyphs) == 0 {
 		return 0
 	}
-	caretX := glyphs[0].X
-	for j := 1; j < len(glyphs); j++ {
-		glyph := &glyphs[j]
-		if absF(caretX-posX) > absF(glyph.X-posX) {
-			cursorIndex = j
-			caretX = glyph.X
+	cursorIndex := layout.slotPosition(0)
+	caretX := t.textIndex2Position(0, lastX, glyphs)
+	for slot := 1; slot <= len(layout.clusters); slot++ {
+		x := t.textIndex2Position(layout.slotRuneIndex(slot), lastX, glyphs)
+		if absF(caretX-posX) > absF(x-posX) {
+			cursorIndex = layout.slotPosition(slot)
+			caretX = x
 		}
 	}
-	if absF(caretX-posX) > absF(lastX-posX) {
-		return len(glyphs)
-	}
 	return cursorIndex
 }
 
-func (t *TextBox) editingText() []rune {
-	if len(t.preeditText) == 0 {
-		return t.valueTemp
-	}
-	result := make([]rune, 0, len(t.valueTemp)+len(t.preeditText))
-	result = append(append(append(result, t.valueTemp[:t.cursorPos]...), t.preeditText...), t.valueTemp[t.cursorPos:]...)
-	return result
-}
-
-func (t *TextBox) String() string {
-	return t.StringHelper("TextBox", t.value)
-}
-
-type IntBox struct {
-	TextBox
-
-	callback func(int)
+// moveCursorVisually() returns the cursor position moved by delta grapheme clusters on the screen
+func (t *TextBox) moveCursorVisually(delta int) int {
+	return newBidiText(t.displayText(t.valueTemp), t.direction).moveVisual(t.cursorPos, delta)
 }
 
-func NewIntBox(parent Widget, signed bool, values ...int) *IntBox {
-	var value int
-	switch len(values) {
-	case 0:
-		value = 0
-	case 1:
-		value = values[0]
-	default:
-		panic("NewIntBox can accept only one extra parameter (value)")
-	}
-
-	intBox := &IntBox{}
-	InitWidget(intBox, parent)
-	intBox.init("")
-	if signed {
-		intBox.SetFormat(`^[-]?[0-9]*$`)
-	} else {
-		intBox.SetFormat(`^[0-9]*$`)
+// previousCharBoundary() returns the start of the character before pos
+//
+// Characters are the grapheme clusters of the drawn text, so a hidden password is edited per mask glyph.
+func (t *TextBox) previousCharBoundary(pos int) int {
+	if t.password && !t.revealed {
+		return maxI(pos-1, 0)
 	}
-	intBox.SetValue(value)
-	return intBox
+	return previousGraphemeBoundary(t.valueTemp, pos)
 }
 
-func (t *IntBox) Value() int {
-	v, _ := strconv.ParseInt(t.value, 10, 64)
-	return int(v)
-}
-
-func (t *IntBox) SetValue(value int) {
-	t.value = strconv.FormatInt(int64(value), 10)
-}
-
-func (i *IntBox) DefaultValue() int {
-	v, _ := strconv.ParseInt(i.defaultValue, 10, 64)
-	return int(v)
+// nextCharBoundary() returns the end of the character after pos
+func (t *TextBox) nextCharBoundary(pos int) int {
+	if t.password && !t.revealed {
+		return minI(pos+1, len(t.valueTemp))
+	}
+	return nextGraphemeBoundary(t.valueTemp, pos)
 }
 
-func (i *IntBox) SetDefaultValue(value int) {
-	i.defaultValue = strconv.FormatInt(int64(value), 10)
+// previousWordBoundary() returns the start of the word before pos. In password mode, the value is a single word
+// so that the word boundaries of the secret aren't revealed
+func (t *TextBox) previousWordBoundary(pos int) int {
+	if t.password {
+		return 0
+	}
+	return previousWordBoundary(t.valueTemp, pos)
 }
 
-func (i *IntBox) SetCallback(callback func(int)) {
-	i.callback = callback
+// nextWordBoundary() returns the end of the word after pos. In password mode, the value is a single word
+func (t *TextBox) nextWordBoundary(pos int) int {
+	if t.password {
+		return len(t.valueTemp)
+	}
+	return nextWordBoundary(t.valueTemp, pos)
 }
 
-func (i *IntBox) String() string {
-	return i.StringHelper("IntBox", i.value)
+func (t *TextBox) editState() editState {
+	return newEditState(t.valueTemp, t.cursorPos, t.selectionPos)
 }
 
-type FloatBox struct {
-	TextBox
-
-	callback func(float64)
+func (t *TextBox) restoreEditState(state editState) {
+	t.valueTemp = append([]rune{}, state.text...)
+	t.cursorPos = clampI(state.cursor, 0, len(t.valueTemp))
+	t.selectionPos = toI(state.selection > len(t.valueTemp), -1, state.selection)
+	t.preeditText = nil
+	t.updateValidation()
 }
 
-func NewFloatBox(parent Widget, values ...float64) *FloatBox {
-	var value float64
-	switch len(values) {
-	case 0:
-		value = 0.0
-	case 1:
-		value = values[0]
-	default:
-		panic("NewFloatBox can accept only one extra parameter (value)")
+func (t *TextBox) editingText() []rune {
+	if len(t.preeditText) == 0 || t.password {
+		return t.valueTemp
 	}
-
-	floatBox := &FloatBox{}
-	InitWidget(floatBox, parent)
-	floatBox.init("")
-	floatBox.SetFormat(`^[-]?[0-9]*\.?[0-9]+$`)
-	floatBox.SetValue(value)
-
-	return floatBox
+	result := make([]rune, 0, len(t.valueTemp)+len(t.preeditText))
+	result = append(append(append(result, t.valueTemp[:t.cursorPos]...), t.preeditText...), t.valueTemp[t.cursorPos:]...)
+	return result
 }
 
-func (t *FloatBox) Value() float64 {
-	v, _ := strconv.ParseFloat(string(t.value), 64)
-	return v
+// displayText() returns the text to draw: a mask glyph per character in password mode
+func (t *TextBox) displayText(text []rune) []rune {
+	if !t.password || t.revealed {
+		return text
+	}
+	masked := make([]rune, len(text))
+	for i := range masked {
+		masked[i] = t.passwordMask
+	}
+	return masked
 }
 
-func (t *FloatBox) SetValue(value float64) {
-	// todo: remove trailing zero
-	t.value = strconv.FormatFloat(value, 'f', 5, 64)
+// spinArea() returns the spin arrow at the position in the parent coordinates
+func (t *TextBox) spinArea(x, y int) spinArea {
+	if !t.spinnable || x < t.x || x >= t.x+spinArrowsWidth {
+		return spinAreaNone
+	}
+	if y < t.y+t.h/2 {
+		return spinAreaTop
+	}
+	return spinAreaBottom
 }
 
-func (t *FloatBox) DefaultValue() float64 {
-	v, _ := strconv.ParseFloat(string(t.defaultValue), 64)
-	return v
+// replaceEditingText() replaces the text being edited as an undoable edit
+func (t *TextBox) replaceEditingText(text string) {
+	if t.committed {
+		return
+	}
+	before := t.editState()
+	t.valueTemp = []rune(text)
+	t.cursorPos = len(t.valueTemp)
+	t.selectionPos = -1
+	t.history.record(before, t.valueTemp, t.cursorPos, editKindOther)
+	t.updateValidation()
 }
 
-func (t *FloatBox) SetDefaultValue(value float64) {
-	// todo: remove trailing zero
-	t.defaultValue = strconv.FormatFloat(value, 'f', 5, 64)
+func (t *TextBox) hasRevealButton() bool {
+	return t.password && t.revealButton
 }
 
-func (f *FloatBox) SetCallback(callback func(float64)) {
-	f.callback = callback
+// revealButtonX() returns the left end of the eye icon in the parent coordinates
+func (t *TextBox) revealButtonX() float32 {
+	h := float32(t.h)
+	return float32(t.x+t.w) - h*0.3 - h*0.7
 }
 
-func (f *FloatBox) String() string {
-	return f.StringHelper("FloatBox", f.value)
+func (t *TextBox) String() string {
+	return t.StringHelper("TextBox", string(t.displayText([]rune(t.value))))
 }
@@ -0,0 +1,179 @@
+package nanogui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+// newPasswordBox() creates an editable password box
+func newPasswordBox(window *Window) *TextBox {
+	textBox := NewTextBox(window, "")
+	textBox.SetEditable(true)
+	textBox.SetPassword(true)
+	textBox.SetAlignment(TextLeft)
+	textBox.SetFixedSize(300, 0)
+	return textBox
+}
+
+func TestTextBoxPasswordMasking(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := newPasswordBox(window)
+	textBox.SetPasswordRevealButton(true)
+	screen.PerformLayout()
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.TypeText("secret")
+
+	SetClipboardText(screen.Clipboard(), "original")
+	in.PressKey(glfw.KeyA, glfw.ModControl)
+	in.PressKey(glfw.KeyX, glfw.ModControl)
+	if text, _ := GetClipboardText(screen.Clipboard()); text != "original" || string(textBox.valueTemp) != "secret" {
+		t.Fatalf("Ctrl+X shouldn't cut a password: clipboard %q, value %q", text, string(textBox.valueTemp))
+	}
+
+	in.PressKey(glfw.KeyEnd, 0)
+	in.Preedit("あ", []int{1}, 0)
+	in.CommitPreedit()
+	ctx := screen.Context().(*RecordingContext)
+	ctx.Reset()
+	screen.DrawAll()
+	drawn := false
+	for _, call := range ctx.Calls() {
+		if call.Name == "TextRune" {
+			if text := string(call.Args[2].([]rune)); text != "•••••••" {
+				t.Fatalf("password is drawn as %q", text)
+			}
+			drawn = true
+		}
+	}
+	if !drawn {
+		t.Fatal("the masked password isn't drawn")
+	}
+
+	x, y := textBox.AbsolutePosition()
+	in.ClickAt(x+295, y+5)
+	if !textBox.Revealed() {
+		t.Fatal("clicking the reveal button should show the password")
+	}
+	in.PressKey(glfw.KeyTab, 0)
+	if textBox.Revealed() {
+		t.Fatal("losing the focus should hide the password again")
+	}
+	if node := DescribeWidget(textBox); !node.Has(StateProtected) || node.Value != "•••••••" {
+		t.Errorf("accessible node = %+v, want a protected node with a masked value", node)
+	}
+}
+
+func TestTextBoxPasswordEditingUnits(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := newPasswordBox(window)
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.TypeText("ab cde\u0301")
+
+	// each code point is a mask glyph, so the combining mark is deleted alone
+	in.PressKey(glfw.KeyBackspace, 0)
+	if got := string(textBox.valueTemp); got != "ab cde" {
+		t.Fatalf("Backspace: %q, want %q", got, "ab cde")
+	}
+	in.PressKey(glfw.KeyLeft, 0)
+	if textBox.cursorPos != 5 {
+		t.Fatalf("Left: cursor %d, want 5", textBox.cursorPos)
+	}
+	in.PressKey(glfw.KeyLeft, glfw.ModControl)
+	if textBox.cursorPos != 0 {
+		t.Fatalf("Ctrl+Left should move to the start: cursor %d", textBox.cursorPos)
+	}
+	in.PressKey(glfw.KeyRight, glfw.ModControl)
+	if textBox.cursorPos != 6 {
+		t.Fatalf("Ctrl+Right should move to the end: cursor %d", textBox.cursorPos)
+	}
+	in.PressKey(glfw.KeyBackspace, glfw.ModControl)
+	if len(textBox.valueTemp) != 0 {
+		t.Fatalf("Ctrl+Backspace should delete the whole password: %q", string(textBox.valueTemp))
+	}
+}
+
+func drawsCall(ctx *RecordingContext, name string, index int, arg interface{}) bool {
+	for _, call := range ctx.Calls() {
+		if call.Name == name && len(call.Args) > index && call.Args[index] == arg {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTextBoxValidators(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := NewTextBox(window, "")
+	textBox.SetEditable(true)
+	textBox.SetPlaceholder("name")
+	committed := 0
+	textBox.SetCallback(func(string) bool {
+		committed++
+		return true
+	})
+	textBox.AddValidator(func(value string) error {
+		if len(value) < 3 {
+			return errors.New("too short")
+		}
+		return nil
+	})
+	screen.PerformLayout()
+	ctx := screen.Context().(*RecordingContext)
+	ctx.Reset()
+	screen.DrawAll()
+	if !drawsCall(ctx, "Text", 2, "name") {
+		t.Fatal("the placeholder isn't drawn for an empty text box")
+	}
+
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.TypeText("ab")
+	if textBox.ValidationError() == nil {
+		t.Fatal("ValidationError() should report the failing validator")
+	}
+	in.PressKey(glfw.KeyEnter, 0)
+	if !textBox.Focused() || committed != 0 {
+		t.Fatal("Enter shouldn't commit an invalid value")
+	}
+	ctx.Reset()
+	screen.DrawAll()
+	if !drawsCall(ctx, "TextBox", 3, "too short") {
+		t.Error("the validation error tooltip isn't drawn")
+	}
+	in.TypeText("c")
+	in.PressKey(glfw.KeyEnter, 0)
+	if committed != 1 || textBox.Value() != "abc" {
+		t.Errorf("valid value: committed %d times, Value() = %q", committed, textBox.Value())
+	}
+}
+
+func TestIntBoxInlineRangeError(t *testing.T) {
+	screen, window := newTestWindow(t)
+	intBox := NewIntBox(window, true, 5)
+	intBox.SetEditable(true)
+	intBox.SetRange(0, 10)
+	intBox.SetValidationErrorDisplay(ValidationErrorInline)
+	screen.PerformLayout()
+
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.PressKey(glfw.KeyEnd, 0)
+	in.TypeText("0")
+	if intBox.ValidationError() == nil {
+		t.Fatal("50 is out of the range")
+	}
+	ctx := screen.Context().(*RecordingContext)
+	ctx.Reset()
+	screen.DrawAll()
+	if !drawsCall(ctx, "Text", 2, "enter a number between 0 and 10") {
+		t.Error("the inline error message isn't drawn")
+	}
+	in.PressKey(glfw.KeyTab, 0)
+	if intBox.Value() != 5 {
+		t.Errorf("an invalid value shouldn't be committed: Value() = %d", intBox.Value())
+	}
+}
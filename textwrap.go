@@ -0,0 +1,100 @@
+package nanogui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// wrapText() breaks text into lines which fit in width with the current font settings of ctx
+//
+// Lines break at newlines, after spaces and between CJK characters. A word wider than
+// width is broken between grapheme clusters, so emoji sequences and combining marks
+// are never split.
+func wrapText(ctx DrawContext, text string, width float32) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		lines = append(lines, wrapParagraph(ctx, []rune(paragraph), width)...)
+	}
+	return lines
+}
+
+func wrapParagraph(ctx DrawContext, text []rune, width float32) []string {
+	lines := make([]string, 0, 1)
+	// the glyph positions are measured once, so each candidate line costs a subtraction
+	glyphs := ctx.TextGlyphPositionsRune(0, 0, text)
+	advance := textWidth(ctx, text)
+	position := func(i int) float32 {
+		if i >= len(glyphs) {
+			return advance
+		}
+		// the positions depend on the text alignment of ctx
+		return glyphs[i].X - glyphs[0].X
+	}
+	measure := func(begin, end int) float32 {
+		return position(end) - position(begin)
+	}
+	for _, line := range breakLines(text, width, measure) {
+		lines = append(lines, string(text[line[0]:line[1]]))
+	}
+	return lines
+}
+
+// breakLines() returns the [begin, end) ranges of the lines of a paragraph which fit in width.
+// measure() returns the width of text[begin:end]. Spaces at the end of the lines are left out
+func breakLines(text []rune, width float32, measure func(begin, end int) float32) [][2]int {
+	var lines [][2]int
+	boundaries := graphemeBoundaries(text)
+	start := 0
+	lastBreak := 0
+	for i := 1; i < len(boundaries); i++ {
+		begin, end := boundaries[i-1], boundaries[i]
+		if begin > start && isLineBreakOpportunity(text, begin) {
+			lastBreak = begin
+		}
+		if begin == start || measure(start, trimRightSpaces(text, start, end)) <= width {
+			continue
+		}
+		breakAt := begin
+		if lastBreak > start {
+			breakAt = lastBreak
+		}
+		lines = append(lines, [2]int{start, trimRightSpaces(text, start, breakAt)})
+		start = breakAt
+		for start < begin && unicode.IsSpace(text[start]) {
+			start++
+		}
+		lastBreak = start
+		// the rest of the line may still be too wide
+		i--
+	}
+	return append(lines, [2]int{start, trimRightSpaces(text, start, len(text))})
+}
+
+// isLineBreakOpportunity() returns whether or not a line can start at pos
+func isLineBreakOpportunity(text []rune, pos int) bool {
+	before, after := text[pos-1], text[pos]
+	if unicode.IsSpace(after) {
+		return false
+	}
+	if unicode.IsSpace(before) {
+		return true
+	}
+	return isCJKWordClass(classifyRune(before)) || isCJKWordClass(classifyRune(after))
+}
+
+func isCJKWordClass(class wordClass) bool {
+	return class == wordClassHan || class == wordClassHiragana || class == wordClassKatakana
+}
+
+// trimRightSpaces() returns the end of text[begin:end] without the trailing spaces
+func trimRightSpaces(text []rune, begin, end int) int {
+	for end > begin && unicode.IsSpace(text[end-1]) {
+		end--
+	}
+	return end
+}
+
+func textWidth(ctx DrawContext, text []rune) float32 {
+	width, _ := ctx.TextBounds(0, 0, string(text))
+	return width
+}
@@ -30,6 +30,12 @@ type Theme struct {
 	ButtonGradientTopPushed    nanovgo.Color
 	ButtonGradientBotPushed    nanovgo.Color
 
+	FocusRingColor nanovgo.Color
+	FocusRingWidth int
+
+	ErrorColor nanovgo.Color
+	LinkColor  nanovgo.Color
+
 	/* Window-related */
 	WindowFillUnfocused  nanovgo.Color
 	WindowFillFocused    nanovgo.Color
@@ -47,9 +53,13 @@ type Theme struct {
 	FontNormal string
 	FontBold   string
 	FontIcons  string
+	// FontItalic and FontBoldItalic are used by RichLabel. When they are empty,
+	// italic text is drawn with FontNormal and FontBold slanted by a skew transform
+	FontItalic     string
+	FontBoldItalic string
 }
 
-func NewStandardTheme(ctx *nanovgo.Context) *Theme {
+func NewStandardTheme(ctx DrawContext) *Theme {
 	ctx.CreateFontFromMemory("sans", MustAsset("fonts/Roboto-Regular.ttf"), 0)
 	ctx.CreateFontFromMemory("sans-bold", MustAsset("fonts/Roboto-Bold.ttf"), 0)
 	ctx.CreateFontFromMemory("icons", MustAsset("fonts/entypo.ttf"), 0)
@@ -79,6 +89,12 @@ func NewStandardTheme(ctx *nanovgo.Context) *Theme {
 		ButtonGradientTopPushed:    nanovgo.MONO(41, 255),
 		ButtonGradientBotPushed:    nanovgo.MONO(29, 255),
 
+		FocusRingColor: nanovgo.RGBA(90, 140, 230, 200),
+		FocusRingWidth: 2,
+
+		ErrorColor: nanovgo.RGBA(220, 60, 60, 255),
+		LinkColor:  nanovgo.RGBA(100, 160, 255, 255),
+
 		WindowFillUnfocused:  nanovgo.MONO(43, 230),
 		WindowFillFocused:    nanovgo.MONO(45, 230),
 		WindowTitleUnfocused: nanovgo.MONO(220, 160),
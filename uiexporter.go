@@ -0,0 +1,483 @@
+package nanogui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/shibukawa/nanovgo"
+)
+
+// ExportUI() converts a live widget tree into a UIDocument which BuildUI() can rebuild
+//
+// If root is a *Screen, the document contains its top level windows. Otherwise
+// it contains root itself. Popups are exported through their PopupButton.
+// Widgets which BuildUI() can't create are exported as a plain Widget with their
+// geometry, ID and children, and their type in Class; unknown layouts are omitted.
+func ExportUI(root Widget) (*UIDocument, error) {
+	doc := &UIDocument{}
+	roots := []Widget{root}
+	if _, ok := root.(*Screen); ok {
+		roots = root.Children()
+	}
+	for _, widget := range roots {
+		if _, ok := widget.(*Popup); ok {
+			continue
+		}
+		node, err := ExportWidget(widget)
+		if err != nil {
+			return nil, err
+		}
+		doc.Widgets = append(doc.Widgets, node)
+	}
+	return doc, nil
+}
+
+// WriteUI() writes the widget tree under root as an indented JSON UIDocument
+func WriteUI(w io.Writer, root Widget) error {
+	doc, err := ExportUI(root)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ExportWidget() converts a widget and its descendants into a UINode
+func ExportWidget(widget Widget) (*UINode, error) {
+	node := &UINode{
+		Type: WidgetTypeName(widget),
+		ID:   widget.ID(),
+	}
+	if err := exportCommon(node, widget); err != nil {
+		return nil, err
+	}
+	if parent := widget.Parent(); parent != nil {
+		if layout, ok := parent.Layout().(*AdvancedGridLayout); ok {
+			if anchor, ok := layout.anchors[widget]; ok {
+				node.Anchor = exportAnchor(anchor)
+			}
+		}
+		if layout, ok := parent.Layout().(*GridLayout); ok {
+			if cell, ok := layout.cells[widget]; ok {
+				node.Cell = exportGridCell(cell)
+			}
+		}
+		if layout, ok := parent.Layout().(*FlexLayout); ok {
+			if item, ok := layout.items[widget]; ok {
+				node.Flex = exportFlexItem(item)
+			}
+		}
+	}
+
+	switch w := widget.(type) {
+	case *Window:
+		node.Title = w.Title()
+		node.Modal = w.Modal()
+		if !w.Draggable() {
+			node.Draggable = boolPtr(false)
+		}
+	case *Label:
+		node.Caption = w.Caption()
+		node.ColumnWidth = w.ColumnWidth()
+		if !w.Wrap() {
+			node.Wrap = boolPtr(false)
+		}
+		if w.TextDirection() != TextDirectionAuto {
+			node.Direction = w.TextDirection().String()
+		}
+	case *RichLabel:
+		node.Markup = w.Markup()
+		node.ColumnWidth = w.ColumnWidth()
+		if !w.Wrap() {
+			node.Wrap = boolPtr(false)
+		}
+	case *ComboBox:
+		node.Items = w.Items()
+		if !equalStrings(w.Items(), w.ShortItems()) {
+			node.ShortItems = w.ShortItems()
+		}
+		node.SelectedIndex = intPtr(w.SelectedIndex())
+		// items are rebuilt from Items, so the popup isn't exported
+		return node, nil
+	case *ColorPicker:
+		node.Color = colorString(w.Color())
+		// the popup is created by the color picker itself
+		return node, nil
+	case *PopupButton:
+		exportButton(node, &w.Button)
+		popup, err := exportContent(&UINode{}, w.Popup())
+		if err != nil {
+			return nil, err
+		}
+		if popup.Layout != nil || len(popup.Children) > 0 {
+			node.Popup = popup
+		}
+	case *Button:
+		exportButton(node, w)
+	case *CheckBox:
+		node.Caption = w.Caption()
+		node.Checked = w.Checked()
+	case *IntBox:
+		exportTextBox(node, &w.TextBox)
+		node.Value = w.Value()
+		node.DefaultValue = w.DefaultValue()
+		node.Signed = boolPtr(w.signed)
+		if w.hasRange {
+			node.Min, node.Max = w.Range()
+		}
+		if w.Step() != 1 {
+			node.Step = w.Step()
+		}
+	case *FloatBox:
+		exportTextBox(node, &w.TextBox)
+		node.Value = w.Value()
+		node.DefaultValue = w.DefaultValue()
+		if w.hasRange {
+			node.Min, node.Max = w.Range()
+		}
+		if w.Step() != 0.1 {
+			node.Step = w.Step()
+		}
+		if w.Precision() != -1 {
+			node.Precision = intPtr(w.Precision())
+		}
+	case *TextBox:
+		exportTextBox(node, w)
+		node.Value = w.Value()
+		node.DefaultValue = w.DefaultValue()
+		node.Format = w.Format()
+		node.Mask = w.Mask()
+		if w.MaskValueMode() == MaskValueRaw {
+			node.MaskValue = "raw"
+		}
+	case *TextArea:
+		node.Value = w.Value()
+		node.Editable = w.Editable()
+		node.Rows = w.Rows()
+		if !w.Wrap() {
+			node.Wrap = boolPtr(false)
+		}
+	case *Slider:
+		node.Value = w.Value()
+	case *ProgressBar:
+		node.Value = w.Value()
+	case *VScrollPanel:
+		node.Scroll = w.Scroll()
+	case *ImagePanel:
+		for _, image := range w.Images() {
+			node.Images = append(node.Images, UIImage{ID: image.ImageID, Name: image.Name})
+		}
+	case *ImageView:
+		node.Image = w.Image()
+		if w.Policy() == ImageSizePolicyExpand {
+			node.ImagePolicy = "expand"
+		}
+	case *ColorWheel:
+		node.Color = colorString(w.Color())
+	case *Graph:
+		node.Caption = w.Caption()
+		node.Header = w.Header()
+		node.Footer = w.Footer()
+		node.Values = w.Values()
+		node.BackgroundColor = colorString(w.BackgroundColor())
+		node.ForegroundColor = colorString(w.ForegroundColor())
+		node.TextColor = colorString(w.TextColor())
+	case *WidgetImplement:
+	default:
+		node.Type = "Widget"
+		node.Class = WidgetTypeName(widget)
+	}
+	return exportContent(node, widget)
+}
+
+func exportContent(node *UINode, widget Widget) (*UINode, error) {
+	var children []Widget
+	for _, child := range widget.Children() {
+		if _, ok := child.(*Popup); ok {
+			continue
+		}
+		childNode, err := ExportWidget(child)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, childNode)
+		children = append(children, child)
+	}
+	if widget.Layout() != nil {
+		node.Layout = exportLayout(widget.Layout(), children)
+	}
+	return node, nil
+}
+
+func exportCommon(node *UINode, widget Widget) error {
+	x, y := widget.Position()
+	node.Position = &[2]int{x, y}
+	w, h := widget.Size()
+	node.Size = &[2]int{w, h}
+	if fw, fh := widget.FixedSize(); fw != 0 || fh != 0 {
+		node.FixedSize = &[2]int{fw, fh}
+	}
+	if mw, mh := widget.MinimumSize(); mw != 0 || mh != 0 {
+		node.MinimumSize = &[2]int{mw, mh}
+	}
+	if mw, mh := widget.MaximumSize(); mw != 0 || mh != 0 {
+		node.MaximumSize = &[2]int{mw, mh}
+	}
+	if hp, vp := widget.SizePolicy(); hp != SizePreferred || vp != SizePreferred {
+		node.SizePolicy = &[2]string{hp.String(), vp.String()}
+	}
+	if !widget.Visible() {
+		node.Visible = boolPtr(false)
+	}
+	if !widget.Enabled() {
+		node.Enabled = boolPtr(false)
+	}
+	node.Tooltip = widget.Tooltip()
+	if widget.HasFontSize() {
+		node.FontSize = widget.FontSize()
+	}
+	if fontWidget, ok := widget.(interface {
+		Font() string
+	}); ok {
+		node.Font = fontWidget.Font()
+	}
+	return nil
+}
+
+func exportButton(node *UINode, button *Button) {
+	node.Caption = button.Caption()
+	flags := button.Flags()
+	for _, flag := range []ButtonFlags{NormalButtonType, RadioButtonType, ToggleButtonType} {
+		if flags&flag != 0 {
+			node.Flags = append(node.Flags, buttonFlagName(flag))
+		}
+	}
+	node.Icon = button.Icon()
+	node.IconPosition = buttonIconPositionName(button.IconPosition())
+	node.Pushed = button.Pushed()
+}
+
+func exportTextBox(node *UINode, textBox *TextBox) {
+	node.Editable = textBox.Editable()
+	node.Units = textBox.Units()
+	node.Password = textBox.Password()
+	node.Placeholder = textBox.Placeholder()
+	node.Spinnable = textBox.Spinnable()
+	node.Alignment = textAlignmentName(textBox.Alignment())
+	if textBox.TextDirection() != TextDirectionAuto {
+		node.Direction = textBox.TextDirection().String()
+	}
+}
+
+// exportLayout() returns nil for layouts which BuildUI() can't create. children are the exported children of the widget
+func exportLayout(layout Layout, children []Widget) *UILayout {
+	switch l := layout.(type) {
+	case *BoxLayout:
+		return &UILayout{
+			Type:        "BoxLayout",
+			Orientation: l.Orientation().String(),
+			Alignment:   l.Alignment().String(),
+			Margin:      intPtr(l.Margin()),
+			Spacing:     intPtr(l.Spacing()),
+		}
+	case *GroupLayout:
+		return &UILayout{
+			Type:         "GroupLayout",
+			Margin:       intPtr(l.Margin()),
+			Spacing:      intPtr(l.Spacing()),
+			GroupIndent:  intPtr(l.GroupIndent()),
+			GroupSpacing: intPtr(l.GroupSpacing()),
+		}
+	case *GridLayout:
+		return &UILayout{
+			Type:          "GridLayout",
+			Orientation:   l.Orientation().String(),
+			Resolution:    l.Resolution(),
+			Margin:        intPtr(l.Margin()),
+			ColSpacing:    intPtr(l.ColSpacing()),
+			RowSpacing:    intPtr(l.RowSpacing()),
+			ColAlignment:  l.ColDefaultAlignment().String(),
+			RowAlignment:  l.RowDefaultAlignment().String(),
+			ColAlignments: alignmentNames(l.ColAlignment()),
+			RowAlignments: alignmentNames(l.RowAlignment()),
+		}
+	case *AdvancedGridLayout:
+		return &UILayout{
+			Type:       "AdvancedGridLayout",
+			Margin:     intPtr(l.Margin()),
+			Cols:       append([]int{}, l.cols...),
+			Rows:       append([]int{}, l.rows...),
+			ColStretch: append([]float32{}, l.colStretch...),
+			RowStretch: append([]float32{}, l.rowStretch...),
+		}
+	case *FlexLayout:
+		return &UILayout{
+			Type:        "FlexLayout",
+			Orientation: l.Orientation().String(),
+			Justify:     l.Justify().String(),
+			Alignment:   l.Alignment().String(),
+			Wrap:        l.Wrap(),
+			Margin:      intPtr(l.Margin()),
+			Spacing:     intPtr(l.Spacing()),
+			LineSpacing: intPtr(l.LineSpacing()),
+		}
+	case *ConstraintLayout:
+		strength := l.SizeStrength()
+		return &UILayout{
+			Type:         "ConstraintLayout",
+			SizeStrength: &strength,
+			Constraints:  exportConstraints(l, children),
+		}
+	}
+	return nil
+}
+
+// exportConstraints() converts the constraints added to a ConstraintLayout
+//
+// Constraints which refer to widgets that aren't exported children are omitted.
+func exportConstraints(layout *ConstraintLayout, children []Widget) []UIConstraint {
+	terms := make(map[*ConstraintVariable]UIConstraintTerm)
+	for i, variable := range layout.container.variables {
+		terms[variable] = UIConstraintTerm{Attribute: ConstraintAttribute(i).String()}
+	}
+	for i, child := range children {
+		if item, ok := layout.items[child]; ok {
+			for j, variable := range item.variables {
+				terms[variable] = UIConstraintTerm{Child: intPtr(i), Attribute: ConstraintAttribute(j).String()}
+			}
+		}
+	}
+	var constraints []UIConstraint
+	for _, constraint := range layout.Constraints() {
+		if c, ok := exportConstraint(constraint, terms); ok {
+			constraints = append(constraints, c)
+		}
+	}
+	return constraints
+}
+
+func exportConstraint(constraint *Constraint, terms map[*ConstraintVariable]UIConstraintTerm) (UIConstraint, bool) {
+	c := UIConstraint{
+		Constant: constraint.expression.constant,
+		Relation: constraint.relation.String(),
+	}
+	for _, term := range constraint.expression.terms {
+		exported, ok := terms[term.variable]
+		if !ok {
+			return c, false
+		}
+		exported.Coefficient = term.coefficient
+		c.Terms = append(c.Terms, exported)
+	}
+	if constraint.strength != StrengthRequired {
+		strength := constraint.strength
+		c.Strength = &strength
+	}
+	return c, true
+}
+
+func exportAnchor(anchor Anchor) *UIAnchor {
+	return &UIAnchor{
+		Pos:   [2]int{int(anchor.pos[0]), int(anchor.pos[1])},
+		Size:  &[2]int{int(anchor.size[0]), int(anchor.size[1])},
+		Align: [2]string{anchor.align[0].String(), anchor.align[1].String()},
+	}
+}
+
+func exportGridCell(cell GridCell) *UIGridCell {
+	return &UIGridCell{
+		Pos:  &[2]int{cell.pos[0], cell.pos[1]},
+		Span: &[2]int{cell.span[0], cell.span[1]},
+	}
+}
+
+func exportFlexItem(item FlexItem) *UIFlex {
+	shrink := item.Shrink()
+	basis := item.Basis()
+	flex := &UIFlex{
+		Grow:   item.Grow(),
+		Shrink: &shrink,
+		Basis:  &basis,
+		Min:    item.Min(),
+		Max:    item.Max(),
+	}
+	if align, ok := item.Alignment(); ok {
+		flex.Align = align.String()
+	}
+	return flex
+}
+
+func alignmentNames(alignments []Alignment) []string {
+	var names []string
+	for _, alignment := range alignments {
+		names = append(names, alignment.String())
+	}
+	return names
+}
+
+func buttonFlagName(flag ButtonFlags) string {
+	switch flag {
+	case NormalButtonType:
+		return "normal"
+	case RadioButtonType:
+		return "radio"
+	case ToggleButtonType:
+		return "toggle"
+	}
+	return "popup"
+}
+
+func buttonIconPositionName(position ButtonIconPosition) string {
+	switch position {
+	case ButtonIconLeft:
+		return "left"
+	case ButtonIconRight:
+		return "right"
+	case ButtonIconRightCentered:
+		return "rightCentered"
+	}
+	return "leftCentered"
+}
+
+func textAlignmentName(alignment TextAlignment) string {
+	switch alignment {
+	case TextLeft:
+		return "left"
+	case TextRight:
+		return "right"
+	}
+	return "center"
+}
+
+// colorString() formats a color as "#rrggbbaa"
+func colorString(color nanovgo.Color) string {
+	channel := func(value float32) uint8 {
+		return uint8(clampF(value, 0, 1)*255 + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", channel(color.R), channel(color.G), channel(color.B), channel(color.A))
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func intPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,142 @@
+package nanogui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/shibukawa/nanovgo"
+)
+
+func TestWriteUIRoundTrip(t *testing.T) {
+	source, window := newTestWindow(t)
+	window.SetID("main")
+	window.SetPosition(20, 30)
+	NewLabel(window, "Hello").SetFont("sans-bold")
+	button := NewButton(window, "OK")
+	button.SetFlags(ToggleButtonType)
+	button.SetPushed(true)
+	NewIntBox(window, false, 42).SetID("port")
+	popupButton := NewPopupButton(window, "More")
+	popupButton.Popup().SetLayout(NewGroupLayout())
+	NewCheckBox(popupButton.Popup(), "flag").SetChecked(true)
+	NewComboBox(window, []string{"a", "b"}).SetSelectedIndex(1)
+	grid := NewWidget(window)
+	layout := NewAdvancedGridLayout([]int{10, 0}, []int{0})
+	layout.SetColStretch(1, 1)
+	grid.SetLayout(layout)
+	layout.SetAnchor(NewLabel(grid, "x"), NewAnchor(1, 0, Minimum, Middle))
+	NewSlider(window).SetValue(0.25)
+	source.PerformLayout()
+
+	var exported bytes.Buffer
+	if err := WriteUI(&exported, source); err != nil {
+		t.Fatal(err)
+	}
+	screen := NewHeadlessScreen(800, 600)
+	ui, err := LoadUI(screen, bytes.NewReader(exported.Bytes()))
+	if err != nil {
+		t.Fatalf("%v\n%s", err, exported.String())
+	}
+	if value := ui.Widget("port").(*IntBox).Value(); value != 42 {
+		t.Errorf("Value() = %d, want 42", value)
+	}
+	screen.PerformLayout()
+	var reexported bytes.Buffer
+	if err := WriteUI(&reexported, screen); err != nil {
+		t.Fatal(err)
+	}
+	if exported.String() != reexported.String() {
+		t.Errorf("round trip changes the document\n%s\n---\n%s", exported.String(), reexported.String())
+	}
+}
+
+func TestExportIntBoxSettings(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	unsigned := NewIntBox(screen, false, 7)
+	unsigned.SetID("unsigned")
+	unsigned.SetFormat(`^[0-9]{0,4}$`)
+	large := NewIntBox(screen, true, 0)
+	large.SetID("large")
+	large.SetRange(-(1<<60)-1, 1<<60+1)
+	large.SetStep(1<<55 + 1)
+
+	var exported bytes.Buffer
+	if err := WriteUI(&exported, screen); err != nil {
+		t.Fatal(err)
+	}
+	ui, err := LoadUI(NewHeadlessScreen(800, 600), &exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ui.Widget("unsigned").(*IntBox).signed {
+		t.Error("an unsigned IntBox with a custom format is loaded as signed")
+	}
+	loaded := ui.Widget("large").(*IntBox)
+	if min, max := loaded.Range(); min != -(1<<60)-1 || max != 1<<60+1 {
+		t.Errorf("Range() = %d, %d, want %d, %d", min, max, int64(-(1<<60)-1), int64(1<<60+1))
+	}
+	if step := loaded.Step(); step != 1<<55+1 {
+		t.Errorf("Step() = %d, want %d", step, int64(1<<55+1))
+	}
+}
+
+type exportTestWidget struct {
+	WidgetImplement
+}
+
+func TestExportWidgetKinds(t *testing.T) {
+	screen, window := newTestWindow(t)
+	NewLabel(NewVScrollPanel(window), "scrolled")
+	NewImagePanel(window).SetImages([]Image{{ImageID: 3, Name: "icon"}})
+	NewImageView(window, 5).SetPolicy(ImageSizePolicyExpand)
+	NewColorWheel(window)
+	NewColorPicker(window)
+	graph := NewGraph(window, "fps")
+	graph.SetValues([]float32{0.5, 1})
+	graph.SetBackgroundColor(nanovgo.RGBA(1, 2, 3, 4))
+	custom := &exportTestWidget{}
+	InitWidget(custom, window)
+	custom.SetID("custom")
+	NewLabel(custom, "inside")
+	screen.PerformLayout()
+
+	doc, err := ExportUI(screen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []string
+	for _, child := range doc.Widgets[0].Children {
+		types = append(types, child.Type)
+	}
+	want := []string{"VScrollPanel", "ImagePanel", "ImageView", "ColorWheel", "ColorPicker", "Graph", "Widget"}
+	if !equalStrings(types, want) {
+		t.Fatalf("exported types = %v, want %v", types, want)
+	}
+	if class := doc.Widgets[0].Children[6].Class; class != "exportTestWidget" {
+		t.Errorf("Class = %q, want %q", class, "exportTestWidget")
+	}
+
+	ui, err := BuildUI(NewHeadlessScreen(800, 600), doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	children := ui.Roots()[0].Children()
+	if images := children[1].(*ImagePanel).Images(); len(images) != 1 || images[0] != (Image{ImageID: 3, Name: "icon"}) {
+		t.Errorf("Images() = %v", images)
+	}
+	if view := children[2].(*ImageView); view.Image() != 5 || view.Policy() != ImageSizePolicyExpand {
+		t.Errorf("Image(), Policy() = %d, %d", view.Image(), view.Policy())
+	}
+	loadedGraph := children[5].(*Graph)
+	if loadedGraph.Caption() != "fps" || len(loadedGraph.Values()) != 2 || loadedGraph.BackgroundColor() != nanovgo.RGBA(1, 2, 3, 4) {
+		t.Errorf("Graph = %q, %v, %v", loadedGraph.Caption(), loadedGraph.Values(), loadedGraph.BackgroundColor())
+	}
+	loadedCustom := ui.Widget("custom")
+	wantX, wantY := custom.Position()
+	if x, y := loadedCustom.Position(); x != wantX || y != wantY || loadedCustom.ChildCount() != 1 {
+		t.Errorf("generic widget: position %d, %d, %d children", x, y, loadedCustom.ChildCount())
+	}
+	if caption := children[0].Children()[0].(*Label).Caption(); caption != "scrolled" {
+		t.Errorf("scrolled label = %q", caption)
+	}
+}
@@ -0,0 +1,1257 @@
+package nanogui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/shibukawa/nanovgo"
+)
+
+// UIDocument is a declarative description of widget trees
+//
+// It is usually stored as JSON:
+//
+//	{"widgets": [{
+//	    "type": "Window", "id": "settings", "title": "Settings", "position": [15, 15],
+//	    "layout": {"type": "GroupLayout"},
+//	    "children": [
+//	        {"type": "Label", "caption": "Port", "font": "sans-bold"},
+//	        {"type": "IntBox", "id": "port", "value": 8080, "editable": true}
+//	    ]
+//	}]}
+//
+// The same document can be written in YAML and read with the uiyaml package. The structs
+// only use plain fields, so other formats can be decoded into them and passed to BuildUI().
+type UIDocument struct {
+	Widgets []*UINode `json:"widgets" yaml:"widgets"`
+}
+
+// UINode describes a widget, its layout and its children
+//
+// Widget specific fields are ignored by widget types which don't support them.
+type UINode struct {
+	Type        string      `json:"type" yaml:"type"`
+	Class       string      `json:"class,omitempty" yaml:"class,omitempty"` // exported type of a widget which is loaded as a plain Widget
+	ID          string      `json:"id,omitempty" yaml:"id,omitempty"`
+	Position    *[2]int     `json:"position,omitempty" yaml:"position,omitempty"`
+	Size        *[2]int     `json:"size,omitempty" yaml:"size,omitempty"`
+	FixedSize   *[2]int     `json:"fixedSize,omitempty" yaml:"fixedSize,omitempty"`
+	MinimumSize *[2]int     `json:"minimumSize,omitempty" yaml:"minimumSize,omitempty"`
+	MaximumSize *[2]int     `json:"maximumSize,omitempty" yaml:"maximumSize,omitempty"`
+	SizePolicy  *[2]string  `json:"sizePolicy,omitempty" yaml:"sizePolicy,omitempty"`
+	Visible     *bool       `json:"visible,omitempty" yaml:"visible,omitempty"`
+	Enabled     *bool       `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Tooltip     string      `json:"tooltip,omitempty" yaml:"tooltip,omitempty"`
+	FontSize    int         `json:"fontSize,omitempty" yaml:"fontSize,omitempty"`
+	Font        string      `json:"font,omitempty" yaml:"font,omitempty"`
+	Layout      *UILayout   `json:"layout,omitempty" yaml:"layout,omitempty"`
+	Anchor      *UIAnchor   `json:"anchor,omitempty" yaml:"anchor,omitempty"`
+	Cell        *UIGridCell `json:"cell,omitempty" yaml:"cell,omitempty"`
+	Flex        *UIFlex     `json:"flex,omitempty" yaml:"flex,omitempty"`
+	Children    []*UINode   `json:"children,omitempty" yaml:"children,omitempty"`
+
+	// Window
+	Title     string `json:"title,omitempty" yaml:"title,omitempty"`
+	Modal     bool   `json:"modal,omitempty" yaml:"modal,omitempty"`
+	Draggable *bool  `json:"draggable,omitempty" yaml:"draggable,omitempty"`
+
+	// Label, Button, CheckBox, PopupButton, Graph
+	Caption string `json:"caption,omitempty" yaml:"caption,omitempty"`
+
+	// Label, RichLabel
+	ColumnWidth int   `json:"columnWidth,omitempty" yaml:"columnWidth,omitempty"`
+	Wrap        *bool `json:"wrap,omitempty" yaml:"wrap,omitempty"`
+
+	// RichLabel
+	Markup string `json:"markup,omitempty" yaml:"markup,omitempty"`
+
+	// Label, TextBox
+	Direction string `json:"direction,omitempty" yaml:"direction,omitempty"`
+
+	// Button, PopupButton
+	Flags        []string `json:"flags,omitempty" yaml:"flags,omitempty"`
+	Icon         Icon     `json:"icon,omitempty" yaml:"icon,omitempty"`
+	IconPosition string   `json:"iconPosition,omitempty" yaml:"iconPosition,omitempty"`
+	Pushed       bool     `json:"pushed,omitempty" yaml:"pushed,omitempty"`
+	Popup        *UINode  `json:"popup,omitempty" yaml:"popup,omitempty"`
+
+	// TextBox, IntBox, FloatBox, Slider, ProgressBar
+	Value        interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+	DefaultValue interface{} `json:"defaultValue,omitempty" yaml:"defaultValue,omitempty"`
+	Editable     bool        `json:"editable,omitempty" yaml:"editable,omitempty"`
+	Units        string      `json:"units,omitempty" yaml:"units,omitempty"`
+	Format       string      `json:"format,omitempty" yaml:"format,omitempty"`
+	Alignment    string      `json:"alignment,omitempty" yaml:"alignment,omitempty"`
+	Signed       *bool       `json:"signed,omitempty" yaml:"signed,omitempty"`
+	Password     bool        `json:"password,omitempty" yaml:"password,omitempty"`
+	Placeholder  string      `json:"placeholder,omitempty" yaml:"placeholder,omitempty"`
+	Mask         string      `json:"mask,omitempty" yaml:"mask,omitempty"`
+	MaskValue    string      `json:"maskValue,omitempty" yaml:"maskValue,omitempty"`
+
+	// IntBox, FloatBox: numbers are int64 for IntBox and float64 for FloatBox
+	Min       interface{} `json:"min,omitempty" yaml:"min,omitempty"`
+	Max       interface{} `json:"max,omitempty" yaml:"max,omitempty"`
+	Step      interface{} `json:"step,omitempty" yaml:"step,omitempty"`
+	Spinnable bool        `json:"spinnable,omitempty" yaml:"spinnable,omitempty"`
+	Precision *int        `json:"precision,omitempty" yaml:"precision,omitempty"`
+
+	// TextArea
+	Rows int `json:"rows,omitempty" yaml:"rows,omitempty"`
+
+	// CheckBox
+	Checked bool `json:"checked,omitempty" yaml:"checked,omitempty"`
+
+	// ComboBox
+	Items         []string `json:"items,omitempty" yaml:"items,omitempty"`
+	ShortItems    []string `json:"shortItems,omitempty" yaml:"shortItems,omitempty"`
+	SelectedIndex *int     `json:"selectedIndex,omitempty" yaml:"selectedIndex,omitempty"`
+
+	// VScrollPanel
+	Scroll float32 `json:"scroll,omitempty" yaml:"scroll,omitempty"`
+
+	// ImagePanel
+	Images []UIImage `json:"images,omitempty" yaml:"images,omitempty"`
+
+	// ImageView
+	Image       int    `json:"image,omitempty" yaml:"image,omitempty"`
+	ImagePolicy string `json:"imagePolicy,omitempty" yaml:"imagePolicy,omitempty"`
+
+	// ColorWheel, ColorPicker: colors are "#rgb", "#rrggbb" or "#rrggbbaa"
+	Color string `json:"color,omitempty" yaml:"color,omitempty"`
+
+	// Graph
+	Header          string    `json:"header,omitempty" yaml:"header,omitempty"`
+	Footer          string    `json:"footer,omitempty" yaml:"footer,omitempty"`
+	Values          []float32 `json:"values,omitempty" yaml:"values,omitempty"`
+	BackgroundColor string    `json:"backgroundColor,omitempty" yaml:"backgroundColor,omitempty"`
+	ForegroundColor string    `json:"foregroundColor,omitempty" yaml:"foregroundColor,omitempty"`
+	TextColor       string    `json:"textColor,omitempty" yaml:"textColor,omitempty"`
+}
+
+// UIImage is an image of an ImagePanel. ID is a handle of the NanoVG context
+type UIImage struct {
+	ID   int    `json:"id" yaml:"id"`
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// UILayout describes a layout generator
+//
+// Type is one of BoxLayout, GroupLayout, GridLayout, AdvancedGridLayout, FlexLayout and ConstraintLayout.
+type UILayout struct {
+	Type string `json:"type" yaml:"type"`
+
+	// BoxLayout, GridLayout, FlexLayout
+	Orientation string `json:"orientation,omitempty" yaml:"orientation,omitempty"`
+	Alignment   string `json:"alignment,omitempty" yaml:"alignment,omitempty"`
+
+	Margin  *int `json:"margin,omitempty" yaml:"margin,omitempty"`
+	Spacing *int `json:"spacing,omitempty" yaml:"spacing,omitempty"`
+
+	// GroupLayout
+	GroupIndent  *int `json:"groupIndent,omitempty" yaml:"groupIndent,omitempty"`
+	GroupSpacing *int `json:"groupSpacing,omitempty" yaml:"groupSpacing,omitempty"`
+
+	// GridLayout
+	Resolution    int      `json:"resolution,omitempty" yaml:"resolution,omitempty"`
+	ColSpacing    *int     `json:"colSpacing,omitempty" yaml:"colSpacing,omitempty"`
+	RowSpacing    *int     `json:"rowSpacing,omitempty" yaml:"rowSpacing,omitempty"`
+	ColAlignment  string   `json:"colAlignment,omitempty" yaml:"colAlignment,omitempty"`
+	RowAlignment  string   `json:"rowAlignment,omitempty" yaml:"rowAlignment,omitempty"`
+	ColAlignments []string `json:"colAlignments,omitempty" yaml:"colAlignments,omitempty"`
+	RowAlignments []string `json:"rowAlignments,omitempty" yaml:"rowAlignments,omitempty"`
+
+	// AdvancedGridLayout
+	Cols       []int     `json:"cols,omitempty" yaml:"cols,omitempty"`
+	Rows       []int     `json:"rows,omitempty" yaml:"rows,omitempty"`
+	ColStretch []float32 `json:"colStretch,omitempty" yaml:"colStretch,omitempty"`
+	RowStretch []float32 `json:"rowStretch,omitempty" yaml:"rowStretch,omitempty"`
+
+	// FlexLayout
+	Justify     string `json:"justify,omitempty" yaml:"justify,omitempty"`
+	Wrap        bool   `json:"wrap,omitempty" yaml:"wrap,omitempty"`
+	LineSpacing *int   `json:"lineSpacing,omitempty" yaml:"lineSpacing,omitempty"`
+
+	// ConstraintLayout
+	SizeStrength *float64       `json:"sizeStrength,omitempty" yaml:"sizeStrength,omitempty"`
+	Constraints  []UIConstraint `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+}
+
+// UIAnchor describes the cell of a widget in its parent's AdvancedGridLayout
+type UIAnchor struct {
+	Pos   [2]int    `json:"pos" yaml:"pos"`
+	Size  *[2]int   `json:"size,omitempty" yaml:"size,omitempty"`
+	Align [2]string `json:"align,omitempty" yaml:"align,omitempty"`
+}
+
+// UIGridCell describes the cell of a widget in its parent's GridLayout
+//
+// Negative or omitted positions are placed automatically.
+type UIGridCell struct {
+	Pos  *[2]int `json:"pos,omitempty" yaml:"pos,omitempty"`
+	Span *[2]int `json:"span,omitempty" yaml:"span,omitempty"`
+}
+
+// UIFlex describes the FlexItem of a widget in its parent's FlexLayout
+type UIFlex struct {
+	Grow   float32  `json:"grow,omitempty" yaml:"grow,omitempty"`
+	Shrink *float32 `json:"shrink,omitempty" yaml:"shrink,omitempty"`
+	Basis  *int     `json:"basis,omitempty" yaml:"basis,omitempty"`
+	Min    int      `json:"min,omitempty" yaml:"min,omitempty"`
+	Max    int      `json:"max,omitempty" yaml:"max,omitempty"`
+	Align  string   `json:"align,omitempty" yaml:"align,omitempty"`
+}
+
+// UIConstraint describes a constraint of a ConstraintLayout: the sum of the terms and the constant <relation> 0
+//
+// Relation is "==", "<=" or ">=". An omitted strength is StrengthRequired.
+type UIConstraint struct {
+	Terms    []UIConstraintTerm `json:"terms" yaml:"terms"`
+	Constant float64            `json:"constant,omitempty" yaml:"constant,omitempty"`
+	Relation string             `json:"relation" yaml:"relation"`
+	Strength *float64           `json:"strength,omitempty" yaml:"strength,omitempty"`
+}
+
+// UIConstraintTerm is the coefficient times an attribute (Left, Top, Right, Bottom, Width, Height,
+// CenterX or CenterY) of a child. Child is the index in the children of the node; nil stands for the container
+type UIConstraintTerm struct {
+	Child       *int    `json:"child,omitempty" yaml:"child,omitempty"`
+	Attribute   string  `json:"attribute" yaml:"attribute"`
+	Coefficient float64 `json:"coefficient" yaml:"coefficient"`
+}
+
+// UI is a handle to widgets built from a UIDocument
+type UI struct {
+	roots   []Widget
+	widgets map[string]Widget
+}
+
+// LoadUI() reads a JSON UIDocument and builds its widgets under parent
+func LoadUI(parent Widget, r io.Reader) (*UI, error) {
+	var doc UIDocument
+	decoder := json.NewDecoder(r)
+	// numbers are kept as json.Number so that large IntBox values don't lose precision
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("nanogui: can't decode UI document: %v", err)
+	}
+	return BuildUI(parent, &doc)
+}
+
+// BuildUI() builds the widgets described by doc under parent
+//
+// If a node can't be built, the widgets created so far (including popups added to the screen) are removed again.
+func BuildUI(parent Widget, doc *UIDocument) (*UI, error) {
+	ui := &UI{
+		widgets: make(map[string]Widget),
+	}
+	containers := []Widget{parent}
+	if screen := findScreen(parent); screen != nil && Widget(screen) != parent {
+		containers = append(containers, screen)
+	}
+	rollback := removeNewChildren(containers)
+	for _, node := range doc.Widgets {
+		widget, err := ui.build(parent, node)
+		if err != nil {
+			rollback()
+			return nil, err
+		}
+		ui.roots = append(ui.roots, widget)
+	}
+	return ui, nil
+}
+
+// removeNewChildren() returns a function which removes the children added to the containers after this call
+func removeNewChildren(containers []Widget) func() {
+	known := make([]map[Widget]bool, len(containers))
+	for i, container := range containers {
+		known[i] = make(map[Widget]bool)
+		for _, child := range container.Children() {
+			known[i][child] = true
+		}
+	}
+	return func() {
+		for i, container := range containers {
+			for _, child := range append([]Widget(nil), container.Children()...) {
+				if !known[i][child] {
+					container.RemoveChild(child)
+				}
+			}
+		}
+	}
+}
+
+// Roots() returns the widgets created for the top level nodes of the document
+func (u *UI) Roots() []Widget {
+	return u.roots
+}
+
+// Widget() returns the widget created for the node with the given ID
+func (u *UI) Widget(id string) Widget {
+	return u.widgets[id]
+}
+
+// Bind() sets a callback of the widget with the given ID
+//
+// The accepted callback types follow the widgets' SetCallback() methods:
+// func() or func(bool) (change callback) for Button, func(bool) for CheckBox,
+// func(string) bool for TextBox and TextArea, func(int64) for IntBox, func(int) for ComboBox,
+// func(float64) for FloatBox, func(float32) for Slider and func(string) (link callback) for RichLabel.
+func (u *UI) Bind(id string, callback interface{}) error {
+	widget, ok := u.widgets[id]
+	if !ok {
+		return fmt.Errorf("nanogui: no widget with id %q", id)
+	}
+	switch w := widget.(type) {
+	case *ComboBox:
+		if f, ok := callback.(func(int)); ok {
+			w.SetCallback(f)
+			return nil
+		}
+	case *PopupButton:
+		if f, ok := callback.(func(bool)); ok {
+			w.SetChangeCallback(f)
+			return nil
+		}
+	case *Button:
+		switch f := callback.(type) {
+		case func():
+			w.SetCallback(f)
+			return nil
+		case func(bool):
+			w.SetChangeCallback(f)
+			return nil
+		}
+	case *CheckBox:
+		if f, ok := callback.(func(bool)); ok {
+			w.SetCallback(f)
+			return nil
+		}
+	case *IntBox:
+		if f, ok := callback.(func(int64)); ok {
+			w.SetCallback(f)
+			return nil
+		}
+	case *FloatBox:
+		if f, ok := callback.(func(float64)); ok {
+			w.SetCallback(f)
+			return nil
+		}
+	case *TextBox:
+		if f, ok := callback.(func(string) bool); ok {
+			w.SetCallback(f)
+			return nil
+		}
+	case *TextArea:
+		if f, ok := callback.(func(string) bool); ok {
+			w.SetCallback(f)
+			return nil
+		}
+	case *Slider:
+		if f, ok := callback.(func(float32)); ok {
+			w.SetCallback(f)
+			return nil
+		}
+	case *RichLabel:
+		if f, ok := callback.(func(string)); ok {
+			w.SetLinkCallback(f)
+			return nil
+		}
+	}
+	return fmt.Errorf("nanogui: can't bind %T to %s#%s", callback, WidgetTypeName(widget), id)
+}
+
+func (u *UI) build(parent Widget, node *UINode) (Widget, error) {
+	widget, err := u.create(parent, node)
+	if err != nil {
+		return nil, err
+	}
+	if node.ID != "" {
+		if _, exists := u.widgets[node.ID]; exists {
+			return nil, fmt.Errorf("nanogui: duplicated widget id %q", node.ID)
+		}
+		widget.SetID(node.ID)
+		u.widgets[node.ID] = widget
+	}
+	if err := u.applyCommon(widget, node); err != nil {
+		return nil, err
+	}
+	if node.Anchor != nil {
+		layout, ok := parent.Layout().(*AdvancedGridLayout)
+		if !ok {
+			return nil, fmt.Errorf("nanogui: %s has an anchor but its parent doesn't use AdvancedGridLayout", describeNode(node))
+		}
+		anchor, err := buildAnchor(node.Anchor)
+		if err != nil {
+			return nil, err
+		}
+		layout.SetAnchor(widget, anchor)
+	}
+	if node.Cell != nil {
+		layout, ok := parent.Layout().(*GridLayout)
+		if !ok {
+			return nil, fmt.Errorf("nanogui: %s has a cell but its parent doesn't use GridLayout", describeNode(node))
+		}
+		cell, err := buildGridCell(node.Cell, layout)
+		if err != nil {
+			return nil, fmt.Errorf("nanogui: %s: %v", describeNode(node), err)
+		}
+		layout.SetCell(widget, cell)
+	}
+	if node.Flex != nil {
+		layout, ok := parent.Layout().(*FlexLayout)
+		if !ok {
+			return nil, fmt.Errorf("nanogui: %s has a flex item but its parent doesn't use FlexLayout", describeNode(node))
+		}
+		item, err := buildFlexItem(node.Flex)
+		if err != nil {
+			return nil, fmt.Errorf("nanogui: %s: %v", describeNode(node), err)
+		}
+		layout.SetItem(widget, item)
+	}
+	if err := u.buildContent(widget, node); err != nil {
+		return nil, err
+	}
+	if popupButton, ok := widget.(*PopupButton); ok && node.Popup != nil {
+		popup := popupButton.Popup()
+		if err := u.applyCommon(popup, node.Popup); err != nil {
+			return nil, err
+		}
+		if err := u.buildContent(popup, node.Popup); err != nil {
+			return nil, err
+		}
+	}
+	return widget, nil
+}
+
+func (u *UI) buildContent(widget Widget, node *UINode) error {
+	if node.Layout != nil {
+		layout, err := buildLayout(node.Layout)
+		if err != nil {
+			return fmt.Errorf("nanogui: %s: %v", describeNode(node), err)
+		}
+		widget.SetLayout(layout)
+	}
+	var children []Widget
+	for _, child := range node.Children {
+		childWidget, err := u.build(widget, child)
+		if err != nil {
+			return err
+		}
+		children = append(children, childWidget)
+	}
+	// constraints refer to the children, so they are added after building them
+	if layout, ok := widget.Layout().(*ConstraintLayout); ok && node.Layout != nil {
+		var constraints []*Constraint
+		for _, c := range node.Layout.Constraints {
+			constraint, err := buildConstraint(layout, children, c)
+			if err != nil {
+				return fmt.Errorf("nanogui: %s: %v", describeNode(node), err)
+			}
+			constraints = append(constraints, constraint)
+		}
+		if err := layout.AddConstraints(constraints...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *UI) create(parent Widget, node *UINode) (Widget, error) {
+	switch node.Type {
+	case "Widget":
+		return NewWidget(parent), nil
+	case "Window":
+		window := NewWindow(parent, node.Title)
+		window.SetModal(node.Modal)
+		if node.Draggable != nil {
+			window.SetDraggable(*node.Draggable)
+		}
+		return window, nil
+	case "Label":
+		label := NewLabel(parent, node.Caption)
+		if node.ColumnWidth > 0 {
+			label.SetColumnWidth(node.ColumnWidth)
+		}
+		if node.Wrap != nil {
+			label.SetWrap(*node.Wrap)
+		}
+		if node.Direction != "" {
+			direction, err := parseTextDirection(node.Direction)
+			if err != nil {
+				return nil, fmt.Errorf("nanogui: %s: %v", describeNode(node), err)
+			}
+			label.SetTextDirection(direction)
+		}
+		return label, nil
+	case "RichLabel":
+		label := NewRichLabel(parent, "")
+		if err := label.SetMarkup(node.Markup); err != nil {
+			return nil, fmt.Errorf("nanogui: %s: invalid markup: %v", describeNode(node), err)
+		}
+		if node.ColumnWidth > 0 {
+			label.SetColumnWidth(node.ColumnWidth)
+		}
+		if node.Wrap != nil {
+			label.SetWrap(*node.Wrap)
+		}
+		return label, nil
+	case "Button":
+		button := NewButton(parent, node.Caption)
+		if err := applyButton(&button.WidgetImplement, button, node); err != nil {
+			return nil, err
+		}
+		return button, nil
+	case "PopupButton":
+		button := NewPopupButton(parent, node.Caption)
+		if err := applyButton(&button.WidgetImplement, &button.Button, node); err != nil {
+			return nil, err
+		}
+		return button, nil
+	case "TextBox":
+		value, err := nodeString(node, node.Value, "Untitled")
+		if err != nil {
+			return nil, err
+		}
+		textBox := NewTextBox(parent, value)
+		if node.DefaultValue != nil {
+			defaultValue, err := nodeString(node, node.DefaultValue, "")
+			if err != nil {
+				return nil, err
+			}
+			textBox.SetDefaultValue(defaultValue)
+		}
+		if node.Format != "" {
+			if err := textBox.SetFormat(node.Format); err != nil {
+				return nil, fmt.Errorf("nanogui: %s: invalid format: %v", describeNode(node), err)
+			}
+		}
+		if node.Mask != "" {
+			if err := textBox.SetMask(node.Mask); err != nil {
+				return nil, fmt.Errorf("nanogui: %s: invalid mask: %v", describeNode(node), err)
+			}
+		}
+		if node.MaskValue != "" {
+			mode, err := parseMaskValueMode(node.MaskValue)
+			if err != nil {
+				return nil, fmt.Errorf("nanogui: %s: %v", describeNode(node), err)
+			}
+			textBox.SetMaskValueMode(mode)
+		}
+		if err := applyTextBox(textBox, node); err != nil {
+			return nil, err
+		}
+		return textBox, nil
+	case "IntBox":
+		value, err := nodeInt64(node, node.Value)
+		if err != nil {
+			return nil, err
+		}
+		intBox := NewIntBox(parent, node.Signed == nil || *node.Signed, value)
+		if node.DefaultValue != nil {
+			defaultValue, err := nodeInt64(node, node.DefaultValue)
+			if err != nil {
+				return nil, err
+			}
+			intBox.SetDefaultValue(defaultValue)
+		}
+		if err := applyTextBox(&intBox.TextBox, node); err != nil {
+			return nil, err
+		}
+		if node.Min != nil || node.Max != nil {
+			min, max := intBox.Range()
+			if node.Min != nil {
+				if min, err = nodeInt64(node, node.Min); err != nil {
+					return nil, err
+				}
+			}
+			if node.Max != nil {
+				if max, err = nodeInt64(node, node.Max); err != nil {
+					return nil, err
+				}
+			}
+			intBox.SetRange(min, max)
+		}
+		if node.Step != nil {
+			step, err := nodeInt64(node, node.Step)
+			if err != nil {
+				return nil, err
+			}
+			intBox.SetStep(step)
+		}
+		return intBox, nil
+	case "FloatBox":
+		value, err := nodeFloat(node, node.Value)
+		if err != nil {
+			return nil, err
+		}
+		floatBox := NewFloatBox(parent, value)
+		if node.DefaultValue != nil {
+			defaultValue, err := nodeFloat(node, node.DefaultValue)
+			if err != nil {
+				return nil, err
+			}
+			floatBox.SetDefaultValue(defaultValue)
+		}
+		if err := applyTextBox(&floatBox.TextBox, node); err != nil {
+			return nil, err
+		}
+		if node.Precision != nil {
+			floatBox.SetPrecision(*node.Precision)
+		}
+		if node.Min != nil || node.Max != nil {
+			min, max := floatBox.Range()
+			if node.Min != nil {
+				if min, err = nodeFloat(node, node.Min); err != nil {
+					return nil, err
+				}
+			}
+			if node.Max != nil {
+				if max, err = nodeFloat(node, node.Max); err != nil {
+					return nil, err
+				}
+			}
+			floatBox.SetRange(min, max)
+		}
+		if node.Step != nil {
+			step, err := nodeFloat(node, node.Step)
+			if err != nil {
+				return nil, err
+			}
+			floatBox.SetStep(step)
+		}
+		return floatBox, nil
+	case "TextArea":
+		value, err := nodeString(node, node.Value, "")
+		if err != nil {
+			return nil, err
+		}
+		textArea := NewTextArea(parent, value)
+		textArea.SetEditable(node.Editable)
+		if node.Wrap != nil {
+			textArea.SetWrap(*node.Wrap)
+		}
+		if node.Rows > 0 {
+			textArea.SetRows(node.Rows)
+		}
+		return textArea, nil
+	case "CheckBox":
+		checkBox := NewCheckBox(parent, node.Caption)
+		checkBox.SetChecked(node.Checked)
+		return checkBox, nil
+	case "ComboBox":
+		comboBox := NewComboBox(parent, node.Items, node.ShortItems)
+		if node.SelectedIndex != nil {
+			comboBox.SetSelectedIndex(*node.SelectedIndex)
+		}
+		return comboBox, nil
+	case "Slider":
+		value, err := nodeFloat(node, node.Value)
+		if err != nil {
+			return nil, err
+		}
+		slider := NewSlider(parent)
+		slider.SetValue(float32(value))
+		return slider, nil
+	case "ProgressBar":
+		value, err := nodeFloat(node, node.Value)
+		if err != nil {
+			return nil, err
+		}
+		progressBar := NewProgressBar(parent)
+		progressBar.SetValue(float32(value))
+		return progressBar, nil
+	case "VScrollPanel":
+		panel := NewVScrollPanel(parent)
+		panel.SetScroll(node.Scroll)
+		return panel, nil
+	case "ImagePanel":
+		panel := NewImagePanel(parent)
+		var images []Image
+		for _, image := range node.Images {
+			images = append(images, Image{ImageID: image.ID, Name: image.Name})
+		}
+		panel.SetImages(images)
+		return panel, nil
+	case "ImageView":
+		policy, err := parseImageSizePolicy(node.ImagePolicy)
+		if err != nil {
+			return nil, fmt.Errorf("nanogui: %s: %v", describeNode(node), err)
+		}
+		imageView := NewImageView(parent, node.Image)
+		imageView.SetPolicy(policy)
+		return imageView, nil
+	case "ColorWheel":
+		colorWheel := NewColorWheel(parent)
+		if err := applyColor(node, node.Color, colorWheel.SetColor); err != nil {
+			return nil, err
+		}
+		return colorWheel, nil
+	case "ColorPicker":
+		colorPicker := NewColorPicker(parent)
+		if err := applyColor(node, node.Color, colorPicker.SetColor); err != nil {
+			return nil, err
+		}
+		return colorPicker, nil
+	case "Graph":
+		graph := NewGraph(parent, node.Caption)
+		graph.SetHeader(node.Header)
+		graph.SetFooter(node.Footer)
+		graph.SetValues(node.Values)
+		for _, color := range []struct {
+			value string
+			set   func(nanovgo.Color)
+		}{
+			{node.BackgroundColor, graph.SetBackgroundColor},
+			{node.ForegroundColor, graph.SetForegroundColor},
+			{node.TextColor, graph.SetTextColor},
+		} {
+			if err := applyColor(node, color.value, color.set); err != nil {
+				return nil, err
+			}
+		}
+		return graph, nil
+	}
+	return nil, fmt.Errorf("nanogui: unknown widget type %q", node.Type)
+}
+
+func (u *UI) applyCommon(widget Widget, node *UINode) error {
+	if node.Position != nil {
+		widget.SetPosition(node.Position[0], node.Position[1])
+	}
+	if node.Size != nil {
+		widget.SetSize(node.Size[0], node.Size[1])
+	}
+	if node.FixedSize != nil {
+		widget.SetFixedSize(node.FixedSize[0], node.FixedSize[1])
+	}
+	if node.MinimumSize != nil {
+		widget.SetMinimumSize(node.MinimumSize[0], node.MinimumSize[1])
+	}
+	if node.MaximumSize != nil {
+		widget.SetMaximumSize(node.MaximumSize[0], node.MaximumSize[1])
+	}
+	if node.SizePolicy != nil {
+		var policies [2]SizePolicy
+		for i, name := range node.SizePolicy {
+			policy, err := parseSizePolicy(name)
+			if err != nil {
+				return fmt.Errorf("nanogui: %s: %v", describeNode(node), err)
+			}
+			policies[i] = policy
+		}
+		widget.SetSizePolicy(policies[0], policies[1])
+	}
+	if node.Visible != nil {
+		widget.SetVisible(*node.Visible)
+	}
+	if node.Enabled != nil {
+		widget.SetEnabled(*node.Enabled)
+	}
+	if node.Tooltip != "" {
+		widget.SetTooltip(node.Tooltip)
+	}
+	if node.FontSize > 0 {
+		widget.SetFontSize(node.FontSize)
+	}
+	if node.Font != "" {
+		fontWidget, ok := widget.(interface {
+			SetFont(string)
+		})
+		if !ok {
+			return fmt.Errorf("nanogui: %s doesn't support font", describeNode(node))
+		}
+		fontWidget.SetFont(node.Font)
+	}
+	return nil
+}
+
+func applyButton(w *WidgetImplement, button *Button, node *UINode) error {
+	if len(node.Flags) > 0 {
+		var flags ButtonFlags
+		for _, name := range node.Flags {
+			flag, err := parseButtonFlag(name)
+			if err != nil {
+				return fmt.Errorf("nanogui: %s: %v", describeNode(node), err)
+			}
+			flags |= flag
+		}
+		if button.Flags()&PopupButtonType != 0 {
+			flags |= PopupButtonType
+		}
+		button.SetFlags(flags)
+	}
+	if node.Icon != 0 {
+		button.SetIcon(node.Icon)
+	}
+	if node.IconPosition != "" {
+		position, err := parseButtonIconPosition(node.IconPosition)
+		if err != nil {
+			return fmt.Errorf("nanogui: %s: %v", describeNode(node), err)
+		}
+		button.SetIconPosition(position)
+	}
+	button.SetPushed(node.Pushed)
+	return nil
+}
+
+func applyTextBox(textBox *TextBox, node *UINode) error {
+	textBox.SetEditable(node.Editable)
+	textBox.SetUnits(node.Units)
+	textBox.SetPassword(node.Password)
+	textBox.SetPlaceholder(node.Placeholder)
+	textBox.SetSpinnable(node.Spinnable)
+	if node.Alignment != "" {
+		alignment, err := parseTextAlignment(node.Alignment)
+		if err != nil {
+			return fmt.Errorf("nanogui: %s: %v", describeNode(node), err)
+		}
+		textBox.SetAlignment(alignment)
+	}
+	if node.Direction != "" {
+		direction, err := parseTextDirection(node.Direction)
+		if err != nil {
+			return fmt.Errorf("nanogui: %s: %v", describeNode(node), err)
+		}
+		textBox.SetTextDirection(direction)
+	}
+	return nil
+}
+
+func buildLayout(l *UILayout) (Layout, error) {
+	intValue := func(v *int, defaultValue int) int {
+		if v == nil {
+			return defaultValue
+		}
+		return *v
+	}
+	switch l.Type {
+	case "BoxLayout":
+		orientation, err := parseOrientation(l.Orientation)
+		if err != nil {
+			return nil, err
+		}
+		alignment, err := parseAlignment(l.Alignment, Middle)
+		if err != nil {
+			return nil, err
+		}
+		return NewBoxLayout(orientation, alignment, intValue(l.Margin, 0), intValue(l.Spacing, 0)), nil
+	case "GroupLayout":
+		return NewGroupLayout(intValue(l.Margin, -1), intValue(l.Spacing, -1), intValue(l.GroupIndent, -1), intValue(l.GroupSpacing, -1)), nil
+	case "GridLayout":
+		orientation, err := parseOrientation(l.Orientation)
+		if err != nil {
+			return nil, err
+		}
+		alignment, err := parseAlignment(l.Alignment, Middle)
+		if err != nil {
+			return nil, err
+		}
+		resolution := l.Resolution
+		if resolution == 0 {
+			resolution = 2
+		}
+		layout := NewGridLayout(orientation, resolution, alignment, intValue(l.Margin, 0), intValue(l.Spacing, 0))
+		if l.ColSpacing != nil {
+			layout.SetColSpacing(*l.ColSpacing)
+		}
+		if l.RowSpacing != nil {
+			layout.SetRowSpacing(*l.RowSpacing)
+		}
+		if l.ColAlignment != "" {
+			a, err := parseAlignment(l.ColAlignment, Middle)
+			if err != nil {
+				return nil, err
+			}
+			layout.SetColDefaultAlignment(a)
+		}
+		if l.RowAlignment != "" {
+			a, err := parseAlignment(l.RowAlignment, Middle)
+			if err != nil {
+				return nil, err
+			}
+			layout.SetRowDefaultAlignment(a)
+		}
+		colAlignments, err := parseAlignments(l.ColAlignments)
+		if err != nil {
+			return nil, err
+		}
+		layout.SetColAlignment(colAlignments...)
+		rowAlignments, err := parseAlignments(l.RowAlignments)
+		if err != nil {
+			return nil, err
+		}
+		layout.SetRowAlignment(rowAlignments...)
+		return layout, nil
+	case "AdvancedGridLayout":
+		layout := NewAdvancedGridLayout(append([]int{}, l.Cols...), append([]int{}, l.Rows...))
+		layout.SetMargin(intValue(l.Margin, 0))
+		for i, stretch := range l.ColStretch {
+			if i >= layout.ColCount() {
+				return nil, fmt.Errorf("too many colStretch values")
+			}
+			layout.SetColStretch(i, stretch)
+		}
+		for i, stretch := range l.RowStretch {
+			if i >= layout.RowCount() {
+				return nil, fmt.Errorf("too many rowStretch values")
+			}
+			layout.SetRowStretch(i, stretch)
+		}
+		return layout, nil
+	case "FlexLayout":
+		orientation, err := parseOrientation(l.Orientation)
+		if err != nil {
+			return nil, err
+		}
+		justify, err := parseFlexJustify(l.Justify)
+		if err != nil {
+			return nil, err
+		}
+		alignment, err := parseAlignment(l.Alignment, Fill)
+		if err != nil {
+			return nil, err
+		}
+		spacing := intValue(l.Spacing, 0)
+		layout := NewFlexLayout(orientation, justify, alignment, intValue(l.Margin, 0), spacing, intValue(l.LineSpacing, spacing))
+		layout.SetWrap(l.Wrap)
+		return layout, nil
+	case "ConstraintLayout":
+		layout := NewConstraintLayout()
+		if l.SizeStrength != nil {
+			layout.SetSizeStrength(*l.SizeStrength)
+		}
+		return layout, nil
+	}
+	return nil, fmt.Errorf("unknown layout type %q", l.Type)
+}
+
+func buildConstraint(layout *ConstraintLayout, children []Widget, c UIConstraint) (*Constraint, error) {
+	expression := Constant(c.Constant)
+	for _, term := range c.Terms {
+		var widget Widget
+		if term.Child != nil {
+			if *term.Child < 0 || *term.Child >= len(children) {
+				return nil, fmt.Errorf("constraint refers to child %d, but there are %d children", *term.Child, len(children))
+			}
+			widget = children[*term.Child]
+		}
+		attribute, err := parseConstraintAttribute(term.Attribute)
+		if err != nil {
+			return nil, err
+		}
+		expression = expression.Plus(layout.Anchor(widget, attribute).Times(term.Coefficient))
+	}
+	relation, err := parseRelation(c.Relation)
+	if err != nil {
+		return nil, err
+	}
+	strength := StrengthRequired
+	if c.Strength != nil {
+		strength = *c.Strength
+	}
+	return NewConstraint(expression, relation, Constant(0), strength), nil
+}
+
+func buildAnchor(a *UIAnchor) (Anchor, error) {
+	size := [2]int{1, 1}
+	if a.Size != nil {
+		size = *a.Size
+	}
+	var aligns [2]Alignment
+	for i, name := range a.Align {
+		align, err := parseAlignment(name, Fill)
+		if err != nil {
+			return Anchor{}, err
+		}
+		aligns[i] = align
+	}
+	return NewAnchorWithSize(a.Pos[0], a.Pos[1], size[0], size[1], aligns[0], aligns[1]), nil
+}
+
+func buildGridCell(c *UIGridCell, layout *GridLayout) (GridCell, error) {
+	pos := [2]int{-1, -1}
+	if c.Pos != nil {
+		pos = *c.Pos
+	}
+	span := [2]int{1, 1}
+	if c.Span != nil {
+		span = *c.Span
+	}
+	if span[0] < 1 || span[1] < 1 {
+		return GridCell{}, fmt.Errorf("cell span should be positive: %v", span)
+	}
+	axis := int(layout.Orientation())
+	if span[axis] > layout.Resolution() || pos[axis]+span[axis] > layout.Resolution() {
+		return GridCell{}, fmt.Errorf("cell is out of the grid resolution %d: pos=%v, span=%v", layout.Resolution(), pos, span)
+	}
+	return NewGridCell(pos[0], pos[1], span[0], span[1]), nil
+}
+
+func buildFlexItem(f *UIFlex) (FlexItem, error) {
+	shrink := float32(1)
+	if f.Shrink != nil {
+		shrink = *f.Shrink
+	}
+	basis := -1
+	if f.Basis != nil {
+		basis = *f.Basis
+	}
+	item := NewFlexItem(f.Grow, shrink, basis, f.Min, f.Max)
+	if f.Align != "" {
+		align, err := parseAlignment(f.Align, Fill)
+		if err != nil {
+			return FlexItem{}, err
+		}
+		item.SetAlignment(align)
+	}
+	return item, nil
+}
+
+// applyColor() parses a color of a node and sets it unless it is omitted
+func applyColor(node *UINode, value string, set func(nanovgo.Color)) error {
+	if value == "" {
+		return nil
+	}
+	color, err := parseColor(value)
+	if err != nil {
+		return fmt.Errorf("nanogui: %s: invalid color %q", describeNode(node), value)
+	}
+	set(color)
+	return nil
+}
+
+func describeNode(node *UINode) string {
+	if node.ID != "" {
+		return node.Type + "#" + node.ID
+	}
+	return node.Type
+}
+
+func nodeString(node *UINode, value interface{}, defaultValue string) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return defaultValue, nil
+	case string:
+		return v, nil
+	case float64, int, int64, json.Number:
+		return fmt.Sprint(v), nil
+	}
+	return "", fmt.Errorf("nanogui: %s: value should be a string: %v", describeNode(node), value)
+}
+
+func nodeFloat(node *UINode, value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("nanogui: %s: value should be a number: %v", describeNode(node), value)
+}
+
+func nodeInt64(node *UINode, value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+	}
+	f, err := nodeFloat(node, value)
+	return int64(f), err
+}
+
+func parseOrientation(name string) (Orientation, error) {
+	switch strings.ToLower(name) {
+	case "", "horizontal":
+		return Horizontal, nil
+	case "vertical":
+		return Vertical, nil
+	}
+	return Horizontal, fmt.Errorf("unknown orientation %q", name)
+}
+
+func parseFlexJustify(name string) (FlexJustify, error) {
+	switch strings.ToLower(name) {
+	case "", "start":
+		return JustifyStart, nil
+	case "center":
+		return JustifyCenter, nil
+	case "end":
+		return JustifyEnd, nil
+	case "spacebetween", "space-between":
+		return JustifySpaceBetween, nil
+	case "spacearound", "space-around":
+		return JustifySpaceAround, nil
+	}
+	return JustifyStart, fmt.Errorf("unknown justify %q", name)
+}
+
+func parseAlignment(name string, defaultValue Alignment) (Alignment, error) {
+	switch strings.ToLower(name) {
+	case "":
+		return defaultValue, nil
+	case "middle":
+		return Middle, nil
+	case "minimum":
+		return Minimum, nil
+	case "maximum":
+		return Maximum, nil
+	case "fill":
+		return Fill, nil
+	}
+	return defaultValue, fmt.Errorf("unknown alignment %q", name)
+}
+
+// parseColor() parses "#rgb", "#rrggbb" and "#rrggbbaa"
+func parseColor(value string) (nanovgo.Color, error) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	c, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 8 || !strings.HasPrefix(value, "#") || err != nil {
+		return nanovgo.Color{}, fmt.Errorf("invalid color %q", value)
+	}
+	return nanovgo.RGBA(uint8(c>>24), uint8(c>>16), uint8(c>>8), uint8(c)), nil
+}
+
+func parseImageSizePolicy(name string) (ImageSizePolicy, error) {
+	switch strings.ToLower(name) {
+	case "", "fixed":
+		return ImageSizePolicyFixed, nil
+	case "expand":
+		return ImageSizePolicyExpand, nil
+	}
+	return ImageSizePolicyFixed, fmt.Errorf("unknown image size policy %q", name)
+}
+
+func parseConstraintAttribute(name string) (ConstraintAttribute, error) {
+	for attribute := AttributeLeft; attribute <= AttributeCenterY; attribute++ {
+		if strings.EqualFold(name, attribute.String()) {
+			return attribute, nil
+		}
+	}
+	return AttributeLeft, fmt.Errorf("unknown constraint attribute %q", name)
+}
+
+func parseRelation(name string) (Relation, error) {
+	switch name {
+	case "==":
+		return RelationEqual, nil
+	case "<=":
+		return RelationLessOrEqual, nil
+	case ">=":
+		return RelationGreaterOrEqual, nil
+	}
+	return RelationEqual, fmt.Errorf("unknown relation %q", name)
+}
+
+func parseSizePolicy(name string) (SizePolicy, error) {
+	switch strings.ToLower(name) {
+	case "", "preferred":
+		return SizePreferred, nil
+	case "fixed":
+		return SizeFixed, nil
+	case "minimum":
+		return SizeMinimum, nil
+	case "maximum":
+		return SizeMaximum, nil
+	case "expanding":
+		return SizeExpanding, nil
+	}
+	return SizePreferred, fmt.Errorf("unknown size policy %q", name)
+}
+
+func parseAlignments(names []string) ([]Alignment, error) {
+	alignments := make([]Alignment, len(names))
+	for i, name := range names {
+		alignment, err := parseAlignment(name, Middle)
+		if err != nil {
+			return nil, err
+		}
+		alignments[i] = alignment
+	}
+	return alignments, nil
+}
+
+func parseTextAlignment(name string) (TextAlignment, error) {
+	switch strings.ToLower(name) {
+	case "center":
+		return TextCenter, nil
+	case "left":
+		return TextLeft, nil
+	case "right":
+		return TextRight, nil
+	}
+	return TextCenter, fmt.Errorf("unknown text alignment %q", name)
+}
+
+func parseTextDirection(name string) (TextDirection, error) {
+	switch strings.ToLower(name) {
+	case "auto":
+		return TextDirectionAuto, nil
+	case "ltr":
+		return TextDirectionLTR, nil
+	case "rtl":
+		return TextDirectionRTL, nil
+	}
+	return TextDirectionAuto, fmt.Errorf("unknown text direction %q", name)
+}
+
+func parseMaskValueMode(name string) (MaskValueMode, error) {
+	switch strings.ToLower(name) {
+	case "formatted":
+		return MaskValueFormatted, nil
+	case "raw":
+		return MaskValueRaw, nil
+	}
+	return MaskValueFormatted, fmt.Errorf("unknown mask value mode %q", name)
+}
+
+func parseButtonFlag(name string) (ButtonFlags, error) {
+	switch strings.ToLower(name) {
+	case "normal":
+		return NormalButtonType, nil
+	case "radio":
+		return RadioButtonType, nil
+	case "toggle":
+		return ToggleButtonType, nil
+	case "popup":
+		return PopupButtonType, nil
+	}
+	return 0, fmt.Errorf("unknown button flag %q", name)
+}
+
+func parseButtonIconPosition(name string) (ButtonIconPosition, error) {
+	switch strings.ToLower(name) {
+	case "left":
+		return ButtonIconLeft, nil
+	case "leftcentered":
+		return ButtonIconLeftCentered, nil
+	case "right":
+		return ButtonIconRight, nil
+	case "rightcentered":
+		return ButtonIconRightCentered, nil
+	}
+	return ButtonIconLeftCentered, fmt.Errorf("unknown icon position %q", name)
+}
@@ -0,0 +1,72 @@
+package nanogui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadUI(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	doc := `{"widgets": [{"type": "Window", "id": "settings", "title": "Settings", "position": [10, 10],
+		"layout": {"type": "AdvancedGridLayout", "cols": [10, 0, 10], "rows": [0, 5, 0, 5, 0], "colStretch": [0, 1, 0]},
+		"children": [
+			{"type": "Label", "caption": "Port", "font": "sans-bold", "anchor": {"pos": [1, 0]}},
+			{"type": "IntBox", "id": "port", "value": 8080, "editable": true, "anchor": {"pos": [1, 2], "align": ["fill", "middle"]}},
+			{"type": "Button", "id": "ok", "caption": "OK", "anchor": {"pos": [1, 4]}}
+		]}]}`
+	ui, err := LoadUI(screen, strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title := ui.Widget("settings").(*Window).Title(); title != "Settings" {
+		t.Errorf("Title() = %q, want %q", title, "Settings")
+	}
+	if value := ui.Widget("port").(*IntBox).Value(); value != 8080 {
+		t.Errorf("Value() = %d, want 8080", value)
+	}
+
+	clicked := false
+	if err := ui.Bind("ok", func() { clicked = true }); err != nil {
+		t.Fatal(err)
+	}
+	if err := ui.Bind("port", func(bool) {}); err == nil {
+		t.Error("Bind() should reject a callback type the widget doesn't accept")
+	}
+	screen.PerformLayout()
+	screen.DrawAll()
+	x, y := ui.Widget("ok").AbsolutePosition()
+	screen.Input().ClickAt(x+5, y+5)
+	if !clicked {
+		t.Error("the bound callback isn't called")
+	}
+}
+
+func TestLoadUIErrors(t *testing.T) {
+	for _, doc := range []string{
+		`{"widgets": [{"type": "NoSuchWidget"}]}`,
+		`{"widgets": [{"type": "Window", "layout": {"type": "NoSuchLayout"}}]}`,
+		`{"widgets": [{"type": "Label", "id": "a"}, {"type": "Label", "id": "a"}]}`,
+	} {
+		if _, err := LoadUI(NewHeadlessScreen(800, 600), strings.NewReader(doc)); err == nil {
+			t.Errorf("LoadUI(%s) should fail", doc)
+		}
+	}
+}
+
+func TestLoadUIRemovesWidgetsOnError(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	existing := NewWindow(screen, "Existing")
+	doc := `{"widgets": [
+		{"type": "Window", "title": "First"},
+		{"type": "Window", "title": "Second", "layout": {"type": "GroupLayout"}, "children": [
+			{"type": "Label", "caption": "Port"},
+			{"type": "PopupButton", "caption": "More"},
+			{"type": "Bogus"}
+		]}]}`
+	if _, err := LoadUI(screen, strings.NewReader(doc)); err == nil {
+		t.Fatal("LoadUI() should fail on an unknown widget type")
+	}
+	if screen.ChildCount() != 1 || screen.Children()[0] != existing {
+		t.Errorf("the screen keeps %d children after a failed load, want only the existing window", screen.ChildCount())
+	}
+}
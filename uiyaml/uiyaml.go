@@ -0,0 +1,35 @@
+// Package uiyaml reads and writes nanogui UI documents in YAML
+//
+// It is a separate package so that only the users of YAML depend on gopkg.in/yaml.v3.
+package uiyaml
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/shibukawa/nanogui.go"
+	"gopkg.in/yaml.v3"
+)
+
+// Load() reads a YAML UIDocument and builds its widgets under parent
+func Load(parent nanogui.Widget, r io.Reader) (*nanogui.UI, error) {
+	var doc nanogui.UIDocument
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("nanogui: can't decode UI document: %v", err)
+	}
+	return nanogui.BuildUI(parent, &doc)
+}
+
+// Write() writes the widget tree under root as a YAML UIDocument
+func Write(w io.Writer, root nanogui.Widget) error {
+	doc, err := nanogui.ExportUI(root)
+	if err != nil {
+		return err
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,62 @@
+package uiyaml_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/shibukawa/nanogui.go"
+	"github.com/shibukawa/nanogui.go/uiyaml"
+)
+
+func TestLoad(t *testing.T) {
+	screen := nanogui.NewHeadlessScreen(800, 600)
+	doc := `
+widgets:
+  - type: Window
+    id: settings
+    title: Settings
+    layout: {type: GroupLayout}
+    children:
+      - {type: IntBox, id: port, value: 8080, fixedSize: [100, 0]}
+      - {type: FloatBox, id: ratio, value: 1.5}
+`
+	ui, err := uiyaml.Load(screen, strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value := ui.Widget("port").(*nanogui.IntBox).Value(); value != 8080 {
+		t.Errorf("Value() = %d, want 8080", value)
+	}
+	if value := ui.Widget("ratio").(*nanogui.FloatBox).Value(); value != 1.5 {
+		t.Errorf("Value() = %v, want 1.5", value)
+	}
+	if w, _ := ui.Widget("port").FixedSize(); w != 100 {
+		t.Errorf("fixed width = %d, want 100", w)
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	screen := nanogui.NewHeadlessScreen(800, 600)
+	window := nanogui.NewWindow(screen, "Main")
+	window.SetID("main")
+	window.SetLayout(nanogui.NewGroupLayout())
+	nanogui.NewIntBox(window, false, 42).SetID("port")
+	nanogui.NewComboBox(window, []string{"a", "b"}).SetSelectedIndex(1)
+	screen.PerformLayout()
+
+	var exported bytes.Buffer
+	if err := uiyaml.Write(&exported, window); err != nil {
+		t.Fatal(err)
+	}
+	ui, err := uiyaml.Load(nanogui.NewHeadlessScreen(800, 600), &exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title := ui.Widget("main").(*nanogui.Window).Title(); title != "Main" {
+		t.Errorf("Title() = %q, want %q", title, "Main")
+	}
+	if value := ui.Widget("port").(*nanogui.IntBox).Value(); value != 42 {
+		t.Errorf("Value() = %d, want 42", value)
+	}
+}
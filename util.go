@@ -16,6 +16,13 @@ func maxF(a, b float32) float32 {
 	return b
 }
 
+func minI(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
 func maxI(a, b int) int {
 	if a > b {
 		return a
@@ -61,6 +68,29 @@ func toF(condition bool, a, b float32) float32 {
 	return b
 }
 
+func toF64(condition bool, a, b float64) float64 {
+	if condition {
+		return a
+	}
+	return b
+}
+
+func absI(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func signI(a int) int {
+	if a < 0 {
+		return -1
+	} else if a > 0 {
+		return 1
+	}
+	return 0
+}
+
 func maxFs(v float32, values ...float32) float32 {
 	max := v
 	for _, value := range values {
@@ -28,19 +28,20 @@ func (v *VScrollPanel) SetScroll(scroll float32) {
 	v.scroll = scroll
 }
 
-func (v *VScrollPanel) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+func (v *VScrollPanel) OnPerformLayout(self Widget, ctx DrawContext) {
 	v.WidgetImplement.OnPerformLayout(self, ctx)
 
 	if len(v.children) == 0 {
 		return
 	}
 	child := v.children[0]
-	_, v.childPreferredHeight = child.PreferredSize(child, ctx)
+	_, v.childPreferredHeight = child.CachedPreferredSize(child, ctx)
 	child.SetPosition(0, 0)
 	child.SetSize(v.w, v.childPreferredHeight)
+	child.LayoutIfNeeded(child, ctx)
 }
 
-func (v *VScrollPanel) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+func (v *VScrollPanel) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	if len(v.children) == 0 {
 		return 0, 0
 	}
@@ -50,7 +51,7 @@ func (v *VScrollPanel) PreferredSize(self Widget, ctx *nanovgo.Context) (int, in
 		return w + 12, h
 	}
 	child := v.children[0]
-	w, h := child.PreferredSize(child, ctx)
+	w, h := child.CachedPreferredSize(child, ctx)
 	return w + 12, h
 }
 
@@ -106,7 +107,7 @@ func (v *VScrollPanel) MouseMotionEvent(self Widget, x, y, relX, relY, button in
 	return child.MouseMotionEvent(child, x, y+shift, relX, relY, button, modifier)
 }
 
-func (v *VScrollPanel) Draw(self Widget, ctx *nanovgo.Context) {
+func (v *VScrollPanel) Draw(self Widget, ctx DrawContext) {
 	if len(v.children) == 0 {
 		return
 	}
@@ -39,6 +39,12 @@ type Widget interface {
 	SetFixedWidth(w int)
 	FixedHeight() int
 	SetFixedHeight(h int)
+	MinimumSize() (int, int)
+	SetMinimumSize(w, h int)
+	MaximumSize() (int, int)
+	SetMaximumSize(w, h int)
+	SizePolicy() (SizePolicy, SizePolicy)
+	SetSizePolicy(horizontal, vertical SizePolicy)
 	Clamp() [2]bool
 	SetClampWidth(clamp bool)
 	SetClampHeight(clamp bool)
@@ -65,6 +71,10 @@ type Widget interface {
 	Focused() bool
 	SetFocused(f bool)
 	RequestFocus(self Widget)
+	TabIndex() int
+	SetTabIndex(index int)
+	TabStop() bool
+	SetTabStop(stop bool)
 
 	Tooltip() string
 	SetTooltip(s string)
@@ -91,9 +101,13 @@ type Widget interface {
 	IMEPreeditEvent(self Widget, text []rune, blocks []int, focusedBlock int) bool
 	IMEStatusEvent(self Widget) bool
 
-	PreferredSize(self Widget, ctx *nanovgo.Context) (int, int)
-	OnPerformLayout(self Widget, ctx *nanovgo.Context)
-	Draw(self Widget, ctx *nanovgo.Context)
+	PreferredSize(self Widget, ctx DrawContext) (int, int)
+	CachedPreferredSize(self Widget, ctx DrawContext) (int, int)
+	OnPerformLayout(self Widget, ctx DrawContext)
+	LayoutIfNeeded(self Widget, ctx DrawContext)
+	InvalidateLayout()
+	NeedsLayout() bool
+	Draw(self Widget, ctx DrawContext)
 	Depth() int
 
 	String() string
@@ -104,9 +118,16 @@ type WidgetImplement struct {
 	layout                     Layout
 	theme                      *Theme
 	x, y, w, h, fixedW, fixedH int
-	clamp                      [2]bool
+	minW, minH, maxW, maxH     int
+	sizePolicy                 [2]SizePolicy
+	layoutDirty                bool
+	preferredValid             bool
+	preferredW, preferredH     int
+	laidOutW, laidOutH         int
 	visible, enabled           bool
 	focused, mouseFocus        bool
+	tabStop                    bool
+	tabIndex                   int
 	id                         string
 	tooltip                    string
 	fontSize                   int
@@ -138,6 +159,7 @@ func (w *WidgetImplement) Layout() Layout {
 // SetLayout() set the used layout generator
 func (w *WidgetImplement) SetLayout(layout Layout) {
 	w.layout = layout
+	w.InvalidateLayout()
 }
 
 // Theme() returns the theme used to draw this widget
@@ -147,7 +169,10 @@ func (w *WidgetImplement) Theme() *Theme {
 
 // SetTheme() set the theme used to draw this widget
 func (w *WidgetImplement) SetTheme(theme *Theme) {
-	w.theme = theme
+	if w.theme != theme {
+		w.theme = theme
+		w.InvalidateLayout()
+	}
 }
 
 // Position() returns the position relative to the parent widget
@@ -218,8 +243,11 @@ func (w *WidgetImplement) FixedSize() (int, int) {
 // size; this is done with a call to \ref SetSize or a call to PerformLayout()
 // in the parent widget.
 func (wg *WidgetImplement) SetFixedSize(w, h int) {
-	wg.fixedW = w
-	wg.fixedH = h
+	if wg.fixedW != w || wg.fixedH != h {
+		wg.fixedW = w
+		wg.fixedH = h
+		wg.InvalidateLayout()
+	}
 }
 
 // FixedWidth() returns the fixed width (see SetFixedSize())
@@ -234,27 +262,70 @@ func (w *WidgetImplement) FixedHeight() int {
 
 // SetFixedWidth() set the fixed width (see SetFixedSize())
 func (wg *WidgetImplement) SetFixedWidth(w int) {
-	wg.fixedW = w
+	wg.SetFixedSize(w, wg.fixedH)
 }
 
 // SetFixedSize() set the fixed height (see SetFixedSize())
 func (w *WidgetImplement) SetFixedHeight(h int) {
-	w.fixedH = h
+	w.SetFixedSize(w.fixedW, h)
+}
+
+// MinimumSize() returns the minimum size (see SetMinimumSize())
+func (w *WidgetImplement) MinimumSize() (int, int) {
+	return w.minW, w.minH
+}
+
+// SetMinimumSize() sets the size which layouts don't shrink this widget below.
+// Zero components are unbounded. The fixed size takes priority over it.
+func (wg *WidgetImplement) SetMinimumSize(w, h int) {
+	if wg.minW != w || wg.minH != h {
+		wg.minW = w
+		wg.minH = h
+		wg.InvalidateLayout()
+	}
 }
 
-// Clamp() returns whether preferred size is used as fixed size
+// MaximumSize() returns the maximum size (see SetMaximumSize())
+func (w *WidgetImplement) MaximumSize() (int, int) {
+	return w.maxW, w.maxH
+}
+
+// SetMaximumSize() sets the size which layouts don't grow this widget beyond.
+// Zero components are unbounded. The fixed size takes priority over it.
+func (wg *WidgetImplement) SetMaximumSize(w, h int) {
+	if wg.maxW != w || wg.maxH != h {
+		wg.maxW = w
+		wg.maxH = h
+		wg.InvalidateLayout()
+	}
+}
+
+// SizePolicy() returns the horizontal and the vertical size policy (see SetSizePolicy())
+func (w *WidgetImplement) SizePolicy() (SizePolicy, SizePolicy) {
+	return w.sizePolicy[0], w.sizePolicy[1]
+}
+
+// SetSizePolicy() sets how layouts may resize this widget from its preferred size (default: SizePreferred)
+func (w *WidgetImplement) SetSizePolicy(horizontal, vertical SizePolicy) {
+	if w.sizePolicy != [2]SizePolicy{horizontal, vertical} {
+		w.sizePolicy = [2]SizePolicy{horizontal, vertical}
+		w.InvalidateLayout()
+	}
+}
+
+// Clamp() returns whether preferred size is used as fixed size (SizeFixed policy)
 func (w *WidgetImplement) Clamp() [2]bool {
-	return w.clamp
+	return [2]bool{w.sizePolicy[0] == SizeFixed, w.sizePolicy[1] == SizeFixed}
 }
 
-// SetClampWidth() set the preferred width as fixed width
+// SetClampWidth() set the preferred width as fixed width. It is a shortcut of the SizeFixed horizontal policy
 func (w *WidgetImplement) SetClampWidth(clamp bool) {
-	w.clamp[0] = clamp
+	w.SetSizePolicy(toPolicy(clamp, SizeFixed, SizePreferred), w.sizePolicy[1])
 }
 
-// SetClampHeight() set the preferred height as fixed height
+// SetClampHeight() set the preferred height as fixed height. It is a shortcut of the SizeFixed vertical policy
 func (w *WidgetImplement) SetClampHeight(clamp bool) {
-	w.clamp[1] = clamp
+	w.SetSizePolicy(w.sizePolicy[0], toPolicy(clamp, SizeFixed, SizePreferred))
 }
 
 // Visible() returns whether or not the widget is currently visible (assuming all parents are visible)
@@ -264,7 +335,14 @@ func (w *WidgetImplement) Visible() bool {
 
 // SetVisible() set whether or not the widget is currently visible (assuming all parents are visible)
 func (w *WidgetImplement) SetVisible(v bool) {
-	w.visible = v
+	if w.visible != v {
+		w.visible = v
+		w.InvalidateLayout()
+		// layouts skip hidden widgets, so the parent may be laid out while this widget stays invalid
+		if w.parent != nil {
+			w.parent.InvalidateLayout()
+		}
+	}
 }
 
 // VisibleRecursive() checks if this widget is currently visible, taking parent widgets into account
@@ -287,6 +365,7 @@ func (w *WidgetImplement) Children() []Widget {
 
 func (w *WidgetImplement) SetChildren(children []Widget) {
 	w.children = children
+	w.InvalidateLayout()
 }
 
 // AddChild() adds a child widget to the current widget
@@ -296,6 +375,7 @@ func (w *WidgetImplement) SetChildren(children []Widget) {
 func (w *WidgetImplement) AddChild(self, child Widget) {
 	w.children = append(w.children, child)
 	child.SetParent(self)
+	w.InvalidateLayout()
 }
 
 // RemoveChildByIndex() removes a child widget by index
@@ -311,6 +391,7 @@ func (w *WidgetImplement) RemoveChildByIndex(index int) {
 		}
 	}
 	w.children = newChildren
+	w.InvalidateLayout()
 }
 
 // RemoveChild() removes a child widget by value
@@ -374,6 +455,30 @@ func (w *WidgetImplement) RequestFocus(self Widget) {
 	screen.UpdateFocus(self)
 }
 
+// TabIndex() returns the explicit position of this widget in the keyboard focus order
+func (w *WidgetImplement) TabIndex() int {
+	return w.tabIndex
+}
+
+// SetTabIndex() sets the explicit position of this widget in the keyboard focus order
+//
+// Widgets with a positive tab index are visited first in ascending order, then
+// widgets with zero (default) tab index are visited in tree order. Widgets
+// with a negative tab index are skipped.
+func (w *WidgetImplement) SetTabIndex(index int) {
+	w.tabIndex = index
+}
+
+// TabStop() returns whether or not this widget can receive the focus by Tab / Shift+Tab
+func (w *WidgetImplement) TabStop() bool {
+	return w.tabStop
+}
+
+// SetTabStop() sets whether or not this widget can receive the focus by Tab / Shift+Tab
+func (w *WidgetImplement) SetTabStop(stop bool) {
+	w.tabStop = stop
+}
+
 // Tooltip() returns tooltip string
 func (w *WidgetImplement) Tooltip() string {
 	return w.tooltip
@@ -394,7 +499,10 @@ func (w *WidgetImplement) FontSize() int {
 
 // SetFontSize() set the font size of this widget
 func (w *WidgetImplement) SetFontSize(s int) {
-	w.fontSize = s
+	if w.fontSize != s {
+		w.fontSize = s
+		w.InvalidateLayout()
+	}
 }
 
 // HasFontSize() return whether the font size is explicitly specified for this widget
@@ -528,31 +636,93 @@ func (w *WidgetImplement) IMEStatusEvent(self Widget) bool {
 }
 
 // PreferredSize() computes the preferred size of the widget
-func (w *WidgetImplement) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+func (w *WidgetImplement) PreferredSize(self Widget, ctx DrawContext) (int, int) {
 	if w.layout != nil {
 		return w.layout.PreferredSize(self, ctx)
 	}
 	return w.w, w.h
 }
 
+// CachedPreferredSize() returns the result of PreferredSize() memoized until the layout is invalidated.
+// Widgets whose preferred size changes by other means than the setters should call InvalidateLayout()
+func (w *WidgetImplement) CachedPreferredSize(self Widget, ctx DrawContext) (int, int) {
+	if !w.preferredValid {
+		w.preferredW, w.preferredH = self.PreferredSize(self, ctx)
+		w.preferredValid = true
+	}
+	return w.preferredW, w.preferredH
+}
+
 // PerformLayout() invokes the associated layout generator to properly place child widgets, if any
-func (w *WidgetImplement) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+func (w *WidgetImplement) OnPerformLayout(self Widget, ctx DrawContext) {
 	if w.layout != nil {
 		w.layout.OnPerformLayout(self, ctx)
 	} else {
 		for _, child := range w.children {
-			prefW, prefH := child.PreferredSize(child, ctx)
-			fixW, fixH := child.FixedSize()
-			w := toI(fixW > 0, fixW, prefW)
-			h := toI(fixH > 0, fixH, prefH)
-			child.SetSize(w, h)
-			child.OnPerformLayout(child, ctx)
+			child.SetSize(LayoutSize(child, ctx))
+			child.LayoutIfNeeded(child, ctx)
+		}
+	}
+}
+
+// LayoutIfNeeded() calls OnPerformLayout() only when the layout was invalidated or the size
+// changed since the last layout. Layouts use it to skip the clean subtrees
+func (w *WidgetImplement) LayoutIfNeeded(self Widget, ctx DrawContext) {
+	if !w.layoutDirty && w.laidOutW == w.w && w.laidOutH == w.h {
+		return
+	}
+	w.layoutDirty = false
+	w.laidOutW, w.laidOutH = w.w, w.h
+	self.OnPerformLayout(self, ctx)
+}
+
+// InvalidateLayout() marks this widget and its ancestors as needing layout and drops their
+// cached preferred sizes. Setters which change the geometry call it
+func (w *WidgetImplement) InvalidateLayout() {
+	// the ancestors of a widget which is already invalid were marked with it
+	invalid := w.layoutDirty && !w.preferredValid
+	w.markLayoutDirty()
+	if w.parent != nil && !invalid {
+		w.parent.InvalidateLayout()
+	}
+}
+
+// markLayoutDirty() invalidates the layout of this widget only
+func (w *WidgetImplement) markLayoutDirty() {
+	w.layoutDirty = true
+	w.preferredValid = false
+	if cache, ok := w.layout.(layoutCache); ok {
+		cache.invalidate()
+	}
+}
+
+// NeedsLayout() returns whether or not the layout of this widget was invalidated
+func (w *WidgetImplement) NeedsLayout() bool {
+	return w.layoutDirty
+}
+
+// invalidateTree() invalidates the layout of the widget, its ancestors and all its descendants
+func invalidateTree(widget Widget) {
+	widget.InvalidateLayout()
+	invalidateDescendants(widget)
+}
+
+// invalidateDescendants() marks the descendants directly; their ancestors are invalid already
+func invalidateDescendants(widget Widget) {
+	for _, child := range widget.Children() {
+		if marker, ok := child.(interface {
+			markLayoutDirty()
+		}); ok {
+			marker.markLayoutDirty()
+		} else {
+			child.InvalidateLayout()
 		}
+		invalidateDescendants(child)
 	}
 }
 
 // Draw() draws the widget (and all child widgets)
-func (w *WidgetImplement) Draw(self Widget, ctx *nanovgo.Context) {
+func (w *WidgetImplement) Draw(self Widget, ctx DrawContext) {
 	if debugFlag {
 		ctx.SetStrokeWidth(1.0)
 		ctx.BeginPath()
@@ -0,0 +1,121 @@
+package nanogui
+
+import "testing"
+
+func TestInvalidateLayout(t *testing.T) {
+	screen, window := newTestWindow(t)
+	label := NewLabel(window, "hello")
+	screen.PerformLayout()
+	if screen.NeedsLayout() || window.NeedsLayout() || label.NeedsLayout() {
+		t.Fatal("PerformLayout() should clear the dirty flags")
+	}
+
+	label.SetCaption("hello world, a longer caption")
+	if !label.NeedsLayout() || !window.NeedsLayout() || !screen.NeedsLayout() {
+		t.Fatal("changing the caption should mark the label and its ancestors dirty")
+	}
+	screen.DrawAll()
+	if screen.NeedsLayout() || window.NeedsLayout() || label.NeedsLayout() {
+		t.Fatal("DrawAll() should lay out the dirty tree")
+	}
+
+	label.SetCaption("hello world, a longer caption")
+	if label.NeedsLayout() {
+		t.Error("setting the same caption shouldn't mark the label dirty")
+	}
+}
+
+// countingWidget counts the calls of PreferredSize() and OnPerformLayout()
+type countingWidget struct {
+	WidgetImplement
+	preferred, layouts int
+}
+
+func newCountingWidget(parent Widget) *countingWidget {
+	w := &countingWidget{}
+	InitWidget(w, parent)
+	return w
+}
+
+func (c *countingWidget) PreferredSize(self Widget, ctx DrawContext) (int, int) {
+	c.preferred++
+	return 30, 20
+}
+
+func (c *countingWidget) OnPerformLayout(self Widget, ctx DrawContext) {
+	c.layouts++
+	c.WidgetImplement.OnPerformLayout(self, ctx)
+}
+
+func TestLayoutSkipsCleanWidgets(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	window := NewWindow(screen, "Main")
+	window.SetLayout(NewBoxLayout(Vertical, Fill))
+	a := newCountingWidget(NewWidget(window))
+	b := newCountingWidget(NewWidget(window))
+	screen.PerformLayout()
+	if a.layouts == 0 || b.layouts == 0 {
+		t.Fatal("PerformLayout() should lay out every widget")
+	}
+
+	a.preferred, a.layouts, b.preferred, b.layouts = 0, 0, 0, 0
+	screen.DrawAll()
+	if a.preferred != 0 || b.preferred != 0 || a.layouts != 0 || b.layouts != 0 {
+		t.Fatalf("DrawAll() on a clean tree: PreferredSize() %d, %d times, OnPerformLayout() %d, %d times, want none",
+			a.preferred, b.preferred, a.layouts, b.layouts)
+	}
+	a.InvalidateLayout()
+	screen.DrawAll()
+	if a.preferred != 1 || a.layouts != 1 {
+		t.Errorf("invalidated widget: PreferredSize() %d times, OnPerformLayout() %d times, want once each", a.preferred, a.layouts)
+	}
+	if b.preferred != 0 || b.layouts != 0 {
+		t.Errorf("clean sibling: PreferredSize() %d times, OnPerformLayout() %d times, want none", b.preferred, b.layouts)
+	}
+}
+
+func TestInvalidateLayoutOfHiddenWidget(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	window := NewWindow(screen, "Main")
+	window.SetLayout(NewBoxLayout(Vertical, Fill))
+	hidden := newCountingWidget(window)
+	hidden.SetVisible(false)
+	screen.PerformLayout()
+	if !hidden.NeedsLayout() || window.NeedsLayout() {
+		t.Fatal("layouts should skip the hidden widget")
+	}
+	hidden.SetVisible(true)
+	if !window.NeedsLayout() || !screen.NeedsLayout() {
+		t.Error("showing a widget which wasn't laid out should invalidate its ancestors")
+	}
+}
+
+func TestLayoutChangesInvalidateOwner(t *testing.T) {
+	screen := NewHeadlessScreen(800, 600)
+	window := NewWindow(screen, "Main")
+	flex := NewFlexLayout(Horizontal, JustifyStart, Fill)
+	flexPanel := NewWidget(window)
+	flexPanel.SetLayout(flex)
+	item := NewWidget(flexPanel)
+	grid := NewAdvancedGridLayout([]int{0, 0}, []int{0})
+	gridPanel := NewWidget(window)
+	gridPanel.SetLayout(grid)
+	anchored := NewWidget(gridPanel)
+
+	changes := []struct {
+		name   string
+		owner  Widget
+		change func()
+	}{
+		{"SetLayout()", window, func() { window.SetLayout(NewGroupLayout()) }},
+		{"FlexLayout.SetItem()", flexPanel, func() { flex.SetItem(item, NewFlexItem(1, 1)) }},
+		{"AdvancedGridLayout.SetAnchor()", gridPanel, func() { grid.SetAnchor(anchored, NewAnchor(1, 0)) }},
+	}
+	for _, c := range changes {
+		screen.PerformLayout()
+		c.change()
+		if !c.owner.NeedsLayout() || !screen.NeedsLayout() {
+			t.Errorf("%s should invalidate the layout of its owner", c.name)
+		}
+	}
+}
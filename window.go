@@ -9,7 +9,7 @@ import (
 type Window struct {
 	WidgetImplement
 	title       string
-	buttonPanel Widget
+	buttonPanel *WidgetImplement
 	modal       bool
 	drag        bool
 	draggable   bool
@@ -41,7 +41,10 @@ func (w *Window) Title() string {
 
 // SetTitle() sets the window title
 func (w *Window) SetTitle(title string) {
-	w.title = title
+	if w.title != title {
+		w.title = title
+		w.InvalidateLayout()
+	}
 }
 
 // Modal() returns is this a model dialog?
@@ -64,7 +67,8 @@ func (w *Window) SetDraggable(flag bool) {
 
 func (w *Window) ButtonPanel() Widget {
 	if w.buttonPanel == nil {
-		w.buttonPanel = NewWidget(w)
+		w.buttonPanel = &WidgetImplement{}
+		InitWidget(w.buttonPanel, w)
 		w.buttonPanel.SetLayout(NewBoxLayout(Horizontal, Middle, 0, 4))
 	}
 	return w.buttonPanel
@@ -125,13 +129,14 @@ func (w *Window) ScrollEvent(self Widget, x, y, relX, relY int) bool {
 	return true
 }
 
-func (w *Window) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
+func (w *Window) PreferredSize(self Widget, ctx DrawContext) (int, int) {
+	// the button panel is hidden from the layout without invalidating it
 	if w.buttonPanel != nil {
-		w.buttonPanel.SetVisible(false)
+		w.buttonPanel.visible = false
 	}
 	width, height := w.WidgetImplement.PreferredSize(self, ctx)
 	if w.buttonPanel != nil {
-		w.buttonPanel.SetVisible(true)
+		w.buttonPanel.visible = true
 	}
 	ctx.SetFontSize(18.0)
 	ctx.SetFontFace(w.theme.FontBold)
@@ -140,25 +145,25 @@ func (w *Window) PreferredSize(self Widget, ctx *nanovgo.Context) (int, int) {
 	return maxI(width, int(bounds[2]-bounds[0])+20), maxI(height, int(bounds[3]-bounds[1]))
 }
 
-func (w *Window) OnPerformLayout(self Widget, ctx *nanovgo.Context) {
+func (w *Window) OnPerformLayout(self Widget, ctx DrawContext) {
 	if w.buttonPanel == nil {
 		w.WidgetImplement.OnPerformLayout(self, ctx)
 	} else {
-		w.buttonPanel.SetVisible(false)
+		w.buttonPanel.visible = false
 		w.WidgetImplement.OnPerformLayout(self, ctx)
 		for _, c := range w.buttonPanel.Children() {
 			c.SetFixedSize(22, 22)
 			c.SetFontSize(15)
 		}
-		w.buttonPanel.SetVisible(true)
+		w.buttonPanel.visible = true
 		w.buttonPanel.SetSize(w.Width(), 22)
 		panelW, _ := w.buttonPanel.PreferredSize(w.buttonPanel, ctx)
 		w.buttonPanel.SetPosition(w.Width()-(panelW+5), 3)
-		w.buttonPanel.OnPerformLayout(w.buttonPanel, ctx)
+		w.buttonPanel.LayoutIfNeeded(w.buttonPanel, ctx)
 	}
 }
 
-func (w *Window) Draw(self Widget, ctx *nanovgo.Context) {
+func (w *Window) Draw(self Widget, ctx DrawContext) {
 	ds := float32(w.theme.WindowDropShadowSize)
 	cr := float32(w.theme.WindowCornerRadius)
 	hh := float32(w.theme.WindowHeaderHeight)
@@ -0,0 +1,115 @@
+package nanogui
+
+import (
+	"unicode"
+)
+
+// wordClass classifies runes for word-wise cursor movement
+//
+// Runs of the same class form a word. CJK text has no spaces between words, so
+// each script (Han, Hiragana, Katakana, Hangul) is its own class: "日本語を入力"
+// is split into "日本語", "を" and "入力".
+type wordClass int
+
+const (
+	wordClassSpace wordClass = iota
+	wordClassPunct
+	wordClassWord
+	wordClassHan
+	wordClassHiragana
+	wordClassKatakana
+	wordClassHangul
+)
+
+func classifyRune(r rune) wordClass {
+	switch {
+	case unicode.IsSpace(r):
+		return wordClassSpace
+	case unicode.Is(unicode.Han, r):
+		return wordClassHan
+	case unicode.Is(unicode.Hiragana, r):
+		return wordClassHiragana
+	case unicode.Is(unicode.Katakana, r):
+		return wordClassKatakana
+	case unicode.Is(unicode.Hangul, r):
+		return wordClassHangul
+	case unicode.IsLetter(r), unicode.IsDigit(r), unicode.IsMark(r), r == '_':
+		return wordClassWord
+	}
+	return wordClassPunct
+}
+
+// isWordExtender() reports whether r continues the word before it (prolonged sound mark, iteration marks)
+func isWordExtender(r rune) bool {
+	switch r {
+	case 'ー', 'ｰ', '々', 'ゝ', 'ゞ', 'ヽ', 'ヾ', '〻':
+		return true
+	}
+	return false
+}
+
+// wordClasses() returns the class of each rune. Extenders take the class of the preceding rune
+func wordClasses(text []rune) []wordClass {
+	classes := make([]wordClass, len(text))
+	for i, r := range text {
+		if i > 0 && isWordExtender(r) && classes[i-1] != wordClassSpace {
+			classes[i] = classes[i-1]
+		} else {
+			classes[i] = classifyRune(r)
+		}
+	}
+	return classes
+}
+
+// previousWordBoundary() returns the start of the word before pos, skipping the spaces before it
+func previousWordBoundary(text []rune, pos int) int {
+	classes := wordClasses(text)
+	pos = clampI(pos, 0, len(text))
+	for pos > 0 && classes[pos-1] == wordClassSpace {
+		pos--
+	}
+	if pos > 0 {
+		class := classes[pos-1]
+		for pos > 0 && classes[pos-1] == class {
+			pos--
+		}
+	}
+	return pos
+}
+
+// nextWordBoundary() returns the start of the next word after pos, skipping the spaces after the current word
+func nextWordBoundary(text []rune, pos int) int {
+	classes := wordClasses(text)
+	pos = clampI(pos, 0, len(text))
+	if pos < len(text) && classes[pos] != wordClassSpace {
+		class := classes[pos]
+		for pos < len(text) && classes[pos] == class {
+			pos++
+		}
+	}
+	for pos < len(text) && classes[pos] == wordClassSpace {
+		pos++
+	}
+	return pos
+}
+
+// wordRangeAt() returns the word which contains pos. At the end of a word, the word before pos is used
+func wordRangeAt(text []rune, pos int) (int, int) {
+	if len(text) == 0 {
+		return 0, 0
+	}
+	classes := wordClasses(text)
+	pos = clampI(pos, 0, len(text))
+	if pos == len(text) || (pos > 0 && classes[pos] == wordClassSpace && classes[pos-1] != wordClassSpace) {
+		pos--
+	}
+	class := classes[pos]
+	begin, end := pos, pos+1
+	for begin > 0 && classes[begin-1] == class {
+		begin--
+	}
+	for end < len(text) && classes[end] == class {
+		end++
+	}
+	return begin, end
+}
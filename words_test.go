@@ -0,0 +1,95 @@
+package nanogui
+
+import (
+	"testing"
+
+	"github.com/shibukawa/glfw"
+)
+
+func TestWordBoundaries(t *testing.T) {
+	text := []rune("日本語を入力 hello, world  テスト")
+	var stops []int
+	for pos := 0; pos < len(text); {
+		pos = nextWordBoundary(text, pos)
+		stops = append(stops, pos)
+	}
+	// script changes split "日本語", "を" and "入力"
+	if len(stops) < 3 || stops[0] != 3 || stops[1] != 4 || stops[2] != 7 {
+		t.Errorf("nextWordBoundary() stops = %v, want it to start with [3 4 7]", stops)
+	}
+	if pos := previousWordBoundary(text, 7); pos != 4 {
+		t.Errorf("previousWordBoundary(7) = %d, want 4", pos)
+	}
+
+	cases := []struct {
+		text       string
+		pos        int
+		begin, end int
+	}{
+		{"ab cd", 3, 3, 5},
+		{"ab cd", 2, 0, 2},
+		{"コーヒー", 1, 0, 4},
+	}
+	for _, c := range cases {
+		if begin, end := wordRangeAt([]rune(c.text), c.pos); begin != c.begin || end != c.end {
+			t.Errorf("wordRangeAt(%q, %d) = %d, %d, want %d, %d", c.text, c.pos, begin, end, c.begin, c.end)
+		}
+	}
+}
+
+func TestTextBoxWordEditing(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := NewTextBox(window, "")
+	textBox.SetEditable(true)
+	textBox.SetAlignment(TextLeft)
+	textBox.SetFixedSize(300, 0)
+	screen.PerformLayout()
+	screen.DrawAll()
+
+	in := screen.Input()
+	in.PressKey(glfw.KeyTab, 0)
+	in.TypeText("foo bar baz")
+	in.PressKey(glfw.KeyLeft, glfw.ModControl)
+	if textBox.cursorPos != 8 {
+		t.Fatalf("Ctrl+Left: cursor %d, want 8", textBox.cursorPos)
+	}
+	in.PressKey(glfw.KeyLeft, glfw.ModControl|glfw.ModShift)
+	if textBox.cursorPos != 4 || textBox.selectionPos != 8 {
+		t.Fatalf("Ctrl+Shift+Left: cursor %d, selection %d, want 4, 8", textBox.cursorPos, textBox.selectionPos)
+	}
+	in.PressKey(glfw.KeyRight, glfw.ModAlt)
+	in.PressKey(glfw.KeyBackspace, glfw.ModControl)
+	if got := string(textBox.valueTemp); got != "foo baz" {
+		t.Fatalf("Ctrl+Backspace: %q, want %q", got, "foo baz")
+	}
+	in.PressKey(glfw.KeyHome, 0)
+	in.PressKey(glfw.KeyDelete, glfw.ModControl)
+	if got := string(textBox.valueTemp); got != "baz" {
+		t.Fatalf("Ctrl+Delete: %q, want %q", got, "baz")
+	}
+}
+
+func TestTextBoxMultiClickSelection(t *testing.T) {
+	screen, window := newTestWindow(t)
+	textBox := NewTextBox(window, "one two baz")
+	textBox.SetEditable(true)
+	textBox.SetAlignment(TextLeft)
+	textBox.SetFixedSize(300, 0)
+	screen.PerformLayout()
+	screen.DrawAll()
+
+	in := screen.Input()
+	x, y := textBox.AbsolutePosition()
+	in.ClickAt(x+12, y+5)
+	screen.DrawAll()
+	in.ClickAt(x+12, y+5)
+	screen.DrawAll()
+	if textBox.selectionPos != 0 || textBox.cursorPos != 3 {
+		t.Fatalf("double click: selection %d-%d, want 0-3", textBox.selectionPos, textBox.cursorPos)
+	}
+	in.ClickAt(x+12, y+5)
+	screen.DrawAll()
+	if textBox.selectionPos != 0 || textBox.cursorPos != len(textBox.valueTemp) {
+		t.Fatalf("triple click: selection %d-%d, want the whole text", textBox.selectionPos, textBox.cursorPos)
+	}
+}